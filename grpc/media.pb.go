@@ -72,22 +72,27 @@ type Media struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id            string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Attributes    *structpb.Struct `protobuf:"bytes,2,opt,name=attributes,proto3" json:"attributes,omitempty"`
-	Height        float64          `protobuf:"fixed64,3,opt,name=height,proto3" json:"height,omitempty"`
-	Width         float64          `protobuf:"fixed64,4,opt,name=width,proto3" json:"width,omitempty"`
-	X             float64          `protobuf:"fixed64,5,opt,name=x,proto3" json:"x,omitempty"`
-	Y             float64          `protobuf:"fixed64,6,opt,name=y,proto3" json:"y,omitempty"`
-	Url           string           `protobuf:"bytes,7,opt,name=url,proto3" json:"url,omitempty"`
-	Phash         string           `protobuf:"bytes,8,opt,name=phash,proto3" json:"phash,omitempty"`
-	Filename      string           `protobuf:"bytes,9,opt,name=filename,proto3" json:"filename,omitempty"`
-	MediaUrl      string           `protobuf:"bytes,10,opt,name=media_url,json=mediaUrl,proto3" json:"media_url,omitempty"`
-	ScreenshotUrl string           `protobuf:"bytes,11,opt,name=screenshot_url,json=screenshotUrl,proto3" json:"screenshot_url,omitempty"`
-	ResourceUrl   string           `protobuf:"bytes,12,opt,name=resource_url,json=resourceUrl,proto3" json:"resource_url,omitempty"`
-	TaskId        string           `protobuf:"bytes,13,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	CreatedAt     string           `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string           `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	DeletedAt     string           `protobuf:"bytes,16,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	Id             string           `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Attributes     *structpb.Struct `protobuf:"bytes,2,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	Height         float64          `protobuf:"fixed64,3,opt,name=height,proto3" json:"height,omitempty"`
+	Width          float64          `protobuf:"fixed64,4,opt,name=width,proto3" json:"width,omitempty"`
+	X              float64          `protobuf:"fixed64,5,opt,name=x,proto3" json:"x,omitempty"`
+	Y              float64          `protobuf:"fixed64,6,opt,name=y,proto3" json:"y,omitempty"`
+	Url            string           `protobuf:"bytes,7,opt,name=url,proto3" json:"url,omitempty"`
+	Phash          string           `protobuf:"bytes,8,opt,name=phash,proto3" json:"phash,omitempty"`
+	Filename       string           `protobuf:"bytes,9,opt,name=filename,proto3" json:"filename,omitempty"`
+	MediaUrl       string           `protobuf:"bytes,10,opt,name=media_url,json=mediaUrl,proto3" json:"media_url,omitempty"`
+	ScreenshotUrl  string           `protobuf:"bytes,11,opt,name=screenshot_url,json=screenshotUrl,proto3" json:"screenshot_url,omitempty"`
+	ResourceUrl    string           `protobuf:"bytes,12,opt,name=resource_url,json=resourceUrl,proto3" json:"resource_url,omitempty"`
+	TaskId         string           `protobuf:"bytes,13,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	CreatedAt      string           `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      string           `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	DeletedAt      string           `protobuf:"bytes,16,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	RunId          string           `protobuf:"bytes,17,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	Hashes         []*MediaHash     `protobuf:"bytes,19,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	ActionId       string           `protobuf:"bytes,20,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	VisualDiff     *VisualDiff      `protobuf:"bytes,21,opt,name=visual_diff,json=visualDiff,proto3" json:"visual_diff,omitempty"`
+	ResourceHashes []*MediaHash     `protobuf:"bytes,22,rep,name=resource_hashes,json=resourceHashes,proto3" json:"resource_hashes,omitempty"`
 }
 
 func (x *Media) Reset() {
@@ -234,6 +239,325 @@ func (x *Media) GetDeletedAt() string {
 	return ""
 }
 
+func (x *Media) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *Media) GetHashes() []*MediaHash {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+func (x *Media) GetActionId() string {
+	if x != nil {
+		return x.ActionId
+	}
+	return ""
+}
+
+func (x *Media) GetVisualDiff() *VisualDiff {
+	if x != nil {
+		return x.VisualDiff
+	}
+	return nil
+}
+
+func (x *Media) GetResourceHashes() []*MediaHash {
+	if x != nil {
+		return x.ResourceHashes
+	}
+	return nil
+}
+
+type VisualDiff struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BaselineMediaId string  `protobuf:"bytes,1,opt,name=baseline_media_id,json=baselineMediaId,proto3" json:"baseline_media_id,omitempty"`
+	Distance        int32   `protobuf:"varint,2,opt,name=distance,proto3" json:"distance,omitempty"`
+	DiffRatio       float64 `protobuf:"fixed64,3,opt,name=diff_ratio,json=diffRatio,proto3" json:"diff_ratio,omitempty"`
+	DiffUrl         string  `protobuf:"bytes,4,opt,name=diff_url,json=diffUrl,proto3" json:"diff_url,omitempty"`
+	Regressed       bool    `protobuf:"varint,5,opt,name=regressed,proto3" json:"regressed,omitempty"`
+	Error           string  `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *VisualDiff) Reset() {
+	*x = VisualDiff{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VisualDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VisualDiff) ProtoMessage() {}
+
+func (x *VisualDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VisualDiff.ProtoReflect.Descriptor instead.
+func (*VisualDiff) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VisualDiff) GetBaselineMediaId() string {
+	if x != nil {
+		return x.BaselineMediaId
+	}
+	return ""
+}
+
+func (x *VisualDiff) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *VisualDiff) GetDiffRatio() float64 {
+	if x != nil {
+		return x.DiffRatio
+	}
+	return 0
+}
+
+func (x *VisualDiff) GetDiffUrl() string {
+	if x != nil {
+		return x.DiffUrl
+	}
+	return ""
+}
+
+func (x *VisualDiff) GetRegressed() bool {
+	if x != nil {
+		return x.Regressed
+	}
+	return false
+}
+
+func (x *VisualDiff) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type Baseline struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TaskId     string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	ActionId   string `protobuf:"bytes,2,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	MediaId    string `protobuf:"bytes,3,opt,name=media_id,json=mediaId,proto3" json:"media_id,omitempty"`
+	Phash      string `protobuf:"bytes,4,opt,name=phash,proto3" json:"phash,omitempty"`
+	Sha256     string `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	MediaUrl   string `protobuf:"bytes,6,opt,name=media_url,json=mediaUrl,proto3" json:"media_url,omitempty"`
+	ApprovedAt string `protobuf:"bytes,7,opt,name=approved_at,json=approvedAt,proto3" json:"approved_at,omitempty"`
+}
+
+func (x *Baseline) Reset() {
+	*x = Baseline{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Baseline) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Baseline) ProtoMessage() {}
+
+func (x *Baseline) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Baseline.ProtoReflect.Descriptor instead.
+func (*Baseline) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Baseline) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *Baseline) GetActionId() string {
+	if x != nil {
+		return x.ActionId
+	}
+	return ""
+}
+
+func (x *Baseline) GetMediaId() string {
+	if x != nil {
+		return x.MediaId
+	}
+	return ""
+}
+
+func (x *Baseline) GetPhash() string {
+	if x != nil {
+		return x.Phash
+	}
+	return ""
+}
+
+func (x *Baseline) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *Baseline) GetMediaUrl() string {
+	if x != nil {
+		return x.MediaUrl
+	}
+	return ""
+}
+
+func (x *Baseline) GetApprovedAt() string {
+	if x != nil {
+		return x.ApprovedAt
+	}
+	return ""
+}
+
+type BaselineResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Baseline *Baseline `protobuf:"bytes,1,opt,name=baseline,proto3" json:"baseline,omitempty"`
+}
+
+func (x *BaselineResponse) Reset() {
+	*x = BaselineResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BaselineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BaselineResponse) ProtoMessage() {}
+
+func (x *BaselineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BaselineResponse.ProtoReflect.Descriptor instead.
+func (*BaselineResponse) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BaselineResponse) GetBaseline() *Baseline {
+	if x != nil {
+		return x.Baseline
+	}
+	return nil
+}
+
+type MediaHash struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Algorithm string `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	Value     string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *MediaHash) Reset() {
+	*x = MediaHash{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MediaHash) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MediaHash) ProtoMessage() {}
+
+func (x *MediaHash) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MediaHash.ProtoReflect.Descriptor instead.
+func (*MediaHash) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MediaHash) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *MediaHash) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
 type MediaIdParam struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -245,7 +569,7 @@ type MediaIdParam struct {
 func (x *MediaIdParam) Reset() {
 	*x = MediaIdParam{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[1]
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -258,7 +582,7 @@ func (x *MediaIdParam) String() string {
 func (*MediaIdParam) ProtoMessage() {}
 
 func (x *MediaIdParam) ProtoReflect() protoreflect.Message {
-	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[1]
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -271,7 +595,7 @@ func (x *MediaIdParam) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MediaIdParam.ProtoReflect.Descriptor instead.
 func (*MediaIdParam) Descriptor() ([]byte, []int) {
-	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{1}
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *MediaIdParam) GetId() string {
@@ -292,7 +616,7 @@ type MediaHashParam struct {
 func (x *MediaHashParam) Reset() {
 	*x = MediaHashParam{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[2]
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -302,61 +626,912 @@ func (x *MediaHashParam) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MediaHashParam) ProtoMessage() {}
+func (*MediaHashParam) ProtoMessage() {}
+
+func (x *MediaHashParam) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MediaHashParam.ProtoReflect.Descriptor instead.
+func (*MediaHashParam) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MediaHashParam) GetPhash() string {
+	if x != nil {
+		return x.Phash
+	}
+	return ""
+}
+
+type MediaFiltersParam struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash      *string     `protobuf:"bytes,1,opt,name=hash,proto3,oneof" json:"hash,omitempty"`
+	CreatedAt *string     `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3,oneof" json:"created_at,omitempty"`
+	TaskId    *string     `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3,oneof" json:"task_id,omitempty"`
+	Order     *MediaOrder `protobuf:"varint,4,opt,name=order,proto3,enum=grpc.MediaOrder,oneof" json:"order,omitempty"`
+	Limit     *int32      `protobuf:"varint,5,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+}
+
+func (x *MediaFiltersParam) Reset() {
+	*x = MediaFiltersParam{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MediaFiltersParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MediaFiltersParam) ProtoMessage() {}
+
+func (x *MediaFiltersParam) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MediaFiltersParam.ProtoReflect.Descriptor instead.
+func (*MediaFiltersParam) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MediaFiltersParam) GetHash() string {
+	if x != nil && x.Hash != nil {
+		return *x.Hash
+	}
+	return ""
+}
+
+func (x *MediaFiltersParam) GetCreatedAt() string {
+	if x != nil && x.CreatedAt != nil {
+		return *x.CreatedAt
+	}
+	return ""
+}
+
+func (x *MediaFiltersParam) GetTaskId() string {
+	if x != nil && x.TaskId != nil {
+		return *x.TaskId
+	}
+	return ""
+}
+
+func (x *MediaFiltersParam) GetOrder() MediaOrder {
+	if x != nil && x.Order != nil {
+		return *x.Order
+	}
+	return MediaOrder_MEDIA_ORDER_ASC
+}
+
+func (x *MediaFiltersParam) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+type SimilarMediaParam struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Algorithm   string `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	Hash        string `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	MaxDistance *int32 `protobuf:"varint,3,opt,name=max_distance,json=maxDistance,proto3,oneof" json:"max_distance,omitempty"`
+	Limit       *int32 `protobuf:"varint,4,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+}
+
+func (x *SimilarMediaParam) Reset() {
+	*x = SimilarMediaParam{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SimilarMediaParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarMediaParam) ProtoMessage() {}
+
+func (x *SimilarMediaParam) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarMediaParam.ProtoReflect.Descriptor instead.
+func (*SimilarMediaParam) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SimilarMediaParam) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *SimilarMediaParam) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *SimilarMediaParam) GetMaxDistance() int32 {
+	if x != nil && x.MaxDistance != nil {
+		return *x.MaxDistance
+	}
+	return 0
+}
+
+func (x *SimilarMediaParam) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+type MediaResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Media *Media `protobuf:"bytes,1,opt,name=media,proto3" json:"media,omitempty"`
+}
+
+func (x *MediaResponse) Reset() {
+	*x = MediaResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MediaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MediaResponse) ProtoMessage() {}
+
+func (x *MediaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MediaResponse.ProtoReflect.Descriptor instead.
+func (*MediaResponse) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MediaResponse) GetMedia() *Media {
+	if x != nil {
+		return x.Media
+	}
+	return nil
+}
+
+type MediaListResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Media []*Media `protobuf:"bytes,1,rep,name=media,proto3" json:"media,omitempty"`
+}
+
+func (x *MediaListResponse) Reset() {
+	*x = MediaListResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MediaListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MediaListResponse) ProtoMessage() {}
+
+func (x *MediaListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MediaListResponse.ProtoReflect.Descriptor instead.
+func (*MediaListResponse) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *MediaListResponse) GetMedia() []*Media {
+	if x != nil {
+		return x.Media
+	}
+	return nil
+}
+
+type SimilarMedia struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Media    *Media `protobuf:"bytes,1,opt,name=media,proto3" json:"media,omitempty"`
+	Distance int32  `protobuf:"varint,2,opt,name=distance,proto3" json:"distance,omitempty"`
+}
+
+func (x *SimilarMedia) Reset() {
+	*x = SimilarMedia{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SimilarMedia) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarMedia) ProtoMessage() {}
+
+func (x *SimilarMedia) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarMedia.ProtoReflect.Descriptor instead.
+func (*SimilarMedia) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SimilarMedia) GetMedia() *Media {
+	if x != nil {
+		return x.Media
+	}
+	return nil
+}
+
+func (x *SimilarMedia) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+type SimilarMediaListResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Media []*SimilarMedia `protobuf:"bytes,1,rep,name=media,proto3" json:"media,omitempty"`
+}
+
+func (x *SimilarMediaListResponse) Reset() {
+	*x = SimilarMediaListResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SimilarMediaListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarMediaListResponse) ProtoMessage() {}
+
+func (x *SimilarMediaListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarMediaListResponse.ProtoReflect.Descriptor instead.
+func (*SimilarMediaListResponse) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SimilarMediaListResponse) GetMedia() []*SimilarMedia {
+	if x != nil {
+		return x.Media
+	}
+	return nil
+}
+
+type TaskRun struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TaskId     string          `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Status     string          `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Error      string          `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	HarUrl     string          `protobuf:"bytes,5,opt,name=har_url,json=harUrl,proto3" json:"har_url,omitempty"`
+	StartedAt  string          `protobuf:"bytes,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt string          `protobuf:"bytes,7,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Events     []*TaskRunEvent `protobuf:"bytes,8,rep,name=events,proto3" json:"events,omitempty"`
+	Failure    *TaskRunFailure `protobuf:"bytes,9,opt,name=failure,proto3" json:"failure,omitempty"`
+	VideoUrl   string          `protobuf:"bytes,10,opt,name=video_url,json=videoUrl,proto3" json:"video_url,omitempty"`
+	Regressed  bool            `protobuf:"varint,11,opt,name=regressed,proto3" json:"regressed,omitempty"`
+}
+
+func (x *TaskRun) Reset() {
+	*x = TaskRun{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaskRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskRun) ProtoMessage() {}
+
+func (x *TaskRun) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskRun.ProtoReflect.Descriptor instead.
+func (*TaskRun) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TaskRun) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TaskRun) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskRun) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TaskRun) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TaskRun) GetHarUrl() string {
+	if x != nil {
+		return x.HarUrl
+	}
+	return ""
+}
+
+func (x *TaskRun) GetStartedAt() string {
+	if x != nil {
+		return x.StartedAt
+	}
+	return ""
+}
+
+func (x *TaskRun) GetFinishedAt() string {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return ""
+}
+
+func (x *TaskRun) GetEvents() []*TaskRunEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *TaskRun) GetFailure() *TaskRunFailure {
+	if x != nil {
+		return x.Failure
+	}
+	return nil
+}
+
+func (x *TaskRun) GetVideoUrl() string {
+	if x != nil {
+		return x.VideoUrl
+	}
+	return ""
+}
+
+func (x *TaskRun) GetRegressed() bool {
+	if x != nil {
+		return x.Regressed
+	}
+	return false
+}
+
+type TaskRunFailure struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ActionId      string `protobuf:"bytes,1,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	Url           string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	ScreenshotUrl string `protobuf:"bytes,3,opt,name=screenshot_url,json=screenshotUrl,proto3" json:"screenshot_url,omitempty"`
+	DomUrl        string `protobuf:"bytes,4,opt,name=dom_url,json=domUrl,proto3" json:"dom_url,omitempty"`
+}
+
+func (x *TaskRunFailure) Reset() {
+	*x = TaskRunFailure{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaskRunFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskRunFailure) ProtoMessage() {}
+
+func (x *TaskRunFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskRunFailure.ProtoReflect.Descriptor instead.
+func (*TaskRunFailure) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TaskRunFailure) GetActionId() string {
+	if x != nil {
+		return x.ActionId
+	}
+	return ""
+}
+
+func (x *TaskRunFailure) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *TaskRunFailure) GetScreenshotUrl() string {
+	if x != nil {
+		return x.ScreenshotUrl
+	}
+	return ""
+}
+
+func (x *TaskRunFailure) GetDomUrl() string {
+	if x != nil {
+		return x.DomUrl
+	}
+	return ""
+}
+
+type TaskRunEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind    string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Level   string `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Url     string `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	Time    string `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+}
+
+func (x *TaskRunEvent) Reset() {
+	*x = TaskRunEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaskRunEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskRunEvent) ProtoMessage() {}
+
+func (x *TaskRunEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskRunEvent.ProtoReflect.Descriptor instead.
+func (*TaskRunEvent) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *TaskRunEvent) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *TaskRunEvent) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *TaskRunEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TaskRunEvent) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *TaskRunEvent) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+type TaskRunIdParam struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *TaskRunIdParam) Reset() {
+	*x = TaskRunIdParam{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaskRunIdParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskRunIdParam) ProtoMessage() {}
+
+func (x *TaskRunIdParam) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskRunIdParam.ProtoReflect.Descriptor instead.
+func (*TaskRunIdParam) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TaskRunIdParam) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type TaskRunResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TaskRun *TaskRun `protobuf:"bytes,1,opt,name=task_run,json=taskRun,proto3" json:"task_run,omitempty"`
+}
+
+func (x *TaskRunResponse) Reset() {
+	*x = TaskRunResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaskRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskRunResponse) ProtoMessage() {}
+
+func (x *TaskRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskRunResponse.ProtoReflect.Descriptor instead.
+func (*TaskRunResponse) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TaskRunResponse) GetTaskRun() *TaskRun {
+	if x != nil {
+		return x.TaskRun
+	}
+	return nil
+}
+
+type ContentChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TaskId          string `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	ActionId        string `protobuf:"bytes,3,opt,name=action_id,json=actionId,proto3" json:"action_id,omitempty"`
+	RunId           string `protobuf:"bytes,4,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	PreviousRunId   string `protobuf:"bytes,5,opt,name=previous_run_id,json=previousRunId,proto3" json:"previous_run_id,omitempty"`
+	Kind            string `protobuf:"bytes,6,opt,name=kind,proto3" json:"kind,omitempty"`
+	Previous        string `protobuf:"bytes,7,opt,name=previous,proto3" json:"previous,omitempty"`
+	Current         string `protobuf:"bytes,8,opt,name=current,proto3" json:"current,omitempty"`
+	MediaId         string `protobuf:"bytes,9,opt,name=media_id,json=mediaId,proto3" json:"media_id,omitempty"`
+	PreviousMediaId string `protobuf:"bytes,10,opt,name=previous_media_id,json=previousMediaId,proto3" json:"previous_media_id,omitempty"`
+	Distance        int32  `protobuf:"varint,11,opt,name=distance,proto3" json:"distance,omitempty"`
+	DetectedAt      string `protobuf:"bytes,12,opt,name=detected_at,json=detectedAt,proto3" json:"detected_at,omitempty"`
+}
+
+func (x *ContentChange) Reset() {
+	*x = ContentChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ContentChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContentChange) ProtoMessage() {}
+
+func (x *ContentChange) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContentChange.ProtoReflect.Descriptor instead.
+func (*ContentChange) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ContentChange) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ContentChange) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ContentChange) GetActionId() string {
+	if x != nil {
+		return x.ActionId
+	}
+	return ""
+}
+
+func (x *ContentChange) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *ContentChange) GetPreviousRunId() string {
+	if x != nil {
+		return x.PreviousRunId
+	}
+	return ""
+}
+
+func (x *ContentChange) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ContentChange) GetPrevious() string {
+	if x != nil {
+		return x.Previous
+	}
+	return ""
+}
+
+func (x *ContentChange) GetCurrent() string {
+	if x != nil {
+		return x.Current
+	}
+	return ""
+}
+
+func (x *ContentChange) GetMediaId() string {
+	if x != nil {
+		return x.MediaId
+	}
+	return ""
+}
 
-func (x *MediaHashParam) ProtoReflect() protoreflect.Message {
-	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *ContentChange) GetPreviousMediaId() string {
+	if x != nil {
+		return x.PreviousMediaId
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use MediaHashParam.ProtoReflect.Descriptor instead.
-func (*MediaHashParam) Descriptor() ([]byte, []int) {
-	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{2}
+func (x *ContentChange) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
 }
 
-func (x *MediaHashParam) GetPhash() string {
+func (x *ContentChange) GetDetectedAt() string {
 	if x != nil {
-		return x.Phash
+		return x.DetectedAt
 	}
 	return ""
 }
 
-type MediaFiltersParam struct {
+type ContentChangeFiltersParam struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Hash      *string     `protobuf:"bytes,1,opt,name=hash,proto3,oneof" json:"hash,omitempty"`
-	CreatedAt *string     `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3,oneof" json:"created_at,omitempty"`
-	TaskId    *string     `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3,oneof" json:"task_id,omitempty"`
-	Order     *MediaOrder `protobuf:"varint,4,opt,name=order,proto3,enum=grpc.MediaOrder,oneof" json:"order,omitempty"`
-	Limit     *int32      `protobuf:"varint,5,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+	TaskId   *string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3,oneof" json:"task_id,omitempty"`
+	ActionId *string `protobuf:"bytes,2,opt,name=action_id,json=actionId,proto3,oneof" json:"action_id,omitempty"`
+	Since    *string `protobuf:"bytes,3,opt,name=since,proto3,oneof" json:"since,omitempty"`
+	Limit    *int32  `protobuf:"varint,4,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
 }
 
-func (x *MediaFiltersParam) Reset() {
-	*x = MediaFiltersParam{}
+func (x *ContentChangeFiltersParam) Reset() {
+	*x = ContentChangeFiltersParam{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[3]
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MediaFiltersParam) String() string {
+func (x *ContentChangeFiltersParam) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MediaFiltersParam) ProtoMessage() {}
+func (*ContentChangeFiltersParam) ProtoMessage() {}
 
-func (x *MediaFiltersParam) ProtoReflect() protoreflect.Message {
-	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[3]
+func (x *ContentChangeFiltersParam) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -367,71 +1542,64 @@ func (x *MediaFiltersParam) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MediaFiltersParam.ProtoReflect.Descriptor instead.
-func (*MediaFiltersParam) Descriptor() ([]byte, []int) {
-	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ContentChangeFiltersParam.ProtoReflect.Descriptor instead.
+func (*ContentChangeFiltersParam) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *MediaFiltersParam) GetHash() string {
-	if x != nil && x.Hash != nil {
-		return *x.Hash
+func (x *ContentChangeFiltersParam) GetTaskId() string {
+	if x != nil && x.TaskId != nil {
+		return *x.TaskId
 	}
 	return ""
 }
 
-func (x *MediaFiltersParam) GetCreatedAt() string {
-	if x != nil && x.CreatedAt != nil {
-		return *x.CreatedAt
+func (x *ContentChangeFiltersParam) GetActionId() string {
+	if x != nil && x.ActionId != nil {
+		return *x.ActionId
 	}
 	return ""
 }
 
-func (x *MediaFiltersParam) GetTaskId() string {
-	if x != nil && x.TaskId != nil {
-		return *x.TaskId
+func (x *ContentChangeFiltersParam) GetSince() string {
+	if x != nil && x.Since != nil {
+		return *x.Since
 	}
 	return ""
 }
 
-func (x *MediaFiltersParam) GetOrder() MediaOrder {
-	if x != nil && x.Order != nil {
-		return *x.Order
-	}
-	return MediaOrder_MEDIA_ORDER_ASC
-}
-
-func (x *MediaFiltersParam) GetLimit() int32 {
+func (x *ContentChangeFiltersParam) GetLimit() int32 {
 	if x != nil && x.Limit != nil {
 		return *x.Limit
 	}
 	return 0
 }
 
-type MediaResponse struct {
+type ContentChangeListResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Media *Media `protobuf:"bytes,1,opt,name=media,proto3" json:"media,omitempty"`
+	Changes []*ContentChange `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
 }
 
-func (x *MediaResponse) Reset() {
-	*x = MediaResponse{}
+func (x *ContentChangeListResponse) Reset() {
+	*x = ContentChangeListResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[4]
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MediaResponse) String() string {
+func (x *ContentChangeListResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MediaResponse) ProtoMessage() {}
+func (*ContentChangeListResponse) ProtoMessage() {}
 
-func (x *MediaResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[4]
+func (x *ContentChangeListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -442,43 +1610,43 @@ func (x *MediaResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MediaResponse.ProtoReflect.Descriptor instead.
-func (*MediaResponse) Descriptor() ([]byte, []int) {
-	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ContentChangeListResponse.ProtoReflect.Descriptor instead.
+func (*ContentChangeListResponse) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *MediaResponse) GetMedia() *Media {
+func (x *ContentChangeListResponse) GetChanges() []*ContentChange {
 	if x != nil {
-		return x.Media
+		return x.Changes
 	}
 	return nil
 }
 
-type MediaListResponse struct {
+type FileChunk struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Media []*Media `protobuf:"bytes,1,rep,name=media,proto3" json:"media,omitempty"`
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
 }
 
-func (x *MediaListResponse) Reset() {
-	*x = MediaListResponse{}
+func (x *FileChunk) Reset() {
+	*x = FileChunk{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[5]
+		mi := &file_adapters_controllers_grpc_media_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MediaListResponse) String() string {
+func (x *FileChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MediaListResponse) ProtoMessage() {}
+func (*FileChunk) ProtoMessage() {}
 
-func (x *MediaListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[5]
+func (x *FileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_adapters_controllers_grpc_media_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -489,14 +1657,14 @@ func (x *MediaListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MediaListResponse.ProtoReflect.Descriptor instead.
-func (*MediaListResponse) Descriptor() ([]byte, []int) {
-	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use FileChunk.ProtoReflect.Descriptor instead.
+func (*FileChunk) Descriptor() ([]byte, []int) {
+	return file_adapters_controllers_grpc_media_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *MediaListResponse) GetMedia() []*Media {
+func (x *FileChunk) GetData() []byte {
 	if x != nil {
-		return x.Media
+		return x.Data
 	}
 	return nil
 }
@@ -508,7 +1676,7 @@ var file_adapters_controllers_grpc_media_proto_rawDesc = []byte{
 	0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x73, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x6d, 0x65, 0x64, 0x69,
 	0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x67, 0x72, 0x70, 0x63, 0x1a, 0x1c, 0x67,
 	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73,
-	0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbb, 0x03, 0x0a, 0x05,
+	0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8b, 0x05, 0x0a, 0x05,
 	0x4d, 0x65, 0x64, 0x69, 0x61, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x37, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75,
 	0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
@@ -536,7 +1704,53 @@ var file_adapters_controllers_grpc_media_proto_rawDesc = []byte{
 	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65,
 	0x6c, 0x65, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
-	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x1e, 0x0a, 0x0c, 0x4d, 0x65, 0x64,
+	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x72, 0x75, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x75, 0x6e, 0x49, 0x64,
+	0x12, 0x27, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x48, 0x61, 0x73,
+	0x68, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x14, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x31, 0x0a, 0x0b, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c,
+	0x5f, 0x64, 0x69, 0x66, 0x66, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x56, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x44, 0x69, 0x66, 0x66, 0x52, 0x0a, 0x76,
+	0x69, 0x73, 0x75, 0x61, 0x6c, 0x44, 0x69, 0x66, 0x66, 0x12, 0x38, 0x0a, 0x0f, 0x72, 0x65, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x16, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x48,
+	0x61, 0x73, 0x68, 0x52, 0x0e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x48, 0x61, 0x73,
+	0x68, 0x65, 0x73, 0x4a, 0x04, 0x08, 0x12, 0x10, 0x13, 0x22, 0xc2, 0x01, 0x0a, 0x0a, 0x56, 0x69,
+	0x73, 0x75, 0x61, 0x6c, 0x44, 0x69, 0x66, 0x66, 0x12, 0x2a, 0x0a, 0x11, 0x62, 0x61, 0x73, 0x65,
+	0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x62, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x4d, 0x65, 0x64,
+	0x69, 0x61, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x69, 0x66, 0x66, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x64, 0x69, 0x66, 0x66, 0x52, 0x61, 0x74, 0x69, 0x6f, 0x12,
+	0x19, 0x0a, 0x08, 0x64, 0x69, 0x66, 0x66, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x64, 0x69, 0x66, 0x66, 0x55, 0x72, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65,
+	0x67, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x72,
+	0x65, 0x67, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xc7,
+	0x01, 0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x74,
+	0x61, 0x73, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61,
+	0x73, 0x6b, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x64, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x70, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x68, 0x61,
+	0x73, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65,
+	0x64, 0x69, 0x61, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d,
+	0x65, 0x64, 0x69, 0x61, 0x55, 0x72, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x70, 0x70, 0x72, 0x6f,
+	0x76, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70,
+	0x70, 0x72, 0x6f, 0x76, 0x65, 0x64, 0x41, 0x74, 0x22, 0x3e, 0x0a, 0x10, 0x42, 0x61, 0x73, 0x65,
+	0x6c, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x08,
+	0x62, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x52, 0x08,
+	0x62, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0x3f, 0x0a, 0x09, 0x4d, 0x65, 0x64, 0x69,
+	0x61, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74,
+	0x68, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69,
+	0x74, 0x68, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x1e, 0x0a, 0x0c, 0x4d, 0x65, 0x64,
 	0x69, 0x61, 0x49, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x26, 0x0a, 0x0e, 0x4d, 0x65, 0x64,
 	0x69, 0x61, 0x48, 0x61, 0x73, 0x68, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x70,
@@ -556,31 +1770,161 @@ var file_adapters_controllers_grpc_media_proto_rawDesc = []byte{
 	0x61, 0x73, 0x68, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f,
 	0x61, 0x74, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x69, 0x64, 0x42, 0x08,
 	0x0a, 0x06, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c, 0x69, 0x6d,
-	0x69, 0x74, 0x22, 0x32, 0x0a, 0x0d, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x52,
-	0x05, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x22, 0x36, 0x0a, 0x11, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x4c,
-	0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x6d,
-	0x65, 0x64, 0x69, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x67, 0x72, 0x70,
-	0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x52, 0x05, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x2a, 0x37,
-	0x0a, 0x0a, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x13, 0x0a, 0x0f,
-	0x4d, 0x45, 0x44, 0x49, 0x41, 0x5f, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x5f, 0x41, 0x53, 0x43, 0x10,
-	0x00, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x45, 0x44, 0x49, 0x41, 0x5f, 0x4f, 0x52, 0x44, 0x45, 0x52,
-	0x5f, 0x44, 0x45, 0x53, 0x43, 0x10, 0x01, 0x32, 0xcc, 0x01, 0x0a, 0x0c, 0x4d, 0x65, 0x64, 0x69,
-	0x61, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4d,
-	0x65, 0x64, 0x69, 0x61, 0x42, 0x79, 0x49, 0x64, 0x12, 0x12, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
-	0x4d, 0x65, 0x64, 0x69, 0x61, 0x49, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x13, 0x2e, 0x67,
-	0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x42,
-	0x79, 0x48, 0x61, 0x73, 0x68, 0x12, 0x14, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64,
-	0x69, 0x61, 0x48, 0x61, 0x73, 0x68, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x13, 0x2e, 0x67, 0x72,
-	0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x12, 0x42, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x4c, 0x69,
-	0x73, 0x74, 0x12, 0x17, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x46,
-	0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x17, 0x2e, 0x67, 0x72,
-	0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x07, 0x5a, 0x05, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x69, 0x74, 0x22, 0xa3, 0x01, 0x0a, 0x11, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x4d, 0x65,
+	0x64, 0x69, 0x61, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x6c, 0x67, 0x6f,
+	0x72, 0x69, 0x74, 0x68, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x6c, 0x67,
+	0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x26, 0x0a, 0x0c, 0x6d, 0x61,
+	0x78, 0x5f, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x00, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x44, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x01, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a,
+	0x0d, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x42, 0x08,
+	0x0a, 0x06, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x32, 0x0a, 0x0d, 0x4d, 0x65, 0x64, 0x69,
+	0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x6d, 0x65, 0x64,
+	0x69, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x4d, 0x65, 0x64, 0x69, 0x61, 0x52, 0x05, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x22, 0x36, 0x0a, 0x11,
+	0x4d, 0x65, 0x64, 0x69, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x21, 0x0a, 0x05, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x52, 0x05, 0x6d,
+	0x65, 0x64, 0x69, 0x61, 0x22, 0x4d, 0x0a, 0x0c, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x4d,
+	0x65, 0x64, 0x69, 0x61, 0x12, 0x21, 0x0a, 0x05, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61,
+	0x52, 0x05, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x22, 0x44, 0x0a, 0x18, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x4d, 0x65,
+	0x64, 0x69, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x28, 0x0a, 0x05, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x4d, 0x65, 0x64,
+	0x69, 0x61, 0x52, 0x05, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x22, 0xd0, 0x02, 0x0a, 0x07, 0x54, 0x61,
+	0x73, 0x6b, 0x52, 0x75, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x73, 0x6b, 0x49, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x17, 0x0a, 0x07,
+	0x68, 0x61, 0x72, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x68,
+	0x61, 0x72, 0x55, 0x72, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x69, 0x6e, 0x69, 0x73,
+	0x68, 0x65, 0x64, 0x41, 0x74, 0x12, 0x2a, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18,
+	0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x61, 0x73,
+	0x6b, 0x52, 0x75, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x12, 0x2e, 0x0a, 0x07, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x75,
+	0x6e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x07, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x12, 0x1b, 0x0a, 0x09, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x76, 0x69, 0x64, 0x65, 0x6f, 0x55, 0x72, 0x6c, 0x12, 0x1c,
+	0x0a, 0x09, 0x72, 0x65, 0x67, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x09, 0x72, 0x65, 0x67, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x22, 0x7f, 0x0a, 0x0e,
+	0x54, 0x61, 0x73, 0x6b, 0x52, 0x75, 0x6e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12, 0x1b,
+	0x0a, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75,
+	0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x25, 0x0a,
+	0x0e, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x73, 0x68, 0x6f, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x73, 0x68, 0x6f,
+	0x74, 0x55, 0x72, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x6f, 0x6d, 0x5f, 0x75, 0x72, 0x6c, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x55, 0x72, 0x6c, 0x22, 0x78, 0x0a,
+	0x0c, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x75, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x75, 0x72, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x22, 0x20, 0x0a, 0x0e, 0x54, 0x61, 0x73, 0x6b, 0x52,
+	0x75, 0x6e, 0x49, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x3b, 0x0a, 0x0f, 0x54, 0x61, 0x73,
+	0x6b, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x08,
+	0x74, 0x61, 0x73, 0x6b, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x75, 0x6e, 0x52, 0x07, 0x74,
+	0x61, 0x73, 0x6b, 0x52, 0x75, 0x6e, 0x22, 0xe2, 0x02, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x61, 0x73, 0x6b,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61, 0x73, 0x6b, 0x49,
+	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x15,
+	0x0a, 0x06, 0x72, 0x75, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x72, 0x75, 0x6e, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75,
+	0x73, 0x5f, 0x72, 0x75, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x52, 0x75, 0x6e, 0x49, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x65, 0x64, 0x69, 0x61,
+	0x5f, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x64, 0x69, 0x61,
+	0x49, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x5f, 0x6d,
+	0x65, 0x64, 0x69, 0x61, 0x5f, 0x69, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70,
+	0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x49, 0x64, 0x12, 0x1a,
+	0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x65,
+	0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x64, 0x65, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0xbf, 0x01, 0x0a, 0x19,
+	0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x46, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x73, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x1c, 0x0a, 0x07, 0x74, 0x61, 0x73,
+	0x6b, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x74, 0x61,
+	0x73, 0x6b, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x08, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x73, 0x69, 0x6e,
+	0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x88, 0x01, 0x01, 0x42,
+	0x0a, 0x0a, 0x08, 0x5f, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x69, 0x64, 0x42, 0x0c, 0x0a, 0x0a, 0x5f,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x69,
+	0x6e, 0x63, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x4a, 0x0a,
+	0x19, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x4c, 0x69,
+	0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22, 0x1f, 0x0a, 0x09, 0x46, 0x69, 0x6c,
+	0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x2a, 0x37, 0x0a, 0x0a, 0x4d, 0x65,
+	0x64, 0x69, 0x61, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x13, 0x0a, 0x0f, 0x4d, 0x45, 0x44, 0x49,
+	0x41, 0x5f, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x5f, 0x41, 0x53, 0x43, 0x10, 0x00, 0x12, 0x14, 0x0a,
+	0x10, 0x4d, 0x45, 0x44, 0x49, 0x41, 0x5f, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x5f, 0x44, 0x45, 0x53,
+	0x43, 0x10, 0x01, 0x32, 0xf9, 0x04, 0x0a, 0x0c, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x64, 0x69, 0x61,
+	0x42, 0x79, 0x49, 0x64, 0x12, 0x12, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69,
+	0x61, 0x49, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x13, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x4d, 0x65, 0x64, 0x69, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x3d, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x42, 0x79, 0x48, 0x61, 0x73,
+	0x68, 0x12, 0x14, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x48, 0x61,
+	0x73, 0x68, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x13, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d,
+	0x65, 0x64, 0x69, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x42,
+	0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x17,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x46, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x73, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x17, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d,
+	0x65, 0x64, 0x69, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x50, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72,
+	0x4d, 0x65, 0x64, 0x69, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x17, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x1a, 0x1e, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61,
+	0x72, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x0f, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x42,
+	0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x12, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d,
+	0x65, 0x64, 0x69, 0x61, 0x49, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x16, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x42, 0x61, 0x73, 0x65, 0x6c, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3b, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x54, 0x61, 0x73, 0x6b,
+	0x52, 0x75, 0x6e, 0x12, 0x14, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x52,
+	0x75, 0x6e, 0x49, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x15, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x3f, 0x0a, 0x12, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x61,
+	0x73, 0x6b, 0x52, 0x75, 0x6e, 0x48, 0x61, 0x72, 0x12, 0x14, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x54, 0x61, 0x73, 0x6b, 0x52, 0x75, 0x6e, 0x49, 0x64, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x0f,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x41, 0x0a, 0x14, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x54,
+	0x61, 0x73, 0x6b, 0x52, 0x75, 0x6e, 0x56, 0x69, 0x64, 0x65, 0x6f, 0x12, 0x14, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x75, 0x6e, 0x49, 0x64, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x1a, 0x0f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x22, 0x00, 0x30, 0x01, 0x12, 0x57, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x1f, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x1f, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42,
+	0x07, 0x5a, 0x05, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -596,33 +1940,71 @@ func file_adapters_controllers_grpc_media_proto_rawDescGZIP() []byte {
 }
 
 var file_adapters_controllers_grpc_media_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_adapters_controllers_grpc_media_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_adapters_controllers_grpc_media_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_adapters_controllers_grpc_media_proto_goTypes = []interface{}{
-	(MediaOrder)(0),           // 0: grpc.MediaOrder
-	(*Media)(nil),             // 1: grpc.Media
-	(*MediaIdParam)(nil),      // 2: grpc.MediaIdParam
-	(*MediaHashParam)(nil),    // 3: grpc.MediaHashParam
-	(*MediaFiltersParam)(nil), // 4: grpc.MediaFiltersParam
-	(*MediaResponse)(nil),     // 5: grpc.MediaResponse
-	(*MediaListResponse)(nil), // 6: grpc.MediaListResponse
-	(*structpb.Struct)(nil),   // 7: google.protobuf.Struct
+	(MediaOrder)(0),                   // 0: grpc.MediaOrder
+	(*Media)(nil),                     // 1: grpc.Media
+	(*VisualDiff)(nil),                // 2: grpc.VisualDiff
+	(*Baseline)(nil),                  // 3: grpc.Baseline
+	(*BaselineResponse)(nil),          // 4: grpc.BaselineResponse
+	(*MediaHash)(nil),                 // 5: grpc.MediaHash
+	(*MediaIdParam)(nil),              // 6: grpc.MediaIdParam
+	(*MediaHashParam)(nil),            // 7: grpc.MediaHashParam
+	(*MediaFiltersParam)(nil),         // 8: grpc.MediaFiltersParam
+	(*SimilarMediaParam)(nil),         // 9: grpc.SimilarMediaParam
+	(*MediaResponse)(nil),             // 10: grpc.MediaResponse
+	(*MediaListResponse)(nil),         // 11: grpc.MediaListResponse
+	(*SimilarMedia)(nil),              // 12: grpc.SimilarMedia
+	(*SimilarMediaListResponse)(nil),  // 13: grpc.SimilarMediaListResponse
+	(*TaskRun)(nil),                   // 14: grpc.TaskRun
+	(*TaskRunFailure)(nil),            // 15: grpc.TaskRunFailure
+	(*TaskRunEvent)(nil),              // 16: grpc.TaskRunEvent
+	(*TaskRunIdParam)(nil),            // 17: grpc.TaskRunIdParam
+	(*TaskRunResponse)(nil),           // 18: grpc.TaskRunResponse
+	(*ContentChange)(nil),             // 19: grpc.ContentChange
+	(*ContentChangeFiltersParam)(nil), // 20: grpc.ContentChangeFiltersParam
+	(*ContentChangeListResponse)(nil), // 21: grpc.ContentChangeListResponse
+	(*FileChunk)(nil),                 // 22: grpc.FileChunk
+	(*structpb.Struct)(nil),           // 23: google.protobuf.Struct
 }
 var file_adapters_controllers_grpc_media_proto_depIdxs = []int32{
-	7, // 0: grpc.Media.attributes:type_name -> google.protobuf.Struct
-	0, // 1: grpc.MediaFiltersParam.order:type_name -> grpc.MediaOrder
-	1, // 2: grpc.MediaResponse.media:type_name -> grpc.Media
-	1, // 3: grpc.MediaListResponse.media:type_name -> grpc.Media
-	2, // 4: grpc.MediaService.GetMediaById:input_type -> grpc.MediaIdParam
-	3, // 5: grpc.MediaService.GetMediaByHash:input_type -> grpc.MediaHashParam
-	4, // 6: grpc.MediaService.GetMediaList:input_type -> grpc.MediaFiltersParam
-	5, // 7: grpc.MediaService.GetMediaById:output_type -> grpc.MediaResponse
-	5, // 8: grpc.MediaService.GetMediaByHash:output_type -> grpc.MediaResponse
-	6, // 9: grpc.MediaService.GetMediaList:output_type -> grpc.MediaListResponse
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	23, // 0: grpc.Media.attributes:type_name -> google.protobuf.Struct
+	5,  // 1: grpc.Media.hashes:type_name -> grpc.MediaHash
+	2,  // 2: grpc.Media.visual_diff:type_name -> grpc.VisualDiff
+	5,  // 3: grpc.Media.resource_hashes:type_name -> grpc.MediaHash
+	3,  // 4: grpc.BaselineResponse.baseline:type_name -> grpc.Baseline
+	0,  // 5: grpc.MediaFiltersParam.order:type_name -> grpc.MediaOrder
+	1,  // 6: grpc.MediaResponse.media:type_name -> grpc.Media
+	1,  // 7: grpc.MediaListResponse.media:type_name -> grpc.Media
+	1,  // 8: grpc.SimilarMedia.media:type_name -> grpc.Media
+	12, // 9: grpc.SimilarMediaListResponse.media:type_name -> grpc.SimilarMedia
+	16, // 10: grpc.TaskRun.events:type_name -> grpc.TaskRunEvent
+	15, // 11: grpc.TaskRun.failure:type_name -> grpc.TaskRunFailure
+	14, // 12: grpc.TaskRunResponse.task_run:type_name -> grpc.TaskRun
+	19, // 13: grpc.ContentChangeListResponse.changes:type_name -> grpc.ContentChange
+	6,  // 14: grpc.MediaService.GetMediaById:input_type -> grpc.MediaIdParam
+	7,  // 15: grpc.MediaService.GetMediaByHash:input_type -> grpc.MediaHashParam
+	8,  // 16: grpc.MediaService.GetMediaList:input_type -> grpc.MediaFiltersParam
+	9,  // 17: grpc.MediaService.GetSimilarMediaList:input_type -> grpc.SimilarMediaParam
+	6,  // 18: grpc.MediaService.ApproveBaseline:input_type -> grpc.MediaIdParam
+	17, // 19: grpc.MediaService.GetTaskRun:input_type -> grpc.TaskRunIdParam
+	17, // 20: grpc.MediaService.DownloadTaskRunHar:input_type -> grpc.TaskRunIdParam
+	17, // 21: grpc.MediaService.DownloadTaskRunVideo:input_type -> grpc.TaskRunIdParam
+	20, // 22: grpc.MediaService.GetContentChanges:input_type -> grpc.ContentChangeFiltersParam
+	10, // 23: grpc.MediaService.GetMediaById:output_type -> grpc.MediaResponse
+	10, // 24: grpc.MediaService.GetMediaByHash:output_type -> grpc.MediaResponse
+	11, // 25: grpc.MediaService.GetMediaList:output_type -> grpc.MediaListResponse
+	13, // 26: grpc.MediaService.GetSimilarMediaList:output_type -> grpc.SimilarMediaListResponse
+	4,  // 27: grpc.MediaService.ApproveBaseline:output_type -> grpc.BaselineResponse
+	18, // 28: grpc.MediaService.GetTaskRun:output_type -> grpc.TaskRunResponse
+	22, // 29: grpc.MediaService.DownloadTaskRunHar:output_type -> grpc.FileChunk
+	22, // 30: grpc.MediaService.DownloadTaskRunVideo:output_type -> grpc.FileChunk
+	21, // 31: grpc.MediaService.GetContentChanges:output_type -> grpc.ContentChangeListResponse
+	23, // [23:32] is the sub-list for method output_type
+	14, // [14:23] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_adapters_controllers_grpc_media_proto_init() }
@@ -644,7 +2026,7 @@ func file_adapters_controllers_grpc_media_proto_init() {
 			}
 		}
 		file_adapters_controllers_grpc_media_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MediaIdParam); i {
+			switch v := v.(*VisualDiff); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -656,7 +2038,7 @@ func file_adapters_controllers_grpc_media_proto_init() {
 			}
 		}
 		file_adapters_controllers_grpc_media_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MediaHashParam); i {
+			switch v := v.(*Baseline); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -668,7 +2050,7 @@ func file_adapters_controllers_grpc_media_proto_init() {
 			}
 		}
 		file_adapters_controllers_grpc_media_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MediaFiltersParam); i {
+			switch v := v.(*BaselineResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -680,7 +2062,7 @@ func file_adapters_controllers_grpc_media_proto_init() {
 			}
 		}
 		file_adapters_controllers_grpc_media_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MediaResponse); i {
+			switch v := v.(*MediaHash); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -692,6 +2074,66 @@ func file_adapters_controllers_grpc_media_proto_init() {
 			}
 		}
 		file_adapters_controllers_grpc_media_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaIdParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaHashParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaFiltersParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SimilarMediaParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*MediaListResponse); i {
 			case 0:
 				return &v.state
@@ -703,15 +2145,149 @@ func file_adapters_controllers_grpc_media_proto_init() {
 				return nil
 			}
 		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SimilarMedia); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SimilarMediaListResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TaskRun); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TaskRunFailure); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TaskRunEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TaskRunIdParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TaskRunResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContentChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContentChangeFiltersParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContentChangeListResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_adapters_controllers_grpc_media_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_adapters_controllers_grpc_media_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_adapters_controllers_grpc_media_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	file_adapters_controllers_grpc_media_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	file_adapters_controllers_grpc_media_proto_msgTypes[19].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_adapters_controllers_grpc_media_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   6,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,6 +25,12 @@ type MediaServiceClient interface {
 	GetMediaById(ctx context.Context, in *MediaIdParam, opts ...grpc.CallOption) (*MediaResponse, error)
 	GetMediaByHash(ctx context.Context, in *MediaHashParam, opts ...grpc.CallOption) (*MediaResponse, error)
 	GetMediaList(ctx context.Context, in *MediaFiltersParam, opts ...grpc.CallOption) (*MediaListResponse, error)
+	GetSimilarMediaList(ctx context.Context, in *SimilarMediaParam, opts ...grpc.CallOption) (*SimilarMediaListResponse, error)
+	ApproveBaseline(ctx context.Context, in *MediaIdParam, opts ...grpc.CallOption) (*BaselineResponse, error)
+	GetTaskRun(ctx context.Context, in *TaskRunIdParam, opts ...grpc.CallOption) (*TaskRunResponse, error)
+	DownloadTaskRunHar(ctx context.Context, in *TaskRunIdParam, opts ...grpc.CallOption) (MediaService_DownloadTaskRunHarClient, error)
+	DownloadTaskRunVideo(ctx context.Context, in *TaskRunIdParam, opts ...grpc.CallOption) (MediaService_DownloadTaskRunVideoClient, error)
+	GetContentChanges(ctx context.Context, in *ContentChangeFiltersParam, opts ...grpc.CallOption) (*ContentChangeListResponse, error)
 }
 
 type mediaServiceClient struct {
@@ -62,6 +68,106 @@ func (c *mediaServiceClient) GetMediaList(ctx context.Context, in *MediaFiltersP
 	return out, nil
 }
 
+func (c *mediaServiceClient) GetSimilarMediaList(ctx context.Context, in *SimilarMediaParam, opts ...grpc.CallOption) (*SimilarMediaListResponse, error) {
+	out := new(SimilarMediaListResponse)
+	err := c.cc.Invoke(ctx, "/grpc.MediaService/GetSimilarMediaList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) ApproveBaseline(ctx context.Context, in *MediaIdParam, opts ...grpc.CallOption) (*BaselineResponse, error) {
+	out := new(BaselineResponse)
+	err := c.cc.Invoke(ctx, "/grpc.MediaService/ApproveBaseline", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) GetTaskRun(ctx context.Context, in *TaskRunIdParam, opts ...grpc.CallOption) (*TaskRunResponse, error) {
+	out := new(TaskRunResponse)
+	err := c.cc.Invoke(ctx, "/grpc.MediaService/GetTaskRun", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) DownloadTaskRunHar(ctx context.Context, in *TaskRunIdParam, opts ...grpc.CallOption) (MediaService_DownloadTaskRunHarClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MediaService_ServiceDesc.Streams[0], "/grpc.MediaService/DownloadTaskRunHar", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mediaServiceDownloadTaskRunHarClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MediaService_DownloadTaskRunHarClient interface {
+	Recv() (*FileChunk, error)
+	grpc.ClientStream
+}
+
+type mediaServiceDownloadTaskRunHarClient struct {
+	grpc.ClientStream
+}
+
+func (x *mediaServiceDownloadTaskRunHarClient) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mediaServiceClient) DownloadTaskRunVideo(ctx context.Context, in *TaskRunIdParam, opts ...grpc.CallOption) (MediaService_DownloadTaskRunVideoClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MediaService_ServiceDesc.Streams[1], "/grpc.MediaService/DownloadTaskRunVideo", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mediaServiceDownloadTaskRunVideoClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MediaService_DownloadTaskRunVideoClient interface {
+	Recv() (*FileChunk, error)
+	grpc.ClientStream
+}
+
+type mediaServiceDownloadTaskRunVideoClient struct {
+	grpc.ClientStream
+}
+
+func (x *mediaServiceDownloadTaskRunVideoClient) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mediaServiceClient) GetContentChanges(ctx context.Context, in *ContentChangeFiltersParam, opts ...grpc.CallOption) (*ContentChangeListResponse, error) {
+	out := new(ContentChangeListResponse)
+	err := c.cc.Invoke(ctx, "/grpc.MediaService/GetContentChanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MediaServiceServer is the server API for MediaService service.
 // All implementations must embed UnimplementedMediaServiceServer
 // for forward compatibility
@@ -69,6 +175,12 @@ type MediaServiceServer interface {
 	GetMediaById(context.Context, *MediaIdParam) (*MediaResponse, error)
 	GetMediaByHash(context.Context, *MediaHashParam) (*MediaResponse, error)
 	GetMediaList(context.Context, *MediaFiltersParam) (*MediaListResponse, error)
+	GetSimilarMediaList(context.Context, *SimilarMediaParam) (*SimilarMediaListResponse, error)
+	ApproveBaseline(context.Context, *MediaIdParam) (*BaselineResponse, error)
+	GetTaskRun(context.Context, *TaskRunIdParam) (*TaskRunResponse, error)
+	DownloadTaskRunHar(*TaskRunIdParam, MediaService_DownloadTaskRunHarServer) error
+	DownloadTaskRunVideo(*TaskRunIdParam, MediaService_DownloadTaskRunVideoServer) error
+	GetContentChanges(context.Context, *ContentChangeFiltersParam) (*ContentChangeListResponse, error)
 	mustEmbedUnimplementedMediaServiceServer()
 }
 
@@ -85,6 +197,24 @@ func (UnimplementedMediaServiceServer) GetMediaByHash(context.Context, *MediaHas
 func (UnimplementedMediaServiceServer) GetMediaList(context.Context, *MediaFiltersParam) (*MediaListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMediaList not implemented")
 }
+func (UnimplementedMediaServiceServer) GetSimilarMediaList(context.Context, *SimilarMediaParam) (*SimilarMediaListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSimilarMediaList not implemented")
+}
+func (UnimplementedMediaServiceServer) ApproveBaseline(context.Context, *MediaIdParam) (*BaselineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveBaseline not implemented")
+}
+func (UnimplementedMediaServiceServer) GetTaskRun(context.Context, *TaskRunIdParam) (*TaskRunResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTaskRun not implemented")
+}
+func (UnimplementedMediaServiceServer) DownloadTaskRunHar(*TaskRunIdParam, MediaService_DownloadTaskRunHarServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadTaskRunHar not implemented")
+}
+func (UnimplementedMediaServiceServer) DownloadTaskRunVideo(*TaskRunIdParam, MediaService_DownloadTaskRunVideoServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadTaskRunVideo not implemented")
+}
+func (UnimplementedMediaServiceServer) GetContentChanges(context.Context, *ContentChangeFiltersParam) (*ContentChangeListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetContentChanges not implemented")
+}
 func (UnimplementedMediaServiceServer) mustEmbedUnimplementedMediaServiceServer() {}
 
 // UnsafeMediaServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -152,6 +282,120 @@ func _MediaService_GetMediaList_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MediaService_GetSimilarMediaList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimilarMediaParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).GetSimilarMediaList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.MediaService/GetSimilarMediaList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).GetSimilarMediaList(ctx, req.(*SimilarMediaParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_ApproveBaseline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MediaIdParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).ApproveBaseline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.MediaService/ApproveBaseline",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).ApproveBaseline(ctx, req.(*MediaIdParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_GetTaskRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskRunIdParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).GetTaskRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.MediaService/GetTaskRun",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).GetTaskRun(ctx, req.(*TaskRunIdParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_DownloadTaskRunHar_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TaskRunIdParam)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MediaServiceServer).DownloadTaskRunHar(m, &mediaServiceDownloadTaskRunHarServer{stream})
+}
+
+type MediaService_DownloadTaskRunHarServer interface {
+	Send(*FileChunk) error
+	grpc.ServerStream
+}
+
+type mediaServiceDownloadTaskRunHarServer struct {
+	grpc.ServerStream
+}
+
+func (x *mediaServiceDownloadTaskRunHarServer) Send(m *FileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MediaService_DownloadTaskRunVideo_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TaskRunIdParam)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MediaServiceServer).DownloadTaskRunVideo(m, &mediaServiceDownloadTaskRunVideoServer{stream})
+}
+
+type MediaService_DownloadTaskRunVideoServer interface {
+	Send(*FileChunk) error
+	grpc.ServerStream
+}
+
+type mediaServiceDownloadTaskRunVideoServer struct {
+	grpc.ServerStream
+}
+
+func (x *mediaServiceDownloadTaskRunVideoServer) Send(m *FileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MediaService_GetContentChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContentChangeFiltersParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).GetContentChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.MediaService/GetContentChanges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).GetContentChanges(ctx, req.(*ContentChangeFiltersParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MediaService_ServiceDesc is the grpc.ServiceDesc for MediaService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -171,7 +415,34 @@ var MediaService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMediaList",
 			Handler:    _MediaService_GetMediaList_Handler,
 		},
+		{
+			MethodName: "GetSimilarMediaList",
+			Handler:    _MediaService_GetSimilarMediaList_Handler,
+		},
+		{
+			MethodName: "ApproveBaseline",
+			Handler:    _MediaService_ApproveBaseline_Handler,
+		},
+		{
+			MethodName: "GetTaskRun",
+			Handler:    _MediaService_GetTaskRun_Handler,
+		},
+		{
+			MethodName: "GetContentChanges",
+			Handler:    _MediaService_GetContentChanges_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DownloadTaskRunHar",
+			Handler:       _MediaService_DownloadTaskRunHar_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DownloadTaskRunVideo",
+			Handler:       _MediaService_DownloadTaskRunVideo_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "adapters/controllers/grpc/media.proto",
 }
@@ -3,8 +3,10 @@ package consumer
 import (
 	"automator-go/robot/adapters/controllers/tasks"
 	adapterConsumer "automator-go/robot/adapters/gateways/consumer"
+	"automator-go/robot/entities/models"
 	"automator-go/robot/usecases/consumer"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"os"
 )
 
 type FileConsumerController struct {
@@ -21,7 +23,8 @@ func NewFileConsumerController(
 
 func (f FileConsumerController) ConsumeTasks() []error {
 	f.logger.Info("starting consumer")
-	consumerHandler := adapterConsumer.NewTaskQueueConsumerFromJSONFile(f.taskController, f.logger)
+	scriptPolicy := models.NewScriptPolicy(os.Getenv("SCRIPTS_ALLOWED_SOURCES"))
+	consumerHandler := adapterConsumer.NewTaskQueueConsumerFromJSONFile(f.taskController, scriptPolicy, f.logger)
 	consumerUseCase := consumer.NewTaskQueueConsumer(consumerHandler)
 
 	return consumerUseCase.StartConsumer()
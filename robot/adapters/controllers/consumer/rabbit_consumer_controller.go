@@ -3,6 +3,7 @@ package consumer
 import (
 	"automator-go/robot/adapters/controllers/tasks"
 	adapterConsumer "automator-go/robot/adapters/gateways/consumer"
+	"automator-go/robot/entities/models"
 	"automator-go/robot/usecases/consumer"
 	"automator-go/utils"
 	"context"
@@ -48,6 +49,7 @@ func (r RabbitConsumerController) ConsumeTasks() []error {
 	consumerHandler := adapterConsumer.NewRabbitTaskQueueConsumer(
 		c.Channel,
 		r.taskController,
+		models.NewScriptPolicy(os.Getenv("SCRIPTS_ALLOWED_SOURCES")),
 		r.logger,
 		r.ctx,
 		queueName,
@@ -2,25 +2,55 @@ package grpc
 
 import (
 	"automator-go/grpc"
+	"automator-go/robot/entities/models"
 	"automator-go/robot/usecases/task"
 	"context"
 	"fmt"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
 	"time"
 )
 
+const (
+	// fileChunkSize is the size of the chunks the files are streamed in.
+	fileChunkSize = 64 * 1024
+	// defaultMaxHashDistance is the hamming distance under which perceptual
+	// hashes are taken for the same image when the request has none.
+	defaultMaxHashDistance = 10
+	// defaultSimilarMediaLimit limits the similar medias when the request
+	// has no limit.
+	defaultSimilarMediaLimit = 20
+)
+
 type grpcServer struct {
 	grpc.UnimplementedMediaServiceServer
 
-	dbRepo task.CapturedMediaRepository
-	logger *otelzap.Logger
+	dbRepo       task.CapturedMediaRepository
+	taskRunRepo  task.TaskRunRepository
+	baselineRepo task.BaselineRepository
+	changeRepo   task.ContentChangeRepository
+	storage      task.StorageMediaAdapter
+	logger       *otelzap.Logger
 }
 
-func NewGrpcServer(dbRepo task.CapturedMediaRepository, logger *otelzap.Logger) grpc.MediaServiceServer {
+func NewGrpcServer(
+	dbRepo task.CapturedMediaRepository,
+	taskRunRepo task.TaskRunRepository,
+	baselineRepo task.BaselineRepository,
+	changeRepo task.ContentChangeRepository,
+	storage task.StorageMediaAdapter,
+	logger *otelzap.Logger,
+) grpc.MediaServiceServer {
 	return &grpcServer{
-		dbRepo: dbRepo,
-		logger: logger,
+		dbRepo:       dbRepo,
+		taskRunRepo:  taskRunRepo,
+		baselineRepo: baselineRepo,
+		changeRepo:   changeRepo,
+		storage:      storage,
+		logger:       logger,
 	}
 }
 
@@ -87,3 +117,185 @@ func (g *grpcServer) GetMediaList(ctx context.Context, param *grpc.MediaFiltersP
 		Media: medias,
 	}, nil
 }
+
+func (g *grpcServer) GetSimilarMediaList(ctx context.Context, param *grpc.SimilarMediaParam) (*grpc.SimilarMediaListResponse, error) {
+	g.logger.Ctx(ctx).Debug("GetSimilarMediaList", zap.Any("param", param))
+	hash, err := models.ParseMediaHash(param.GetAlgorithm(), param.GetHash())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	query := task.SimilarMediaQuery{
+		Hash:        hash,
+		MaxDistance: defaultMaxHashDistance,
+		Limit:       defaultSimilarMediaLimit,
+	}
+	if param.MaxDistance != nil {
+		query.MaxDistance = int(param.GetMaxDistance())
+	}
+	if param.Limit != nil {
+		query.Limit = int(param.GetLimit())
+	}
+
+	similarMedias, err := g.dbRepo.GetSimilarMedias(query, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	medias := make([]*grpc.SimilarMedia, 0, len(similarMedias))
+	for _, similarMedia := range similarMedias {
+		media, err := MapMediaModelToMediaRPC(similarMedia.Media)
+		if err != nil {
+			return nil, err
+		}
+		medias = append(medias, &grpc.SimilarMedia{
+			Media:    media,
+			Distance: int32(similarMedia.Distance),
+		})
+	}
+
+	return &grpc.SimilarMediaListResponse{
+		Media: medias,
+	}, nil
+}
+
+// ApproveBaseline makes the media the baseline of the action that captured
+// it, the next captures of the action are compared with it.
+func (g *grpcServer) ApproveBaseline(ctx context.Context, param *grpc.MediaIdParam) (*grpc.BaselineResponse, error) {
+	g.logger.Ctx(ctx).Debug("ApproveBaseline", zap.String("id", param.GetId()))
+	mediaModel, err := g.dbRepo.GetMedia(param.GetId(), ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline, err := models.NewBaseline(mediaModel)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "media %s can't be a baseline: %v", mediaModel.Id, err)
+	}
+	approvedAt := time.Now()
+	baseline.ApprovedAt = &approvedAt
+	if err = g.baselineRepo.SaveBaseline(baseline, ctx); err != nil {
+		return nil, err
+	}
+
+	return &grpc.BaselineResponse{
+		Baseline: MapBaselineModelToRPC(baseline),
+	}, nil
+}
+
+func (g *grpcServer) getTaskRun(ctx context.Context, id string) (*models.TaskRun, error) {
+	run, err := g.taskRunRepo.GetTaskRun(id, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, status.Errorf(codes.NotFound, "task run %s not found", id)
+	}
+
+	return run, nil
+}
+
+func (g *grpcServer) GetTaskRun(ctx context.Context, param *grpc.TaskRunIdParam) (*grpc.TaskRunResponse, error) {
+	g.logger.Ctx(ctx).Debug("GetTaskRun", zap.String("id", param.GetId()))
+	run, err := g.getTaskRun(ctx, param.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &grpc.TaskRunResponse{
+		TaskRun: MapTaskRunModelToRPC(run),
+	}, nil
+}
+
+func (g *grpcServer) DownloadTaskRunHar(param *grpc.TaskRunIdParam, stream grpc.MediaService_DownloadTaskRunHarServer) error {
+	ctx := stream.Context()
+	g.logger.Ctx(ctx).Debug("DownloadTaskRunHar", zap.String("id", param.GetId()))
+	run, err := g.getTaskRun(ctx, param.GetId())
+	if err != nil {
+		return err
+	}
+	if run.HarUrl == "" {
+		return status.Errorf(codes.NotFound, "task run %s has no har", run.Id)
+	}
+
+	return g.streamFile(ctx, run.HarUrl, stream)
+}
+
+func (g *grpcServer) DownloadTaskRunVideo(param *grpc.TaskRunIdParam, stream grpc.MediaService_DownloadTaskRunVideoServer) error {
+	ctx := stream.Context()
+	g.logger.Ctx(ctx).Debug("DownloadTaskRunVideo", zap.String("id", param.GetId()))
+	run, err := g.getTaskRun(ctx, param.GetId())
+	if err != nil {
+		return err
+	}
+	if run.VideoUrl == "" {
+		return status.Errorf(codes.NotFound, "task run %s has no video", run.Id)
+	}
+
+	return g.streamFile(ctx, run.VideoUrl, stream)
+}
+
+// GetContentChanges returns the log of the changes of the contents of the
+// task actions, the latest first.
+func (g *grpcServer) GetContentChanges(ctx context.Context, param *grpc.ContentChangeFiltersParam) (*grpc.ContentChangeListResponse, error) {
+	g.logger.Ctx(ctx).Debug("GetContentChanges", zap.Any("param", param))
+	filter := task.ContentChangeFilter{
+		TaskId:   param.TaskId,
+		ActionId: param.ActionId,
+		Limit:    param.Limit,
+	}
+	if param.Since != nil {
+		since, err := time.Parse(time.RFC3339, param.GetSince())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "error parsing time: %v", err)
+		}
+		filter.Since = &since
+	}
+
+	changesModel, err := g.changeRepo.GetChanges(&filter, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*grpc.ContentChange, 0, len(changesModel))
+	for _, changeModel := range changesModel {
+		changes = append(changes, MapContentChangeModelToRPC(changeModel))
+	}
+
+	return &grpc.ContentChangeListResponse{
+		Changes: changes,
+	}, nil
+}
+
+// fileStream is the server side of the rpcs downloading files.
+type fileStream interface {
+	Send(chunk *grpc.FileChunk) error
+}
+
+func (g *grpcServer) streamFile(ctx context.Context, url string, stream fileStream) error {
+	file, err := g.storage.Open(url)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			g.logger.Ctx(ctx).Error("Error closing file", zap.Error(err))
+		}
+	}()
+
+	buffer := make([]byte, fileChunkSize)
+	for {
+		n, err := file.Read(buffer)
+		if n > 0 {
+			if sendErr := stream.Send(&grpc.FileChunk{Data: buffer[:n]}); sendErr != nil {
+				return fmt.Errorf("error sending file chunk: %w", sendErr)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading file: %w", err)
+		}
+	}
+}
@@ -10,25 +10,54 @@ import (
 func MapMediaModelToMediaRPC(mediaModel *models.Media) (*grpc.Media, error) {
 	attributes, err := structpb.NewStruct(mediaModel.Attributes)
 
+	var visualDiff *grpc.VisualDiff
+	if mediaModel.VisualDiff != nil {
+		visualDiff = &grpc.VisualDiff{
+			BaselineMediaId: mediaModel.VisualDiff.BaselineMediaId,
+			Distance:        int32(mediaModel.VisualDiff.Distance),
+			DiffRatio:       mediaModel.VisualDiff.DiffRatio,
+			DiffUrl:         mediaModel.VisualDiff.DiffUrl,
+			Regressed:       mediaModel.VisualDiff.Regressed,
+			Error:           mediaModel.VisualDiff.Error,
+		}
+	}
+
 	return &grpc.Media{
-		Id:            mediaModel.Id,
-		Attributes:    attributes,
-		Height:        mediaModel.Height,
-		Width:         mediaModel.Width,
-		X:             mediaModel.X,
-		Y:             mediaModel.Y,
-		Url:           mediaModel.Url,
-		Phash:         mediaModel.PHash,
-		Filename:      mediaModel.Filename,
-		MediaUrl:      mediaModel.MediaUrl,
-		ScreenshotUrl: mediaModel.ScreenshotUrl,
-		ResourceUrl:   mediaModel.ResourceUrl,
-		TaskId:        mediaModel.TaskId,
-		CreatedAt:     mediaModel.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:     mediaModel.UpdatedAt.Format(time.RFC3339),
+		Id:             mediaModel.Id,
+		Attributes:     attributes,
+		Height:         mediaModel.Height,
+		Width:          mediaModel.Width,
+		X:              mediaModel.X,
+		Y:              mediaModel.Y,
+		Url:            mediaModel.Url,
+		Phash:          mediaModel.PHash,
+		ResourceHashes: mapHashesToRPC(mediaModel.ResourceHashes),
+		Hashes:         mapHashesToRPC(mediaModel.Hashes),
+		Filename:       mediaModel.Filename,
+		MediaUrl:       mediaModel.MediaUrl,
+		ScreenshotUrl:  mediaModel.ScreenshotUrl,
+		ResourceUrl:    mediaModel.ResourceUrl,
+		TaskId:         mediaModel.TaskId,
+		ActionId:       mediaModel.ActionId,
+		RunId:          mediaModel.RunId,
+		VisualDiff:     visualDiff,
+		CreatedAt:      mediaModel.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      mediaModel.UpdatedAt.Format(time.RFC3339),
 	}, err
 }
 
+func mapHashesToRPC(hashesModel []models.MediaHash) []*grpc.MediaHash {
+	hashes := make([]*grpc.MediaHash, 0, len(hashesModel))
+	for _, hash := range hashesModel {
+		hashes = append(hashes, &grpc.MediaHash{
+			Algorithm: string(hash.Algorithm),
+			Value:     hash.Value,
+		})
+	}
+
+	return hashes
+}
+
 func MapMediaModelToRPC(mediaModel *models.Media) (*grpc.MediaResponse, error) {
 	media, err := MapMediaModelToMediaRPC(mediaModel)
 
@@ -36,3 +65,74 @@ func MapMediaModelToRPC(mediaModel *models.Media) (*grpc.MediaResponse, error) {
 		Media: media,
 	}, err
 }
+
+func MapTaskRunModelToRPC(runModel *models.TaskRun) *grpc.TaskRun {
+	events := make([]*grpc.TaskRunEvent, 0, len(runModel.Events))
+	for _, event := range runModel.Events {
+		events = append(events, &grpc.TaskRunEvent{
+			Kind:    string(event.Kind),
+			Level:   event.Level,
+			Message: event.Message,
+			Url:     event.Url,
+			Time:    event.Time.Format(time.RFC3339Nano),
+		})
+	}
+
+	var failure *grpc.TaskRunFailure
+	if runModel.Failure != nil {
+		failure = &grpc.TaskRunFailure{
+			ActionId:      runModel.Failure.ActionId,
+			Url:           runModel.Failure.Url,
+			ScreenshotUrl: runModel.Failure.ScreenshotUrl,
+			DomUrl:        runModel.Failure.DomUrl,
+		}
+	}
+
+	return &grpc.TaskRun{
+		Id:         runModel.Id,
+		TaskId:     runModel.TaskId,
+		Status:     string(runModel.Status),
+		Error:      runModel.Error,
+		HarUrl:     runModel.HarUrl,
+		VideoUrl:   runModel.VideoUrl,
+		StartedAt:  runModel.StartedAt.Format(time.RFC3339),
+		FinishedAt: runModel.FinishedAt.Format(time.RFC3339),
+		Events:     events,
+		Failure:    failure,
+		Regressed:  runModel.Regressed,
+	}
+}
+
+func MapBaselineModelToRPC(baselineModel *models.Baseline) *grpc.Baseline {
+	var approvedAt string
+	if baselineModel.ApprovedAt != nil {
+		approvedAt = baselineModel.ApprovedAt.Format(time.RFC3339)
+	}
+
+	return &grpc.Baseline{
+		TaskId:     baselineModel.TaskId,
+		ActionId:   baselineModel.ActionId,
+		MediaId:    baselineModel.MediaId,
+		Phash:      baselineModel.PHash,
+		Sha256:     baselineModel.Sha256,
+		MediaUrl:   baselineModel.MediaUrl,
+		ApprovedAt: approvedAt,
+	}
+}
+
+func MapContentChangeModelToRPC(changeModel *models.ContentChange) *grpc.ContentChange {
+	return &grpc.ContentChange{
+		Id:              changeModel.Id,
+		TaskId:          changeModel.TaskId,
+		ActionId:        changeModel.ActionId,
+		RunId:           changeModel.RunId,
+		PreviousRunId:   changeModel.PreviousRunId,
+		Kind:            string(changeModel.Kind),
+		Previous:        changeModel.Previous,
+		Current:         changeModel.Current,
+		MediaId:         changeModel.MediaId,
+		PreviousMediaId: changeModel.PreviousMediaId,
+		Distance:        int32(changeModel.Distance),
+		DetectedAt:      changeModel.DetectedAt.Format(time.RFC3339),
+	}
+}
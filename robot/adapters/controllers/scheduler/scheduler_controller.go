@@ -0,0 +1,118 @@
+package scheduler
+
+import (
+	"automator-go/robot/adapters/gateways/publisher"
+	bunRepo "automator-go/robot/adapters/repositories/bun"
+	"automator-go/robot/usecases/scheduler"
+	"automator-go/utils"
+	"context"
+	"fmt"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// leaderLockName names the advisory lock of the schedulers, only the one
+	// holding it publishes the tasks.
+	leaderLockName = "robot.scheduler"
+	// defaultTickInterval is how often the schedules are checked.
+	defaultTickInterval = 15 * time.Second
+	// defaultMissedRunTolerance is how late a run can be published before it
+	// is taken as missed.
+	defaultMissedRunTolerance = time.Minute
+)
+
+func durationEnv(name string, defaultValue time.Duration) (time.Duration, error) {
+	durationEnv := strings.TrimSpace(os.Getenv(name))
+	if durationEnv == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(durationEnv)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("error parsing %s env: %q is not a positive duration", name, durationEnv)
+	}
+
+	return duration, nil
+}
+
+type SchedulerController struct {
+	db     *bun.DB
+	logger *otelzap.LoggerWithCtx
+	ctx    context.Context
+}
+
+func NewSchedulerController(db *bun.DB, logger *otelzap.LoggerWithCtx, ctx context.Context) SchedulerController {
+	return SchedulerController{db: db, logger: logger, ctx: ctx}
+}
+
+// Start publishes the due tasks on every tick until the context is done. The
+// tasks are published with SCHEDULER_ROUTING_KEY, or with the binding key the
+// robots consume from when it is not set.
+func (s SchedulerController) Start() error {
+	tickInterval, err := durationEnv("SCHEDULER_TICK_INTERVAL", defaultTickInterval)
+	if err != nil {
+		return err
+	}
+	tolerance, err := durationEnv("SCHEDULER_MISSED_RUN_TOLERANCE", defaultMissedRunTolerance)
+	if err != nil {
+		return err
+	}
+	routingKey := strings.TrimSpace(os.Getenv("SCHEDULER_ROUTING_KEY"))
+	if routingKey == "" {
+		routingKey = os.Getenv("RABBITMQ_BINDING_KEY")
+	}
+	if routingKey == "" {
+		return fmt.Errorf("SCHEDULER_ROUTING_KEY or RABBITMQ_BINDING_KEY is required")
+	}
+
+	connectionName := os.Getenv("RABBITMQ_CONNECTION_NAME")
+	if connectionName == "" {
+		connectionName = "robot"
+	}
+	client, err := utils.StartPublisher(s.logger, connectionName+"-scheduler")
+	if err != nil {
+		return fmt.Errorf("error starting task publisher: %w", err)
+	}
+	defer func() {
+		if err := client.Shutdown(); err != nil {
+			s.logger.Error("Error shutting down task publisher", zap.Error(err))
+		}
+	}()
+
+	taskScheduler := scheduler.NewScheduler(
+		bunRepo.NewBunSchedule(s.db),
+		publisher.NewRabbitTaskPublisher(client.Channel, client.Exchange, routingKey, s.logger),
+		bunRepo.NewBunLeaderLock(s.db, leaderLockName),
+		tolerance,
+	)
+	defer func() {
+		// The context is done, the lock is released with a fresh one.
+		if err := taskScheduler.Stop(context.Background()); err != nil {
+			s.logger.Error("Error resigning the scheduler lead", zap.Error(err))
+		}
+	}()
+
+	s.logger.Info("[*] Scheduling tasks. To exit press CTRL+C", zap.Duration("tick", tickInterval))
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	for {
+		published, err := taskScheduler.Tick(time.Now(), s.ctx)
+		if err != nil {
+			s.logger.Error("Error scheduling tasks", zap.Error(err))
+		}
+		if published > 0 {
+			s.logger.Debug("Published scheduled tasks", zap.Int("published", published))
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
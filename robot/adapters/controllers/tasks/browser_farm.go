@@ -0,0 +1,41 @@
+package tasks
+
+import (
+	"automator-go/robot/adapters/gateways/browser_automator"
+	"context"
+	"fmt"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"os"
+	"strings"
+	"time"
+)
+
+// NewBrowserFarm connects to the comma separated CDP endpoints of
+// BROWSER_ENDPOINTS, or launches a local browser when it is empty, with
+// pagePoolSize page slots for each of them. The task profiles are kept in
+// BROWSER_PROFILES_DIR.
+func NewBrowserFarm(
+	ctx context.Context,
+	pagePoolSize int,
+	logger *otelzap.LoggerWithCtx,
+) (*browser_automator.BrowserFarm, error) {
+	var urls []string
+	for _, url := range strings.Split(os.Getenv("BROWSER_ENDPOINTS"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+
+	intervalEnv := os.Getenv("BROWSER_HEALTH_CHECK_INTERVAL")
+	if strings.TrimSpace(intervalEnv) == "" {
+		intervalEnv = "30s"
+	}
+	interval, err := time.ParseDuration(intervalEnv)
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("error parsing browser health check interval %q", intervalEnv)
+	}
+
+	profilesDir := strings.TrimSpace(os.Getenv("BROWSER_PROFILES_DIR"))
+
+	return browser_automator.NewBrowserFarm(ctx, urls, pagePoolSize, profilesDir, interval, logger)
+}
@@ -0,0 +1,40 @@
+package tasks
+
+import (
+	"automator-go/robot/adapters/gateways/publisher"
+	"automator-go/robot/usecases/task"
+	"automator-go/utils"
+	"fmt"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"os"
+	"strings"
+)
+
+// NewChangePublisher publishes the content changes on the exchange of the
+// robot with the RABBITMQ_EVENTS_ROUTING_KEY routing key. The changes are only
+// logged in the database when it is not set, the publisher is nil then.
+// Shutdown closes the connection of the publisher.
+func NewChangePublisher(logger *otelzap.LoggerWithCtx) (changePublisher task.ChangePublisher, shutdown func(), err error) {
+	routingKey := strings.TrimSpace(os.Getenv("RABBITMQ_EVENTS_ROUTING_KEY"))
+	if routingKey == "" {
+		return nil, func() {}, nil
+	}
+
+	connectionName := os.Getenv("RABBITMQ_CONNECTION_NAME")
+	if connectionName == "" {
+		connectionName = "robot"
+	}
+	client, err := utils.StartPublisher(logger, connectionName+"-publisher")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error starting change publisher: %w", err)
+	}
+
+	shutdown = func() {
+		if err := client.Shutdown(); err != nil {
+			logger.Error("Error shutting down change publisher", zap.Error(err))
+		}
+	}
+
+	return publisher.NewRabbitChangePublisher(client.Channel, client.Exchange, routingKey, logger), shutdown, nil
+}
@@ -0,0 +1,52 @@
+package tasks
+
+import (
+	"automator-go/robot/entities/models"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultMockFixturesDir holds the fixtures of the mock network rules when
+// MOCK_FIXTURES_DIR is not set.
+const defaultMockFixturesDir = "./fixtures"
+
+// MockFixturesDir is the only directory the mock network rules can read their
+// fixtures from.
+func MockFixturesDir() string {
+	dir := strings.TrimSpace(os.Getenv("MOCK_FIXTURES_DIR"))
+	if dir == "" {
+		return defaultMockFixturesDir
+	}
+
+	return dir
+}
+
+// LoadNetworkBlocklist reads the network rules of the NETWORK_BLOCKLIST_FILE
+// json file, applied to every task after its own rules. There are none when
+// it is not set.
+func LoadNetworkBlocklist() ([]models.NetworkRule, error) {
+	path := os.Getenv("NETWORK_BLOCKLIST_FILE")
+	if path == "" {
+		return nil, nil
+	}
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading network blocklist file: %w", err)
+	}
+
+	var rules []models.NetworkRule
+	if err = json.Unmarshal(file, &rules); err != nil {
+		return nil, fmt.Errorf("error unmarshalling network blocklist: %w", err)
+	}
+
+	for i, rule := range rules {
+		if err = rule.Validate(); err != nil {
+			return nil, fmt.Errorf("network blocklist rule %d: %w", i, err)
+		}
+	}
+
+	return rules, nil
+}
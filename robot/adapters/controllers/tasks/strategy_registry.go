@@ -0,0 +1,41 @@
+package tasks
+
+import (
+	"automator-go/robot/adapters/gateways/secrets"
+	bunRepo "automator-go/robot/adapters/repositories/bun"
+	"automator-go/robot/entities/models"
+	"automator-go/robot/usecases/task"
+	"encoding/json"
+	"fmt"
+	"github.com/uptrace/bun"
+	"os"
+)
+
+// NewStrategyRegistry registers the strategies of the STRATEGIES_FILE json
+// file, if any, on top of the ones stored in the database.
+func NewStrategyRegistry(db *bun.DB) (*task.StrategyRegistry, error) {
+	registry := task.NewStrategyRegistry(bunRepo.NewBunStrategy(db), secrets.NewEnvSecrets())
+
+	path := os.Getenv("STRATEGIES_FILE")
+	if path == "" {
+		return registry, nil
+	}
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading strategies file: %w", err)
+	}
+
+	var strategies []*models.Strategy
+	if err = json.Unmarshal(file, &strategies); err != nil {
+		return nil, fmt.Errorf("error unmarshalling strategies: %w", err)
+	}
+
+	for _, strategy := range strategies {
+		if err = registry.Register(strategy); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
@@ -2,48 +2,140 @@ package tasks
 
 import (
 	"automator-go/robot/adapters/gateways/browser_automator"
+	"automator-go/robot/adapters/gateways/differ"
+	"automator-go/robot/adapters/gateways/driver"
 	"automator-go/robot/adapters/gateways/hasher"
+	"automator-go/robot/adapters/gateways/http_automator"
 	"automator-go/robot/adapters/gateways/storage"
 	bunRepo "automator-go/robot/adapters/repositories/bun"
 	"automator-go/robot/entities/models"
 	"automator-go/robot/usecases/task"
 	"context"
-	"github.com/go-rod/rod"
+	"encoding/base64"
+	"fmt"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"os"
+	"strconv"
+	"strings"
 )
 
+// defaultRegressionThreshold is the hamming distance between perception
+// hashes over which a capture differs from its baseline.
+const defaultRegressionThreshold = 10
+
+func regressionThreshold() (int, error) {
+	thresholdEnv := os.Getenv("VISUAL_REGRESSION_THRESHOLD")
+	if strings.TrimSpace(thresholdEnv) == "" {
+		return defaultRegressionThreshold, nil
+	}
+
+	threshold, err := strconv.Atoi(strings.TrimSpace(thresholdEnv))
+	if err != nil || threshold < 0 || threshold > 64 {
+		return 0, fmt.Errorf("error parsing visual regression threshold env: %q is not a number of bits between 0 and 64", thresholdEnv)
+	}
+
+	return threshold, nil
+}
+
 type TaskController struct {
-	browser  *rod.Browser
-	pagePool rod.PagePool
-	db       *bun.DB
-	ctx      context.Context
-	logger   *otelzap.LoggerWithCtx
+	farm            *browser_automator.BrowserFarm
+	registry        *task.StrategyRegistry
+	blocklist       []models.NetworkRule
+	changePublisher task.ChangePublisher
+	db              *bun.DB
+	ctx             context.Context
+	logger          *otelzap.LoggerWithCtx
 }
 
+// NewTaskController receives a nil changePublisher to only log the changes of
+// the contents in the database.
 func NewTaskController(
-	browser *rod.Browser,
-	pagePool rod.PagePool,
+	farm *browser_automator.BrowserFarm,
+	registry *task.StrategyRegistry,
+	blocklist []models.NetworkRule,
+	changePublisher task.ChangePublisher,
 	db *bun.DB,
 	ctx context.Context,
 	logger *otelzap.LoggerWithCtx,
 ) *TaskController {
 	return &TaskController{
-		browser:  browser,
-		pagePool: pagePool,
-		db:       db,
-		ctx:      ctx,
-		logger:   logger,
+		farm:            farm,
+		registry:        registry,
+		blocklist:       blocklist,
+		changePublisher: changePublisher,
+		db:              db,
+		ctx:             ctx,
+		logger:          logger,
+	}
+}
+
+// CheckStrategies checks the task along with its resolved strategies: the
+// driver of the task runs their actions and they only run scripts when the
+// source of the task is allowed to.
+func (t *TaskController) CheckStrategies(taskToCheck *models.Task, policy models.ScriptPolicy, source models.TaskSource) error {
+	strategies, err := t.registry.Resolve(taskToCheck, t.ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = taskToCheck.ValidateStrategies(strategies); err != nil {
+		return err
 	}
+
+	return policy.Check(taskToCheck, strategies, source)
 }
 
 func (t *TaskController) ProcessTask(taskToProcess *models.Task) error {
 	t.logger.Debug("Initializing task processor")
-	automator := browser_automator.NewRodAutomator(t.browser, t.pagePool, t.logger)
+	var sessionStore task.SessionStore
+	if key := os.Getenv("SESSION_ENCRYPTION_KEY"); key != "" {
+		decodedKey, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return fmt.Errorf("error decoding session encryption key: %w", err)
+		}
+		bunSessionStore, err := bunRepo.NewBunSessionStore(t.db, decodedKey)
+		if err != nil {
+			return err
+		}
+		sessionStore = bunSessionStore
+	}
+	httpClient, err := http_automator.NewHttpClient()
+	if err != nil {
+		return err
+	}
+	automator := driver.NewRouter(map[string]task.AutomatorTaskAdapter{
+		models.BrowserDriver: browser_automator.NewRodAutomator(t.farm, sessionStore, t.blocklist, MockFixturesDir(), t.ctx, t.logger),
+		models.HttpDriver:    http_automator.NewHttpAutomator(httpClient, t.ctx, t.logger),
+	})
 	fileStorage := storage.NewFileStorage("png", t.logger)
 	mediaRepo := bunRepo.NewBunCaptureMedia(t.db)
-	hashHandler := hasher.NewPHashHandler(t.logger)
-	taskUseCase := task.NewProcessor(automator, mediaRepo, fileStorage, hashHandler)
+	hashHandler := hasher.NewImageHashHandler(t.logger)
+	taskRunRepo := bunRepo.NewBunTaskRun(t.db)
+	threshold, err := regressionThreshold()
+	if err != nil {
+		return err
+	}
+	regressionDetector := task.NewRegressionDetector(
+		bunRepo.NewBunBaseline(t.db),
+		fileStorage,
+		differ.NewPixelDiffHandler(t.logger),
+		threshold,
+	)
+	changeDetector := task.NewChangeDetector(bunRepo.NewBunContentChange(t.db), t.changePublisher, func(err error) {
+		t.logger.Error("Error detecting content changes", zap.Error(err))
+	})
+	taskUseCase := task.NewProcessor(
+		automator,
+		mediaRepo,
+		fileStorage,
+		hashHandler,
+		t.registry,
+		taskRunRepo,
+		regressionDetector,
+		changeDetector,
+	)
 	t.logger.Debug("Finished initializing task processor")
 
 	return taskUseCase.Process(taskToProcess, t.ctx)
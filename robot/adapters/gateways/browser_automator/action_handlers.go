@@ -0,0 +1,61 @@
+package browser_automator
+
+import (
+	"automator-go/robot/adapters/gateways/driver"
+	models2 "automator-go/robot/entities/models"
+	"automator-go/robot/usecases/task"
+	"context"
+	"github.com/go-rod/rod"
+	"strings"
+)
+
+// browserHandlers are the actions the browser runs on its own, over the
+// built-in handlers of the driver. The session actions run on the page of the
+// task, bound to the context of the action.
+func (at *RodAutomator) browserHandlers(page *rod.Page) map[models2.Action]driver.ActionHandler {
+	actionPage := func(run *driver.ActionRun) *rod.Page {
+		return page.Context(run.Page.Context())
+	}
+
+	return map[models2.Action]driver.ActionHandler{
+		models2.DownloadResource: func(run *driver.ActionRun) (*task.RawMedia, error) {
+			return downloadResource(run.Page, run.Action)
+		},
+		models2.WaitForElement: driver.WithoutMedia(func(run *driver.ActionRun) error { return waitForElement(run.Page, run.Action) }),
+		models2.EvaluateScript: driver.WithoutMedia(runEvaluateScript),
+		models2.SaveSession: driver.WithoutMedia(func(run *driver.ActionRun) error {
+			name, ttl := sessionTarget(run.Task, run.Action)
+
+			return at.saveSession(actionPage(run), name, ttl)
+		}),
+		models2.LoadSession: driver.WithoutMedia(func(run *driver.ActionRun) error {
+			name, _ := sessionTarget(run.Task, run.Action)
+
+			return at.loadSession(actionPage(run), name)
+		}),
+	}
+}
+
+// runEvaluateScript limits the script with BROWSER_SCRIPT_TIMEOUT when the
+// action has no timeout of its own.
+func runEvaluateScript(run *driver.ActionRun) error {
+	page := run.Page
+	if strings.TrimSpace(run.Action.Timeout) == "" {
+		timeout, err := scriptTimeout()
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(page.Context(), timeout)
+		defer cancel()
+		page = page.WithContext(ctx)
+	}
+
+	value, err := driver.EvaluateScript(page, run.Action)
+	if err != nil {
+		return err
+	}
+	run.Variables[run.Action.Id] = value
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package browser_automator
+
+import (
+	"automator-go/robot/entities/models"
+	"fmt"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/devices"
+	"github.com/go-rod/rod/lib/proto"
+	"net/url"
+)
+
+// devicePresets are the rod devices of the models.DevicePresets, by name.
+var devicePresets = func() map[string]devices.Device {
+	presets := make(map[string]devices.Device)
+	for _, device := range []devices.Device{
+		devices.IPhone4,
+		devices.IPhone5orSE,
+		devices.IPhone6or7or8,
+		devices.IPhone6or7or8Plus,
+		devices.IPhoneX,
+		devices.Nexus5,
+		devices.Nexus5X,
+		devices.Nexus6P,
+		devices.Pixel2,
+		devices.Pixel2XL,
+		devices.GalaxySIII,
+		devices.GalaxyS5,
+		devices.GalaxyNote3,
+		devices.GalaxyFold,
+		devices.MotoG4,
+		devices.SurfaceDuo,
+		devices.IPadMini,
+		devices.IPad,
+		devices.IPadPro,
+		devices.Nexus7,
+		devices.Nexus10,
+		devices.KindleFireHDX,
+		devices.LaptopWithTouch,
+		devices.LaptopWithHiDPIScreen,
+		devices.LaptopWithMDPIScreen,
+	} {
+		presets[device.Title] = device
+	}
+
+	return presets
+}()
+
+func permissionOrigin(rawUrl string) (string, error) {
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", fmt.Errorf("error parsing task url: %w", err)
+	}
+
+	return parsedUrl.Scheme + "://" + parsedUrl.Host, nil
+}
+
+// setGeolocationPermission changes the permission in the browser context of
+// the page only.
+func setGeolocationPermission(page *rod.Page, setting proto.BrowserPermissionSetting, taskUrl string) error {
+	origin, err := permissionOrigin(taskUrl)
+	if err != nil {
+		return err
+	}
+
+	browser := page.Browser()
+
+	return proto.BrowserSetPermission{
+		Permission:       &proto.BrowserPermissionDescriptor{Name: "geolocation"},
+		Setting:          setting,
+		Origin:           origin,
+		BrowserContextID: browser.BrowserContextID,
+	}.Call(browser)
+}
+
+// applyBrowserConfig emulates the task browser context on the page. It must
+// be called before navigating to the task url.
+func applyBrowserConfig(page *rod.Page, config *models.BrowserConfig, taskUrl string) error {
+	userAgent := &proto.NetworkSetUserAgentOverride{}
+
+	if config.Device != "" {
+		device, ok := devicePresets[config.Device]
+		if !ok {
+			return fmt.Errorf("unknown device %q", config.Device)
+		}
+		if config.Landscape {
+			device = device.Landscape()
+		}
+
+		if err := page.Emulate(device); err != nil {
+			return fmt.Errorf("error emulating device: %w", err)
+		}
+		userAgent = device.UserAgentEmulation()
+	}
+
+	if config.Viewport != nil {
+		err := page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
+			Width:             config.Viewport.Width,
+			Height:            config.Viewport.Height,
+			DeviceScaleFactor: config.Viewport.DeviceScaleFactor,
+			Mobile:            config.Viewport.Mobile,
+		})
+		if err != nil {
+			return fmt.Errorf("error setting viewport: %w", err)
+		}
+	}
+
+	if config.UserAgent != "" || config.Locale != "" {
+		if config.UserAgent != "" {
+			userAgent.UserAgent = config.UserAgent
+		}
+		if userAgent.UserAgent == "" {
+			version, err := proto.BrowserGetVersion{}.Call(page.Browser())
+			if err != nil {
+				return fmt.Errorf("error getting browser user agent: %w", err)
+			}
+			userAgent.UserAgent = version.UserAgent
+		}
+		if config.Locale != "" {
+			userAgent.AcceptLanguage = config.AcceptLanguage()
+		}
+
+		if err := page.SetUserAgent(userAgent); err != nil {
+			return fmt.Errorf("error setting user agent: %w", err)
+		}
+	}
+
+	if config.Locale != "" {
+		if err := (proto.EmulationSetLocaleOverride{Locale: config.Locale}).Call(page); err != nil {
+			return fmt.Errorf("error setting locale: %w", err)
+		}
+	}
+
+	if config.Timezone != "" {
+		if err := (proto.EmulationSetTimezoneOverride{TimezoneID: config.Timezone}).Call(page); err != nil {
+			return fmt.Errorf("error setting timezone: %w", err)
+		}
+	}
+
+	if config.Geolocation != nil {
+		if err := setGeolocationPermission(page, proto.BrowserPermissionSettingGranted, taskUrl); err != nil {
+			return fmt.Errorf("error granting geolocation permission: %w", err)
+		}
+
+		err := proto.EmulationSetGeolocationOverride{
+			Latitude:  &config.Geolocation.Latitude,
+			Longitude: &config.Geolocation.Longitude,
+			Accuracy:  &config.Geolocation.Accuracy,
+		}.Call(page)
+		if err != nil {
+			return fmt.Errorf("error setting geolocation: %w", err)
+		}
+	}
+
+	if config.ColorScheme != "" {
+		err := proto.EmulationSetEmulatedMedia{
+			Features: []*proto.EmulationMediaFeature{{Name: "prefers-color-scheme", Value: config.ColorScheme}},
+		}.Call(page)
+		if err != nil {
+			return fmt.Errorf("error setting color scheme: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// revokeBrowserPermissions resets the permissions granted by
+// applyBrowserConfig, which outlive the page in a profile browser context.
+func revokeBrowserPermissions(page *rod.Page, config *models.BrowserConfig, taskUrl string) error {
+	if config.Geolocation == nil {
+		return nil
+	}
+
+	if err := setGeolocationPermission(page, proto.BrowserPermissionSettingPrompt, taskUrl); err != nil {
+		return fmt.Errorf("error resetting geolocation permission: %w", err)
+	}
+
+	return nil
+}
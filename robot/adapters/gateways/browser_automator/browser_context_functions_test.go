@@ -0,0 +1,17 @@
+package browser_automator
+
+import (
+	"automator-go/robot/entities/models"
+	"testing"
+)
+
+func TestDevicePresets(t *testing.T) {
+	if len(devicePresets) != len(models.DevicePresets) {
+		t.Errorf("devicePresets has %d devices, want the %d models.DevicePresets", len(devicePresets), len(models.DevicePresets))
+	}
+	for _, name := range models.DevicePresets {
+		if _, ok := devicePresets[name]; !ok {
+			t.Errorf("devicePresets has no device %q", name)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package browser_automator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// pingTimeout limits how long a health check waits for a browser to answer.
+const pingTimeout = 5 * time.Second
+
+// browserEndpoint is a browser the farm runs tasks on. Its page slots limit
+// how many tasks run on it at the same time, a slot is taken by sending to
+// slots and freed by receiving from it.
+type browserEndpoint struct {
+	// url is the CDP endpoint of a remote browser, empty for the local one.
+	url     string
+	conn    *browserConnection
+	slots   chan struct{}
+	healthy bool
+}
+
+func (ep *browserEndpoint) freeSlots() int {
+	return cap(ep.slots) - len(ep.slots)
+}
+
+// browserConnection is the connection of the robot to a browser.
+type browserConnection struct {
+	browser *rod.Browser
+	// kill stops the local browser the robot launched, nil for remote ones.
+	kill func()
+	// disconnect closes the websocket to the browser.
+	disconnect context.CancelFunc
+}
+
+// close disconnects from the browser. The local browser is killed, remote
+// ones are shared with other robots and are left running.
+func (c *browserConnection) close() {
+	if c.kill != nil {
+		c.kill()
+	}
+	if c.disconnect != nil {
+		c.disconnect()
+	}
+}
+
+func (ep *browserEndpoint) name() string {
+	if ep.url == "" {
+		return "local"
+	}
+
+	return ep.url
+}
+
+// BrowserFarm distributes the tasks across one or more browsers, so browser
+// capacity scales apart from the robot processes. Browsers failing their
+// health checks get no tasks until they are reconnected.
+type BrowserFarm struct {
+	mu        sync.Mutex
+	endpoints []*browserEndpoint
+	profiles  *BrowserProfiles
+	// changed is closed, and replaced, when a page is released or an
+	// endpoint turns healthy, waking up the tasks waiting for a page.
+	changed        chan struct{}
+	healthInterval time.Duration
+	ctx            context.Context
+	// stop ends the health checks.
+	stop context.CancelFunc
+	// connect and ping reach the browsers.
+	connect func(ctx context.Context, url string) (*browserConnection, error)
+	ping    func(conn *browserConnection) error
+	logger  *otelzap.LoggerWithCtx
+}
+
+// NewBrowserFarm connects to the browsers of the urls, launching a local one
+// when there are none, and starts checking their health every
+// healthInterval. Every browser gets pagePoolSize page slots. The profiles of
+// the tasks are kept in profilesDir, tasks with a profile fail when it is
+// empty.
+func NewBrowserFarm(
+	ctx context.Context,
+	urls []string,
+	pagePoolSize int,
+	profilesDir string,
+	healthInterval time.Duration,
+	logger *otelzap.LoggerWithCtx,
+) (*BrowserFarm, error) {
+	if len(urls) == 0 {
+		urls = []string{""}
+	}
+
+	healthCtx, stop := context.WithCancel(ctx)
+	farm := &BrowserFarm{
+		endpoints:      make([]*browserEndpoint, 0, len(urls)),
+		profiles:       NewBrowserProfiles(ctx, profilesDir),
+		changed:        make(chan struct{}),
+		healthInterval: healthInterval,
+		ctx:            ctx,
+		stop:           stop,
+		connect:        connectBrowser,
+		ping:           pingBrowser,
+		logger:         logger,
+	}
+
+	for _, url := range urls {
+		endpoint := &browserEndpoint{url: url, slots: make(chan struct{}, pagePoolSize)}
+		conn, err := farm.connect(ctx, url)
+		if err != nil {
+			// Remote browsers may come up later, the health checks connect them.
+			logger.Warn("Browser is not available", zap.String("endpoint", endpoint.name()), zap.Error(err))
+		} else {
+			endpoint.conn = conn
+			endpoint.healthy = true
+			logger.Debug("Connected to browser", zap.String("endpoint", endpoint.name()))
+		}
+		farm.endpoints = append(farm.endpoints, endpoint)
+	}
+
+	if farm.healthyCount() == 0 {
+		farm.Close()
+		return nil, errors.New("no browser is available")
+	}
+
+	go farm.checkHealthLoop(healthCtx)
+
+	return farm, nil
+}
+
+// connectBrowser connects to the browser of the url, launching a local one
+// when it is empty.
+func connectBrowser(ctx context.Context, url string) (*browserConnection, error) {
+	if url == "" {
+		return launchBrowser(ctx, "")
+	}
+
+	// Accepts both the websocket url of the browser and the host:port of its
+	// debugging server.
+	controlUrl, err := launcher.ResolveURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving browser url: %w", err)
+	}
+
+	return dialBrowser(ctx, controlUrl, nil)
+}
+
+// launchBrowser launches a local browser on userDataDir, which is kept when
+// the browser is killed. An empty userDataDir launches it on a temporary one,
+// removed with the browser.
+func launchBrowser(ctx context.Context, userDataDir string) (*browserConnection, error) {
+	local := launcher.New().Context(ctx)
+	kill := func() {
+		local.Kill()
+		local.Cleanup()
+	}
+	if userDataDir != "" {
+		local = local.UserDataDir(userDataDir)
+		kill = local.Kill
+	}
+
+	controlUrl, err := local.Launch()
+	if err != nil {
+		return nil, fmt.Errorf("error launching browser: %w", err)
+	}
+
+	return dialBrowser(ctx, controlUrl, kill)
+}
+
+func dialBrowser(ctx context.Context, controlUrl string, kill func()) (*browserConnection, error) {
+	connCtx, disconnect := context.WithCancel(ctx)
+	conn := &browserConnection{kill: kill, disconnect: disconnect}
+
+	browser := rod.New().Context(connCtx).ControlURL(controlUrl)
+	if err := browser.Connect(); err != nil {
+		conn.close()
+		return nil, fmt.Errorf("error connecting to browser: %w", err)
+	}
+	conn.browser = browser
+
+	return conn, nil
+}
+
+func pingBrowser(conn *browserConnection) error {
+	_, err := proto.BrowserGetVersion{}.Call(conn.browser.Timeout(pingTimeout))
+
+	return err
+}
+
+// BrowserLease is a page slot taken on one of the browsers of the farm, it
+// must be released when the task finishes.
+type BrowserLease struct {
+	Browser  *rod.Browser
+	Profiles *BrowserProfiles
+	Endpoint string
+	farm     *BrowserFarm
+	slots    chan struct{}
+}
+
+func (l *BrowserLease) Release() {
+	<-l.slots
+	l.farm.notifyChanged()
+}
+
+// Acquire waits for a free page slot on a healthy browser, the one with more
+// free slots.
+func (bf *BrowserFarm) Acquire() (*BrowserLease, error) {
+	for {
+		lease, changed := bf.tryAcquire()
+		if lease != nil {
+			return lease, nil
+		}
+
+		select {
+		case <-bf.ctx.Done():
+			return nil, bf.ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (bf *BrowserFarm) tryAcquire() (*BrowserLease, <-chan struct{}) {
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+
+	var chosen *browserEndpoint
+	for _, endpoint := range bf.endpoints {
+		if endpoint.healthy && endpoint.freeSlots() > 0 && (chosen == nil || endpoint.freeSlots() > chosen.freeSlots()) {
+			chosen = endpoint
+		}
+	}
+	if chosen == nil {
+		return nil, bf.changed
+	}
+
+	// Slots are only taken under the lock, the chosen one is still free.
+	chosen.slots <- struct{}{}
+
+	return &BrowserLease{
+		Browser:  chosen.conn.browser,
+		Profiles: bf.profiles,
+		Endpoint: chosen.name(),
+		farm:     bf,
+		slots:    chosen.slots,
+	}, nil
+}
+
+func (bf *BrowserFarm) notifyChanged() {
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+
+	close(bf.changed)
+	bf.changed = make(chan struct{})
+}
+
+func (bf *BrowserFarm) healthyCount() int {
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+
+	count := 0
+	for _, endpoint := range bf.endpoints {
+		if endpoint.healthy {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (bf *BrowserFarm) checkHealthLoop(ctx context.Context) {
+	ticker := time.NewTicker(bf.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			bf.checkHealth()
+		}
+	}
+}
+
+// checkHealth pings every browser and reconnects the ones not answering. The
+// old connection is closed first, the local browser is killed with it.
+func (bf *BrowserFarm) checkHealth() {
+	bf.mu.Lock()
+	endpoints := append([]*browserEndpoint(nil), bf.endpoints...)
+	bf.mu.Unlock()
+
+	for _, endpoint := range endpoints {
+		bf.mu.Lock()
+		conn := endpoint.conn
+		bf.mu.Unlock()
+
+		if conn != nil {
+			err := bf.ping(conn)
+			if err == nil {
+				continue
+			}
+			bf.logger.Warn("Browser failed health check", zap.String("endpoint", endpoint.name()), zap.Error(err))
+		}
+
+		bf.mu.Lock()
+		endpoint.healthy = false
+		endpoint.conn = nil
+		bf.mu.Unlock()
+
+		if conn != nil {
+			conn.close()
+		}
+
+		reconnected, err := bf.connect(bf.ctx, endpoint.url)
+		if err != nil {
+			bf.logger.Debug("Browser is still not available", zap.String("endpoint", endpoint.name()), zap.Error(err))
+			continue
+		}
+
+		bf.mu.Lock()
+		endpoint.conn = reconnected
+		endpoint.healthy = true
+		bf.mu.Unlock()
+		bf.notifyChanged()
+		bf.logger.Info("Reconnected to browser", zap.String("endpoint", endpoint.name()))
+	}
+}
+
+// Close stops the health checks and kills the local browser and the ones of
+// the profiles. Remote browsers are left running, they are shared with other
+// robots.
+func (bf *BrowserFarm) Close() {
+	bf.stop()
+	bf.profiles.Close()
+
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+
+	for _, endpoint := range bf.endpoints {
+		if endpoint.conn != nil {
+			endpoint.conn.close()
+			endpoint.conn = nil
+			endpoint.healthy = false
+		}
+	}
+}
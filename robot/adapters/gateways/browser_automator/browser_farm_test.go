@@ -0,0 +1,134 @@
+package browser_automator
+
+import (
+	"context"
+	"errors"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func newTestFarm(endpoints ...*browserEndpoint) *BrowserFarm {
+	return &BrowserFarm{
+		endpoints: endpoints,
+		profiles:  NewBrowserProfiles(context.Background(), ""),
+		changed:   make(chan struct{}),
+		ctx:       context.Background(),
+	}
+}
+
+func TestBrowserFarm_Acquire(t *testing.T) {
+	tests := []struct {
+		name         string
+		endpoints    []*browserEndpoint
+		wantEndpoint string
+	}{
+		{
+			name: "Browser with more free pages",
+			endpoints: []*browserEndpoint{
+				{url: "ws://chrome-1:9222", conn: &browserConnection{}, slots: make(chan struct{}, 1), healthy: true},
+				{url: "ws://chrome-2:9222", conn: &browserConnection{}, slots: make(chan struct{}, 3), healthy: true},
+			},
+			wantEndpoint: "ws://chrome-2:9222",
+		},
+		{
+			name: "Unhealthy browser",
+			endpoints: []*browserEndpoint{
+				{url: "ws://chrome-1:9222", conn: &browserConnection{}, slots: make(chan struct{}, 1), healthy: true},
+				{url: "ws://chrome-2:9222", slots: make(chan struct{}, 3), healthy: false},
+			},
+			wantEndpoint: "ws://chrome-1:9222",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			farm := newTestFarm(tt.endpoints...)
+			for i := 0; i < 2; i++ {
+				lease, err := farm.Acquire()
+				if err != nil {
+					t.Fatalf("BrowserFarm.Acquire() error = %v", err)
+				}
+				if lease.Endpoint != tt.wantEndpoint {
+					t.Errorf("BrowserFarm.Acquire() endpoint = %v, want %v", lease.Endpoint, tt.wantEndpoint)
+				}
+				lease.Release()
+			}
+		})
+	}
+}
+
+func TestBrowserFarm_AcquireWaitsForRelease(t *testing.T) {
+	farm := newTestFarm(&browserEndpoint{url: "ws://chrome-1:9222", conn: &browserConnection{}, slots: make(chan struct{}, 1), healthy: true})
+
+	lease, err := farm.Acquire()
+	if err != nil {
+		t.Fatalf("BrowserFarm.Acquire() error = %v", err)
+	}
+
+	acquired := make(chan *BrowserLease)
+	go func() {
+		next, _ := farm.Acquire()
+		acquired <- next
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("BrowserFarm.Acquire() returned while the only page was taken")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	lease.Release()
+
+	select {
+	case next := <-acquired:
+		if next == nil {
+			t.Error("BrowserFarm.Acquire() returned no lease after release")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BrowserFarm.Acquire() did not return after release")
+	}
+}
+
+func TestBrowserFarm_CheckHealthReconnectsLocalBrowser(t *testing.T) {
+	logger := otelzap.New(zap.NewNop()).Ctx(context.Background())
+	launched, killed := 0, 0
+	newConn := func() *browserConnection {
+		launched++
+		return &browserConnection{kill: func() { killed++ }}
+	}
+
+	oldConn := newConn()
+	farm := newTestFarm(&browserEndpoint{conn: oldConn, slots: make(chan struct{}, 1), healthy: true})
+	farm.stop = func() {}
+	farm.logger = &logger
+	farm.connect = func(context.Context, string) (*browserConnection, error) {
+		return newConn(), nil
+	}
+	farm.ping = func(conn *browserConnection) error {
+		if conn == oldConn {
+			return errors.New("browser is not answering")
+		}
+		return nil
+	}
+
+	farm.checkHealth()
+	if launched != 2 || killed != 1 {
+		t.Errorf("after the failed check launched = %d, killed = %d, want 2 and 1", launched, killed)
+	}
+	if endpoint := farm.endpoints[0]; !endpoint.healthy || endpoint.conn == oldConn {
+		t.Errorf("endpoint healthy = %v, want a new healthy connection", endpoint.healthy)
+	}
+
+	// The new browser answers, it is not launched again.
+	farm.checkHealth()
+	if launched != 2 || killed != 1 {
+		t.Errorf("after the passed check launched = %d, killed = %d, want 2 and 1", launched, killed)
+	}
+
+	farm.Close()
+	if killed != 2 {
+		t.Errorf("after Close killed = %d, want 2", killed)
+	}
+}
@@ -0,0 +1,75 @@
+package browser_automator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-rod/rod"
+	"path/filepath"
+	"sync"
+)
+
+// BrowserProfiles keeps the browsers of the named profiles that tasks opt into
+// when they need to share cookies and storage, like a login, with other tasks.
+// Each profile is a local browser launched on its own user data dir in dir,
+// so it outlives the robot. The browser locks its user data dir, only one
+// robot can use a profile at a time.
+type BrowserProfiles struct {
+	dir      string
+	ctx      context.Context
+	mu       sync.Mutex
+	browsers map[string]*browserConnection
+	// launch and ping reach the browsers of the profiles.
+	launch func(ctx context.Context, userDataDir string) (*browserConnection, error)
+	ping   func(conn *browserConnection) error
+}
+
+// NewBrowserProfiles receives an empty dir when profiles are not configured,
+// tasks using them fail then.
+func NewBrowserProfiles(ctx context.Context, dir string) *BrowserProfiles {
+	return &BrowserProfiles{
+		dir:      dir,
+		ctx:      ctx,
+		browsers: make(map[string]*browserConnection),
+		launch:   launchBrowser,
+		ping:     pingBrowser,
+	}
+}
+
+// Get returns the browser of the profile, launching it the first time and
+// again when it stopped answering.
+func (bp *BrowserProfiles) Get(name string) (*rod.Browser, error) {
+	if bp.dir == "" {
+		return nil, errors.New("browser profiles are not configured")
+	}
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	if conn, ok := bp.browsers[name]; ok {
+		if bp.ping(conn) == nil {
+			return conn.browser, nil
+		}
+		conn.close()
+		delete(bp.browsers, name)
+	}
+
+	conn, err := bp.launch(bp.ctx, filepath.Join(bp.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("error launching browser of profile %q: %w", name, err)
+	}
+	bp.browsers[name] = conn
+
+	return conn.browser, nil
+}
+
+// Close kills the browser of every profile, their user data dirs are kept.
+func (bp *BrowserProfiles) Close() {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	for name, conn := range bp.browsers {
+		conn.close()
+		delete(bp.browsers, name)
+	}
+}
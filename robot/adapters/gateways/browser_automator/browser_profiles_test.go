@@ -0,0 +1,53 @@
+package browser_automator
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestBrowserProfiles_Get(t *testing.T) {
+	dir := t.TempDir()
+	var launched []string
+	killed := 0
+	answering := true
+	profiles := NewBrowserProfiles(context.Background(), dir)
+	profiles.launch = func(_ context.Context, userDataDir string) (*browserConnection, error) {
+		launched = append(launched, userDataDir)
+		return &browserConnection{kill: func() { killed++ }}, nil
+	}
+	profiles.ping = func(*browserConnection) error {
+		if !answering {
+			return errors.New("browser is not answering")
+		}
+		return nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := profiles.Get("wikipedia-login"); err != nil {
+			t.Fatalf("BrowserProfiles.Get() error = %v", err)
+		}
+	}
+	if len(launched) != 1 || launched[0] != filepath.Join(dir, "wikipedia-login") {
+		t.Errorf("launched = %v, want one browser on the user data dir of the profile", launched)
+	}
+
+	// A browser not answering is killed and launched again on the same dir.
+	answering = false
+	if _, err := profiles.Get("wikipedia-login"); err != nil {
+		t.Fatalf("BrowserProfiles.Get() error = %v", err)
+	}
+	if len(launched) != 2 || launched[1] != launched[0] || killed != 1 {
+		t.Errorf("launched = %v, killed = %d, want the profile relaunched on its dir", launched, killed)
+	}
+
+	profiles.Close()
+	if killed != 2 {
+		t.Errorf("killed = %d after Close, want 2", killed)
+	}
+
+	if _, err := NewBrowserProfiles(context.Background(), "").Get("wikipedia-login"); err == nil {
+		t.Error("BrowserProfiles.Get() error = nil, want an error without profiles dir")
+	}
+}
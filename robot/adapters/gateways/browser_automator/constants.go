@@ -1,3 +1,43 @@
 package browser_automator
 
-var VideoExtensions = []string{"mp4", "avi", "mkv", "webm", "mov", "flv", "wmv", "m4v", "mpeg", "mpg", "3gp", "3g2"}
+import "github.com/go-rod/rod/lib/input"
+
+// tracerName names the spans of the tasks.
+const tracerName = "automator-go/robot/adapters/gateways/browser_automator"
+
+// rodKeys maps the modifier and named keys of the validation package, the
+// ones PressKey accepts, to rod keys. Single letters and digits are mapped
+// directly from their rune.
+var rodKeys = map[string]input.Key{
+	"Control":    input.ControlLeft,
+	"Shift":      input.ShiftLeft,
+	"Alt":        input.AltLeft,
+	"Meta":       input.MetaLeft,
+	"Enter":      input.Enter,
+	"Tab":        input.Tab,
+	"Escape":     input.Escape,
+	"Backspace":  input.Backspace,
+	"Delete":     input.Delete,
+	"Insert":     input.Insert,
+	"Space":      input.Space,
+	"ArrowUp":    input.ArrowUp,
+	"ArrowDown":  input.ArrowDown,
+	"ArrowLeft":  input.ArrowLeft,
+	"ArrowRight": input.ArrowRight,
+	"Home":       input.Home,
+	"End":        input.End,
+	"PageUp":     input.PageUp,
+	"PageDown":   input.PageDown,
+	"F1":         input.F1,
+	"F2":         input.F2,
+	"F3":         input.F3,
+	"F4":         input.F4,
+	"F5":         input.F5,
+	"F6":         input.F6,
+	"F7":         input.F7,
+	"F8":         input.F8,
+	"F9":         input.F9,
+	"F10":        input.F10,
+	"F11":        input.F11,
+	"F12":        input.F12,
+}
@@ -1,297 +1,156 @@
 package browser_automator
 
 import (
+	"automator-go/robot/adapters/gateways/driver"
 	"automator-go/robot/entities/models"
 	"automator-go/robot/entities/validation"
 	"automator-go/robot/usecases/task"
 	"fmt"
 	"github.com/go-rod/rod"
-	"github.com/go-rod/rod/lib/proto"
-	"io"
-	"net/http"
+	"github.com/go-rod/rod/lib/input"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 )
 
-const (
-	timeLayout = "2006-01-02T15:04:05Z04:00"
-)
-
-func findElement(page *rod.Page, action models.TaskAction) (*rod.Element, error) {
-	var element *rod.Element
-	var err error
-	if validation.IsXpath(action.Value) {
-		element, err = page.ElementX(action.Value)
-	} else {
-		element, err = page.Element(action.Value)
-	}
-
-	if err != nil {
-		return nil, fmt.Errorf("error getting element by selector: %w", err)
-	}
-
-	return element, nil
+// elementScope is where a selector is searched: the page, an iframe document
+// (also a *rod.Page) or an element like a shadow root.
+type elementScope interface {
+	Element(selector string) (*rod.Element, error)
+	ElementX(xPath string) (*rod.Element, error)
 }
 
-func click(page *rod.Page, action models.TaskAction) error {
-	element, err := findElement(page, action)
-	if err != nil {
-		return err
-	}
-
-	err = element.Click(proto.InputMouseButtonLeft, 1)
-	if err != nil {
-		return fmt.Errorf("error clicking element: %w", err)
-	}
-
-	return nil
-}
-
-func navigate(page *rod.Page, action models.TaskAction) error {
-	err := click(page, action)
-	if err != nil {
-		return err
-	}
-
-	page.WaitNavigation(proto.PageLifecycleEventNameNetworkAlmostIdle)
-
-	return nil
-}
-
-func scrollDown(page *rod.Page, action models.TaskAction) error {
-	parsedSteps, err := strconv.ParseInt(action.Value, 10, 64)
-	if err != nil {
-		return fmt.Errorf("error parsing scroll steps: %w", err)
-	}
-
-	window, err := page.GetWindow()
+// stepInto returns the scope for the next selector of a chain.
+func stepInto(element *rod.Element) (elementScope, error) {
+	node, err := element.Describe(1, false)
 	if err != nil {
-		return fmt.Errorf("error getting page window: %w", err)
+		return nil, fmt.Errorf("error describing chained element: %w", err)
 	}
 
-	currentPos := float64(*window.Top)
-	for i := 0; i < int(parsedSteps); i++ {
-		currentPos += 100
-		err = page.Mouse.Scroll(0, currentPos, 0)
+	if node.NodeName == "IFRAME" || node.NodeName == "FRAME" {
+		frame, err := element.Frame()
 		if err != nil {
-			return fmt.Errorf("error scrolling down: %w", err)
+			return nil, fmt.Errorf("error getting iframe document: %w", err)
 		}
-	}
-
-	return nil
-}
-
-func captureElement(element *rod.Element) ([]byte, error) {
-	bin, err := element.Screenshot(proto.PageCaptureScreenshotFormatPng, 0)
-
-	if err != nil {
-		return nil, fmt.Errorf("error capturing element: %w", err)
-	}
-
-	return bin, nil
-}
-
-func _captureAction(page *rod.Page, element *rod.Element, resource bool) (*task.RawMedia, error) {
-	mediaScreenshot, err := captureElement(element)
-	if err != nil {
-		return nil, err
-	}
 
-	pageScreenshot, err := page.Screenshot(false, &proto.PageCaptureScreenshot{
-		Format: proto.PageCaptureScreenshotFormatPng,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("error capturing page: %w", err)
-	}
-
-	shape, err := element.Shape()
-	if err != nil {
-		return nil, fmt.Errorf("error getting element shape: %w", err)
+		return frame, nil
 	}
 
-	box := shape.Box()
-
-	info, err := page.Info()
-	if err != nil {
-		return nil, fmt.Errorf("error getting page info: %w", err)
-	}
-
-	extension := "png"
-
-	var resourceCapture []byte
-	if resource {
-		err = element.WaitLoad()
+	if len(node.ShadowRoots) > 0 {
+		shadowRoot, err := element.ShadowRoot()
 		if err != nil {
-			return nil, fmt.Errorf("error waiting resource to load: %w", err)
+			return nil, fmt.Errorf("error getting shadow root: %w", err)
 		}
 
-		src, err := element.Property("currentSrc")
-		if err != nil {
-			return nil, fmt.Errorf("error getting element currentSrc: %w", err)
-		}
-		srcSplit := strings.Split(src.String(), ".")
-		extension = srcSplit[len(srcSplit)-1]
+		return shadowRoot, nil
+	}
 
-		video := false
-		for _, videoExtension := range VideoExtensions {
-			if extension == videoExtension {
-				video = true
-				break
-			}
-		}
+	return element, nil
+}
 
-		if video {
-			httpClient := http.DefaultClient
-			getVideo, err := httpClient.Get(src.String())
-			defer func(Body io.ReadCloser) {
-				err := Body.Close()
-				if err != nil {
-					println("error closing video body")
-				}
-			}(getVideo.Body)
+// findInChain searches each selector inside the element found by the
+// previous one, stepping into iframes and shadow roots.
+func findInChain(page *rod.Page, selectors []validation.Selector) (*rod.Element, error) {
+	var scope elementScope = page
+	var element *rod.Element
+	var err error
+	for i, selector := range selectors {
+		if i > 0 {
+			scope, err = stepInto(element)
 			if err != nil {
-				return nil, fmt.Errorf("error getting video: %w", err)
+				return nil, err
 			}
+		}
 
-			resourceCapture, err = io.ReadAll(getVideo.Body)
-			if err != nil {
-				return nil, fmt.Errorf("error reading video: %w", err)
-			}
+		query, isXpath := selector.Query()
+		if isXpath {
+			element, err = scope.ElementX(query)
 		} else {
-			resourceCapture, err = element.Resource()
-			if err != nil {
-				return nil, fmt.Errorf("error getting element resource: %w", err)
-			}
+			element, err = scope.Element(query)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error getting element by selector: %w", err)
 		}
 	}
 
-	return &task.RawMedia{
-		Ext:        extension,
-		Media:      mediaScreenshot,
-		Screenshot: pageScreenshot,
-		Resource:   resourceCapture,
-		Height:     box.Height,
-		Width:      box.Width,
-		X:          box.X,
-		Y:          box.Y,
-		Url:        info.URL,
-	}, nil
+	return element, nil
 }
 
-func capture(page *rod.Page, action models.TaskAction) (*task.RawMedia, error) {
-	element, err := findElement(page, action)
+// downloadElementResource streams the resource of the element to a temporary
+// file, with the size limit of BROWSER_RESOURCE_MAX_SIZE.
+func downloadElementResource(element *rod.Element) (*downloadedResource, error) {
+	maxSize, err := maxResourceSize()
 	if err != nil {
 		return nil, err
 	}
 
-	timeOutStableEnv := os.Getenv("BROWSER_WAIT_STABLE_TIMEOUT")
-	if strings.TrimSpace(timeOutStableEnv) == "" {
-		timeOutStableEnv = "5s"
-	}
-
-	timeOutStable, err := time.ParseDuration(timeOutStableEnv)
+	// Images are read once loaded, media elements stream their resource and
+	// have no load event to wait for.
+	tagName, err := element.Eval(`() => this.tagName`)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing timeout stable env: %w", err)
+		return nil, fmt.Errorf("error getting element tag: %w", err)
 	}
-
-	if err = page.WaitStable(timeOutStable); err != nil {
-		return nil, fmt.Errorf("error waiting element to load: %w", err)
-	}
-
-	return _captureAction(page, element, false)
-}
-
-func waitSeconds(action models.TaskAction) error {
-	parsedSeconds, err := strconv.ParseInt(action.Value, 10, 64)
-	if err != nil {
-		return fmt.Errorf("error parsing seconds: %w", err)
-	}
-
-	seconds := int(parsedSeconds)
-	if seconds < 1 {
-		return fmt.Errorf("seconds must be greater than 0")
-	}
-
-	time.Sleep(time.Duration(seconds) * time.Second)
-
-	return nil
-}
-
-func writeInput(page *rod.Page, action models.TaskAction) error {
-	element, err := findElement(page, action)
-	if err != nil {
-		return err
+	if tagName.Value.Str() == "IMG" {
+		if err = element.WaitLoad(); err != nil {
+			return nil, fmt.Errorf("error waiting resource to load: %w", err)
+		}
 	}
 
-	err = element.Input(action.Value)
-	if err != nil {
-		return fmt.Errorf("error writing input: %w", err)
-	}
+	downloader := &resourceDownloader{page: element.Page(), maxSize: maxSize}
 
-	return nil
+	return downloader.download(element)
 }
 
-func clearInput(page *rod.Page, action models.TaskAction) error {
-	element, err := findElement(page, action)
+// downloadResource captures the element and streams its resource to a
+// temporary file.
+func downloadResource(page driver.Page, action models.TaskAction) (*task.RawMedia, error) {
+	element, err := driver.FindElement(page, action)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = element.SelectAllText()
+	rawMedia, err := driver.CaptureElement(page, element)
 	if err != nil {
-		return fmt.Errorf("error selecting text on input: %w", err)
+		return nil, err
 	}
 
-	err = element.Input("")
+	file, ext, err := element.ResourceFile()
 	if err != nil {
-		return fmt.Errorf("error clearing input: %w", err)
+		return nil, err
 	}
+	rawMedia.Ext = ext
+	rawMedia.ResourceFile = file
 
-	return nil
+	return rawMedia, nil
 }
 
-func selectOptions(page *rod.Page, action models.TaskAction) error {
-	element, err := findElement(page, action)
-	if err != nil {
-		return err
-	}
-
-	parsedOptions := strings.Split(action.Value, ",")
+// parseKeys maps the validated key names of a combination to rod keys.
+func parseKeys(names []string) []input.Key {
+	keys := make([]input.Key, 0, len(names))
+	for _, name := range names {
+		if key, ok := rodKeys[name]; ok {
+			keys = append(keys, key)
+			continue
+		}
 
-	err = element.Select(parsedOptions, true, rod.SelectorTypeText)
-	if err != nil {
-		return fmt.Errorf("error selecting option: %w", err)
+		// Shift must be explicit in combinations, so letters are typed lowercase.
+		keys = append(keys, input.Key(strings.ToLower(name)[0]))
 	}
 
-	return nil
+	return keys
 }
 
-func writeTime(page *rod.Page, action models.TaskAction) error {
-	element, err := findElement(page, action)
-	if err != nil {
-		return err
-	}
-
-	timeToWrite, err := time.Parse(timeLayout, action.Value)
-	if err != nil {
-		return fmt.Errorf("error parsing time: %w", err)
+func scriptTimeout() (time.Duration, error) {
+	scriptTimeoutEnv := os.Getenv("BROWSER_SCRIPT_TIMEOUT")
+	if strings.TrimSpace(scriptTimeoutEnv) == "" {
+		scriptTimeoutEnv = "10s"
 	}
 
-	err = element.InputTime(timeToWrite)
+	timeout, err := time.ParseDuration(scriptTimeoutEnv)
 	if err != nil {
-		return fmt.Errorf("error writing time: %w", err)
-	}
-
-	return nil
-}
-
-func downloadResource(page *rod.Page, action models.TaskAction) (*task.RawMedia, error) {
-	element, err := findElement(page, action)
-	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("error parsing script timeout env: %w", err)
 	}
 
-	return _captureAction(page, element, true)
+	return timeout, nil
 }
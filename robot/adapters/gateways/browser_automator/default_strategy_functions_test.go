@@ -0,0 +1,37 @@
+package browser_automator
+
+import (
+	"automator-go/robot/entities/validation"
+	"github.com/go-rod/rod/lib/input"
+	"slices"
+	"testing"
+)
+
+func TestRodKeys(t *testing.T) {
+	names := make(map[string]bool, len(validation.ModifierKeys)+len(validation.NamedKeys))
+	for name := range validation.ModifierKeys {
+		names[name] = true
+	}
+	for name := range validation.NamedKeys {
+		names[name] = true
+	}
+
+	for name := range names {
+		if _, ok := rodKeys[name]; !ok {
+			t.Errorf("rodKeys has no key %q", name)
+		}
+	}
+	for name := range rodKeys {
+		if !names[name] {
+			t.Errorf("rodKeys has key %q, not accepted by the validation", name)
+		}
+	}
+}
+
+func Test_parseKeys(t *testing.T) {
+	got := parseKeys([]string{"Control", "Shift", "A"})
+	want := []input.Key{input.ControlLeft, input.ShiftLeft, input.Key('a')}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseKeys() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package browser_automator
+
+import (
+	models2 "automator-go/robot/entities/models"
+	"automator-go/robot/usecases/task"
+	"errors"
+	"fmt"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+	"time"
+)
+
+// failureSnapshotTimeout limits how long the snapshot of a failed action
+// takes, the page may be the reason the action failed.
+const failureSnapshotTimeout = 10 * time.Second
+
+// actionFailure is the error of a failed action, with the snapshot of the
+// page taken when it failed.
+type actionFailure struct {
+	err      error
+	snapshot *task.FailureSnapshot
+}
+
+func (f *actionFailure) Error() string {
+	return f.err.Error()
+}
+
+func (f *actionFailure) Unwrap() error {
+	return f.err
+}
+
+// failureSnapshot returns the first snapshot in the error chain, the one of
+// the action that made the task fail.
+func failureSnapshot(err error) *task.FailureSnapshot {
+	var failure *actionFailure
+	if errors.As(err, &failure) {
+		return failure.snapshot
+	}
+
+	return nil
+}
+
+// snapshotFailure wraps the error of the action with a full page screenshot,
+// the serialized DOM and the url of the page. The page must not carry the
+// timeout of the action, it may be the one that expired. Parts of the
+// snapshot that can't be taken are left empty.
+func (at *RodAutomator) snapshotFailure(page *rod.Page, action models2.TaskAction, err error) error {
+	snapshotPage := page.Timeout(failureSnapshotTimeout)
+	defer snapshotPage.CancelTimeout()
+
+	snapshot := &task.FailureSnapshot{ActionId: action.Id}
+	var errs []error
+
+	info, infoErr := snapshotPage.Info()
+	if infoErr != nil {
+		errs = append(errs, fmt.Errorf("error getting page url: %w", infoErr))
+	} else {
+		snapshot.Url = info.URL
+	}
+
+	screenshot, screenshotErr := snapshotPage.Screenshot(true, &proto.PageCaptureScreenshot{
+		Format: proto.PageCaptureScreenshotFormatPng,
+	})
+	if screenshotErr != nil {
+		errs = append(errs, fmt.Errorf("error taking screenshot: %w", screenshotErr))
+	} else {
+		snapshot.Screenshot = screenshot
+	}
+
+	dom, domErr := snapshotPage.HTML()
+	if domErr != nil {
+		errs = append(errs, fmt.Errorf("error serializing dom: %w", domErr))
+	} else {
+		snapshot.Dom = []byte(dom)
+	}
+
+	if snapshotErr := errors.Join(errs...); snapshotErr != nil {
+		at.logger.Warn("Incomplete failure snapshot", zap.String("action", action.Id), zap.Error(snapshotErr))
+	}
+	at.logger.Debug("Took failure snapshot", zap.String("action", action.Id), zap.String("url", snapshot.Url))
+
+	return &actionFailure{err: err, snapshot: snapshot}
+}
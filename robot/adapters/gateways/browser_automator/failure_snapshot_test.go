@@ -0,0 +1,54 @@
+package browser_automator
+
+import (
+	"automator-go/robot/usecases/task"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFailureSnapshot(t *testing.T) {
+	snapshot := &task.FailureSnapshot{ActionId: "1", Url: "https://en.wikipedia.org"}
+	failure := &actionFailure{err: errors.New("element not found"), snapshot: snapshot}
+	postFailure := &actionFailure{err: errors.New("logout not found"), snapshot: &task.FailureSnapshot{ActionId: "logout"}}
+
+	tests := []struct {
+		name string
+		err  error
+		want *task.FailureSnapshot
+	}{
+		{
+			name: "Action failure",
+			err:  failure,
+			want: snapshot,
+		},
+		{
+			name: "Wrapped action failure",
+			err:  fmt.Errorf("error running login task: %w", failure),
+			want: snapshot,
+		},
+		{
+			name: "Task failure followed by a post action failure",
+			err:  errors.Join(failure, fmt.Errorf("error running strategy logout post actions: %w", postFailure)),
+			want: snapshot,
+		},
+		{
+			name: "Failure before the actions",
+			err:  errors.New("error navigating to url"),
+			want: nil,
+		},
+		{
+			name: "No failure",
+			err:  nil,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failureSnapshot(tt.err); got != tt.want {
+				t.Errorf("failureSnapshot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
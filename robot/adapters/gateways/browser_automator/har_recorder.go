@@ -0,0 +1,449 @@
+package browser_automator
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	harVersion    = "1.2"
+	harTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+	// harPageId is the only page of the log, tasks run in a single page.
+	harPageId = "page_1"
+	// harRedacted replaces the sensitive values of the recorded requests.
+	harRedacted = "[REDACTED]"
+)
+
+// harSensitiveHeaders carry credentials, their values are redacted unless the
+// task asks for the full capture.
+var harSensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"x-api-key":           true,
+	"x-auth-token":        true,
+	"x-csrf-token":        true,
+}
+
+// The HAR types follow the HAR 1.2 spec, fields starting with an underscore
+// are custom ones.
+type har struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string      `json:"version"`
+	Creator harCreator  `json:"creator"`
+	Pages   []harPage   `json:"pages"`
+	Entries []*harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harPage struct {
+	StartedDateTime string         `json:"startedDateTime"`
+	Id              string         `json:"id"`
+	Title           string         `json:"title"`
+	PageTimings     harPageTimings `json:"pageTimings"`
+}
+
+type harPageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad"`
+	OnLoad        float64 `json:"onLoad"`
+}
+
+type harEntry struct {
+	Pageref         string      `json:"pageref,omitempty"`
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	ResourceType    string      `json:"_resourceType,omitempty"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	Url         string         `json:"url"`
+	HttpVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HttpVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+// harTimings are in milliseconds, -1 when they don't apply to the request.
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	Dns     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	Ssl     float64 `json:"ssl"`
+}
+
+// harRequestState is a request being recorded. The monotonic timestamps of
+// the events only make sense relative to each other, so the entry time is
+// measured from started.
+type harRequestState struct {
+	entry   *harEntry
+	started proto.MonotonicTime
+	timing  *proto.NetworkResourceTiming
+}
+
+// harRecorder builds a HAR log from the network events of a page. Response
+// bodies are not recorded, only their sizes. The credential headers and the
+// values of the request bodies are redacted, unless fullCapture is set.
+type harRecorder struct {
+	mu          sync.Mutex
+	fullCapture bool
+	pageUrl     string
+	started     time.Time
+	requests    []*harRequestState
+	pending     map[proto.NetworkRequestID]*harRequestState
+	stop        func()
+}
+
+func newHarRecorder(fullCapture bool) *harRecorder {
+	return &harRecorder{
+		fullCapture: fullCapture,
+		started:     time.Now(),
+		pending:     make(map[proto.NetworkRequestID]*harRequestState),
+	}
+}
+
+// startHarRecorder records the network activity of the page until Stop is
+// called. The network domain is enabled before it returns, so no request made
+// afterward is missed.
+func startHarRecorder(page *rod.Page, fullCapture bool) *harRecorder {
+	recorder := newHarRecorder(fullCapture)
+	recorder.stop = listenPage(
+		page,
+		recorder.onRequestWillBeSent,
+		recorder.onResponseReceived,
+		recorder.onLoadingFinished,
+		recorder.onLoadingFailed,
+	)
+
+	return recorder
+}
+
+// Stop ends the recording and returns the HAR file of what was recorded.
+func (r *harRecorder) Stop() ([]byte, error) {
+	r.stop()
+
+	data, err := json.Marshal(r.har())
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling har: %w", err)
+	}
+
+	return data, nil
+}
+
+func (r *harRecorder) onRequestWillBeSent(e *proto.NetworkRequestWillBeSent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Redirects reuse the request id, the redirected request ends there.
+	if previous, ok := r.pending[e.RequestID]; ok && e.RedirectResponse != nil {
+		previous.setResponse(e.RedirectResponse, r.fullCapture)
+		previous.entry.Response.RedirectURL = e.Request.URL
+		previous.finish(e.Timestamp)
+	}
+
+	if r.pageUrl == "" && e.Type == proto.NetworkResourceTypeDocument {
+		r.pageUrl = e.Request.URL
+	}
+
+	request := &harRequestState{
+		started: e.Timestamp,
+		entry: &harEntry{
+			Pageref:         harPageId,
+			StartedDateTime: e.WallTime.Time().UTC().Format(harTimeFormat),
+			Request: harRequest{
+				Method:      e.Request.Method,
+				Url:         e.Request.URL + e.Request.URLFragment,
+				HttpVersion: "HTTP/1.1",
+				Cookies:     []harNameValue{},
+				Headers:     harHeaders(e.Request.Headers, r.fullCapture),
+				QueryString: harQueryString(e.Request.URL),
+				HeadersSize: -1,
+				BodySize:    len(e.Request.PostData),
+			},
+			Response: harResponse{
+				Cookies:     []harNameValue{},
+				Headers:     []harNameValue{},
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+			Timings:      harTimings{Blocked: -1, Dns: -1, Connect: -1, Ssl: -1},
+			ResourceType: string(e.Type),
+		},
+	}
+	if e.Request.HasPostData {
+		mimeType := headerValue(e.Request.Headers, "Content-Type")
+		text := e.Request.PostData
+		if !r.fullCapture {
+			text = redactPostData(mimeType, text)
+		}
+		request.entry.Request.PostData = &harPostData{MimeType: mimeType, Text: text}
+	}
+
+	r.requests = append(r.requests, request)
+	r.pending[e.RequestID] = request
+}
+
+func (r *harRecorder) onResponseReceived(e *proto.NetworkResponseReceived) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if request, ok := r.pending[e.RequestID]; ok {
+		request.setResponse(e.Response, r.fullCapture)
+	}
+}
+
+func (r *harRecorder) onLoadingFinished(e *proto.NetworkLoadingFinished) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if request, ok := r.pending[e.RequestID]; ok {
+		request.entry.Response.BodySize = int(e.EncodedDataLength)
+		request.entry.Response.Content.Size = int(e.EncodedDataLength)
+		request.finish(e.Timestamp)
+		delete(r.pending, e.RequestID)
+	}
+}
+
+func (r *harRecorder) onLoadingFailed(e *proto.NetworkLoadingFailed) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if request, ok := r.pending[e.RequestID]; ok {
+		request.entry.Error = e.ErrorText
+		if e.BlockedReason != "" {
+			request.entry.Error += " (" + string(e.BlockedReason) + ")"
+		}
+		request.finish(e.Timestamp)
+		delete(r.pending, e.RequestID)
+	}
+}
+
+func (rs *harRequestState) setResponse(response *proto.NetworkResponse, fullCapture bool) {
+	entry := rs.entry
+	entry.Response.Status = response.Status
+	entry.Response.StatusText = response.StatusText
+	entry.Response.Headers = harHeaders(response.Headers, fullCapture)
+	entry.Response.Content.MimeType = response.MIMEType
+	entry.Response.RedirectURL = headerValue(response.Headers, "Location")
+	entry.ServerIPAddress = response.RemoteIPAddress
+	if response.Protocol != "" {
+		entry.Request.HttpVersion = strings.ToUpper(response.Protocol)
+		entry.Response.HttpVersion = entry.Request.HttpVersion
+	}
+	if len(response.RequestHeaders) > 0 {
+		// The headers actually sent, with the ones added by the browser.
+		entry.Request.Headers = harHeaders(response.RequestHeaders, fullCapture)
+	}
+	rs.timing = response.Timing
+}
+
+// finish sets the entry timings. Without the resource timing of the response,
+// all the time is accounted as waiting.
+func (rs *harRequestState) finish(finished proto.MonotonicTime) {
+	entry := rs.entry
+	total := math.Max(float64(finished-rs.started)*1000, 0)
+	timings := harTimings{Blocked: -1, Dns: -1, Connect: -1, Ssl: -1, Wait: total}
+
+	if timing := rs.timing; timing != nil {
+		timings.Blocked = math.Max((timing.RequestTime-float64(rs.started))*1000, 0)
+		timings.Dns = timingSpan(timing.DNSStart, timing.DNSEnd)
+		timings.Connect = timingSpan(timing.ConnectStart, timing.ConnectEnd)
+		timings.Ssl = timingSpan(timing.SslStart, timing.SslEnd)
+		timings.Send = math.Max(timing.SendEnd-timing.SendStart, 0)
+		timings.Wait = math.Max(timing.ReceiveHeadersEnd-timing.SendEnd, 0)
+
+		// The ssl time is part of the connect one, it is not added again.
+		elapsed := timings.Blocked + timings.Send + timings.Wait
+		for _, span := range []float64{timings.Dns, timings.Connect} {
+			if span > 0 {
+				elapsed += span
+			}
+		}
+		timings.Receive = math.Max(total-elapsed, 0)
+	}
+
+	entry.Timings = timings
+	entry.Time = math.Max(total, 0)
+}
+
+func timingSpan(start, end float64) float64 {
+	if start < 0 || end < 0 {
+		return -1
+	}
+
+	return end - start
+}
+
+func (r *harRecorder) har() *har {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]*harEntry, 0, len(r.requests))
+	for _, request := range r.requests {
+		entries = append(entries, request.entry)
+	}
+
+	startedDateTime := r.started.UTC().Format(harTimeFormat)
+	if len(entries) > 0 {
+		startedDateTime = entries[0].StartedDateTime
+	}
+
+	return &har{
+		Log: harLog{
+			Version: harVersion,
+			Creator: harCreator{Name: "automator-go", Version: harVersion},
+			Pages: []harPage{{
+				StartedDateTime: startedDateTime,
+				Id:              harPageId,
+				Title:           r.pageUrl,
+				PageTimings:     harPageTimings{OnContentLoad: -1, OnLoad: -1},
+			}},
+			Entries: entries,
+		},
+	}
+}
+
+func harHeaders(headers proto.NetworkHeaders, fullCapture bool) []harNameValue {
+	values := make([]harNameValue, 0, len(headers))
+	for name, value := range headers {
+		headerValue := value.Str()
+		if !fullCapture && harSensitiveHeaders[strings.ToLower(name)] {
+			headerValue = harRedacted
+		}
+		values = append(values, harNameValue{Name: name, Value: headerValue})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+
+	return values
+}
+
+func headerValue(headers proto.NetworkHeaders, name string) string {
+	for headerName, value := range headers {
+		if strings.EqualFold(headerName, name) {
+			return value.Str()
+		}
+	}
+
+	return ""
+}
+
+func harQueryString(rawUrl string) []harNameValue {
+	values := []harNameValue{}
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return values
+	}
+
+	for name, list := range parsed.Query() {
+		for _, value := range list {
+			values = append(values, harNameValue{Name: name, Value: value})
+		}
+	}
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+
+	return values
+}
+
+// redactPostData keeps the field names of form and json bodies, so the
+// request can still be told apart, and redacts their values. Other bodies are
+// redacted whole.
+func redactPostData(mimeType string, text string) string {
+	mimeType = strings.ToLower(mimeType)
+	switch {
+	case strings.Contains(mimeType, "application/x-www-form-urlencoded"):
+		form, err := url.ParseQuery(text)
+		if err != nil {
+			return harRedacted
+		}
+		for name := range form {
+			form[name] = []string{harRedacted}
+		}
+
+		return form.Encode()
+	case strings.Contains(mimeType, "json"):
+		var body interface{}
+		if err := json.Unmarshal([]byte(text), &body); err != nil {
+			return harRedacted
+		}
+		redacted, err := json.Marshal(redactJson(body))
+		if err != nil {
+			return harRedacted
+		}
+
+		return string(redacted)
+	default:
+		return harRedacted
+	}
+}
+
+func redactJson(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = redactJson(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJson(item)
+		}
+		return v
+	default:
+		return harRedacted
+	}
+}
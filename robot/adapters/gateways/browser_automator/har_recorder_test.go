@@ -0,0 +1,229 @@
+package browser_automator
+
+import (
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/gson"
+	"net/http"
+	"testing"
+)
+
+func TestHarRecorder(t *testing.T) {
+	documentRequest := func(id proto.NetworkRequestID, url string, redirect *proto.NetworkResponse, at proto.MonotonicTime) *proto.NetworkRequestWillBeSent {
+		return &proto.NetworkRequestWillBeSent{
+			RequestID: id,
+			Request: &proto.NetworkRequest{
+				URL:     url,
+				Method:  "GET",
+				Headers: proto.NetworkHeaders{"Accept": gson.New("text/html")},
+			},
+			Timestamp:        at,
+			WallTime:         proto.TimeSinceEpoch(1700000000 + float64(at)),
+			RedirectResponse: redirect,
+			Type:             proto.NetworkResourceTypeDocument,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		record  func(r *harRecorder)
+		want    []harEntry
+		pageUrl string
+	}{
+		{
+			name: "Finished request",
+			record: func(r *harRecorder) {
+				r.onRequestWillBeSent(documentRequest("1", "https://en.wikipedia.org/w/index.php?search=sinatra", nil, 10))
+				r.onResponseReceived(&proto.NetworkResponseReceived{
+					RequestID: "1",
+					Response: &proto.NetworkResponse{
+						Status:     200,
+						StatusText: "OK",
+						Headers:    proto.NetworkHeaders{"Content-Type": gson.New("text/html")},
+						MIMEType:   "text/html",
+						Protocol:   "http/1.1",
+					},
+				})
+				r.onLoadingFinished(&proto.NetworkLoadingFinished{RequestID: "1", Timestamp: 10.25, EncodedDataLength: 2048})
+			},
+			want: []harEntry{{
+				Time:     250,
+				Request:  harRequest{Method: "GET", Url: "https://en.wikipedia.org/w/index.php?search=sinatra", QueryString: []harNameValue{{Name: "search", Value: "sinatra"}}},
+				Response: harResponse{Status: 200, HttpVersion: "HTTP/1.1", BodySize: 2048, Content: harContent{Size: 2048, MimeType: "text/html"}},
+			}},
+			pageUrl: "https://en.wikipedia.org/w/index.php?search=sinatra",
+		},
+		{
+			name: "Redirected request",
+			record: func(r *harRecorder) {
+				r.onRequestWillBeSent(documentRequest("1", "http://wikipedia.org/", nil, 10))
+				r.onRequestWillBeSent(documentRequest("1", "https://www.wikipedia.org/", &proto.NetworkResponse{
+					Status:  301,
+					Headers: proto.NetworkHeaders{"Location": gson.New("https://www.wikipedia.org/")},
+				}, 10.1))
+				r.onResponseReceived(&proto.NetworkResponseReceived{RequestID: "1", Response: &proto.NetworkResponse{Status: 200}})
+				r.onLoadingFinished(&proto.NetworkLoadingFinished{RequestID: "1", Timestamp: 10.2, EncodedDataLength: 100})
+			},
+			want: []harEntry{
+				{
+					Time:     100,
+					Request:  harRequest{Method: "GET", Url: "http://wikipedia.org/", QueryString: []harNameValue{}},
+					Response: harResponse{Status: 301, RedirectURL: "https://www.wikipedia.org/", BodySize: -1},
+				},
+				{
+					Time:     100,
+					Request:  harRequest{Method: "GET", Url: "https://www.wikipedia.org/", QueryString: []harNameValue{}},
+					Response: harResponse{Status: 200, BodySize: 100, Content: harContent{Size: 100}},
+				},
+			},
+			pageUrl: "http://wikipedia.org/",
+		},
+		{
+			name: "Blocked request",
+			record: func(r *harRecorder) {
+				r.onRequestWillBeSent(documentRequest("1", "https://ad.doubleclick.net/", nil, 10))
+				r.onLoadingFailed(&proto.NetworkLoadingFailed{RequestID: "1", Timestamp: 10, ErrorText: "net::ERR_BLOCKED_BY_CLIENT"})
+			},
+			want: []harEntry{{
+				Request:  harRequest{Method: "GET", Url: "https://ad.doubleclick.net/", QueryString: []harNameValue{}},
+				Response: harResponse{BodySize: -1},
+				Error:    "net::ERR_BLOCKED_BY_CLIENT",
+			}},
+			pageUrl: "https://ad.doubleclick.net/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newHarRecorder(false)
+			tt.record(recorder)
+			log := recorder.har().Log
+
+			if log.Version != harVersion || len(log.Pages) != 1 || log.Pages[0].Title != tt.pageUrl {
+				t.Errorf("har log = %v, %v, want version %v and page %v", log.Version, log.Pages, harVersion, tt.pageUrl)
+			}
+			if len(log.Entries) != len(tt.want) {
+				t.Fatalf("har entries = %d, want %d", len(log.Entries), len(tt.want))
+			}
+
+			for i, want := range tt.want {
+				got := log.Entries[i]
+				if int(got.Time+0.5) != int(want.Time) {
+					t.Errorf("entry %d time = %v, want %v", i, got.Time, want.Time)
+				}
+				if got.Request.Method != want.Request.Method || got.Request.Url != want.Request.Url {
+					t.Errorf("entry %d request = %v %v, want %v %v", i, got.Request.Method, got.Request.Url, want.Request.Method, want.Request.Url)
+				}
+				if len(got.Request.QueryString) != len(want.Request.QueryString) {
+					t.Errorf("entry %d query string = %v, want %v", i, got.Request.QueryString, want.Request.QueryString)
+				}
+				if got.Response.Status != want.Response.Status ||
+					got.Response.RedirectURL != want.Response.RedirectURL ||
+					got.Response.BodySize != want.Response.BodySize ||
+					got.Response.Content != want.Response.Content {
+					t.Errorf("entry %d response = %+v, want %+v", i, got.Response, want.Response)
+				}
+				if want.Response.HttpVersion != "" && got.Response.HttpVersion != want.Response.HttpVersion {
+					t.Errorf("entry %d http version = %v, want %v", i, got.Response.HttpVersion, want.Response.HttpVersion)
+				}
+				if got.Error != want.Error {
+					t.Errorf("entry %d error = %v, want %v", i, got.Error, want.Error)
+				}
+				if got.Timings.Send < 0 || got.Timings.Wait < 0 || got.Timings.Receive < 0 {
+					t.Errorf("entry %d timings = %+v, send, wait and receive must not be negative", i, got.Timings)
+				}
+			}
+		})
+	}
+}
+
+func TestHarRecorderRedaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		fullCapture bool
+		contentType string
+		postData    string
+		wantHeaders map[string]string
+		wantBody    string
+	}{
+		{
+			name:        "Form body",
+			contentType: "application/x-www-form-urlencoded",
+			postData:    "user=admin&password=secret",
+			wantHeaders: map[string]string{"Authorization": harRedacted, "Cookie": harRedacted, "Set-Cookie": harRedacted, "Accept": "text/html"},
+			wantBody:    "password=%5BREDACTED%5D&user=%5BREDACTED%5D",
+		},
+		{
+			name:        "Json body",
+			contentType: "application/json; charset=utf-8",
+			postData:    `{"user":"admin","tokens":["a","b"],"remember":true}`,
+			wantHeaders: map[string]string{"Authorization": harRedacted, "Cookie": harRedacted, "Set-Cookie": harRedacted, "Accept": "text/html"},
+			wantBody:    `{"remember":"[REDACTED]","tokens":["[REDACTED]","[REDACTED]"],"user":"[REDACTED]"}`,
+		},
+		{
+			name:        "Other body",
+			contentType: "text/plain",
+			postData:    "admin:secret",
+			wantHeaders: map[string]string{"Authorization": harRedacted, "Cookie": harRedacted, "Set-Cookie": harRedacted, "Accept": "text/html"},
+			wantBody:    harRedacted,
+		},
+		{
+			name:        "Full capture",
+			fullCapture: true,
+			contentType: "application/x-www-form-urlencoded",
+			postData:    "user=admin&password=secret",
+			wantHeaders: map[string]string{"Authorization": "Basic YWRtaW46c2VjcmV0", "Cookie": "session=1", "Set-Cookie": "session=2", "Accept": "text/html"},
+			wantBody:    "user=admin&password=secret",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newHarRecorder(tt.fullCapture)
+			recorder.onRequestWillBeSent(&proto.NetworkRequestWillBeSent{
+				RequestID: "1",
+				Request: &proto.NetworkRequest{
+					URL:    "https://example.org/login",
+					Method: "POST",
+					Headers: proto.NetworkHeaders{
+						"Accept":        gson.New("text/html"),
+						"Authorization": gson.New("Basic YWRtaW46c2VjcmV0"),
+						"Cookie":        gson.New("session=1"),
+						"Content-Type":  gson.New(tt.contentType),
+					},
+					HasPostData: true,
+					PostData:    tt.postData,
+				},
+				Timestamp: 10,
+				Type:      proto.NetworkResourceTypeDocument,
+			})
+			recorder.onResponseReceived(&proto.NetworkResponseReceived{
+				RequestID: "1",
+				Response: &proto.NetworkResponse{
+					Status:  200,
+					Headers: proto.NetworkHeaders{"set-cookie": gson.New("session=2")},
+				},
+			})
+			recorder.onLoadingFinished(&proto.NetworkLoadingFinished{RequestID: "1", Timestamp: 10.1})
+
+			entries := recorder.har().Log.Entries
+			if len(entries) != 1 {
+				t.Fatalf("har entries = %d, want 1", len(entries))
+			}
+			entry := entries[0]
+
+			headers := map[string]string{}
+			for _, header := range append(entry.Request.Headers, entry.Response.Headers...) {
+				headers[http.CanonicalHeaderKey(header.Name)] = header.Value
+			}
+			for name, want := range tt.wantHeaders {
+				if headers[name] != want {
+					t.Errorf("header %s = %q, want %q", name, headers[name], want)
+				}
+			}
+
+			if entry.Request.PostData == nil || entry.Request.PostData.Text != tt.wantBody {
+				t.Errorf("post data = %+v, want %q", entry.Request.PostData, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,466 @@
+package browser_automator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// manifestKind tells the streaming manifests apart from the media files.
+type manifestKind string
+
+const (
+	hlsManifest  manifestKind = "hls"
+	dashManifest manifestKind = "dash"
+)
+
+// maxManifestSize limits the manifests read in memory, they only list urls.
+const maxManifestSize = 5 << 20
+
+// maxManifestSegments limits the segments listed by a manifest. Templates and
+// timelines expand to any number of urls, a few bytes of an untrusted manifest
+// would take all the memory otherwise.
+const maxManifestSegments = 10000
+
+var errTooManySegments = fmt.Errorf("stream has more than %d segments", maxManifestSegments)
+
+// detectManifest returns the kind of manifest of the content, or an empty kind
+// for media files. Servers often send manifests as plain text or xml, so the
+// content is checked besides the type.
+func detectManifest(contentType string, head []byte) manifestKind {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch strings.ToLower(mediaType) {
+	case "application/vnd.apple.mpegurl", "application/x-mpegurl", "audio/mpegurl", "audio/x-mpegurl":
+		return hlsManifest
+	case "application/dash+xml":
+		return dashManifest
+	}
+
+	head = bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if bytes.HasPrefix(head, []byte("#EXTM3U")) {
+		return hlsManifest
+	}
+	if bytes.HasPrefix(head, []byte("<")) && bytes.Contains(head, []byte("<MPD")) {
+		return dashManifest
+	}
+
+	return ""
+}
+
+// mediaSegments are the urls of the segments of a stream, in play order. The
+// initialization segment, when there is one, goes first.
+type mediaSegments struct {
+	urls []string
+	ext  string
+	mime string
+}
+
+// hlsPlaylist is a parsed HLS playlist. Master playlists only have variants,
+// media playlists only segments.
+type hlsPlaylist struct {
+	// variant is the url of the variant stream with the highest bandwidth.
+	variant  string
+	segments mediaSegments
+}
+
+var hlsAttributePattern = regexp.MustCompile(`([A-Z0-9-]+)=("[^"]*"|[^,]*)`)
+
+func hlsAttributes(tag string) map[string]string {
+	attributes := make(map[string]string)
+	for _, match := range hlsAttributePattern.FindAllStringSubmatch(tag, -1) {
+		attributes[match[1]] = strings.Trim(match[2], `"`)
+	}
+
+	return attributes
+}
+
+// parseHlsPlaylist reads a master or media playlist, resolving its urls
+// against the playlist url. Encrypted and byte range segments can't be joined
+// into a playable file, they are rejected.
+func parseHlsPlaylist(manifest []byte, base *url.URL) (*hlsPlaylist, error) {
+	playlist := &hlsPlaylist{segments: mediaSegments{ext: "ts", mime: "video/mp2t"}}
+	resolve := func(reference string) (string, error) {
+		resolved, err := base.Parse(strings.TrimSpace(reference))
+		if err != nil {
+			return "", fmt.Errorf("error parsing playlist url %q: %w", reference, err)
+		}
+
+		return resolved.String(), nil
+	}
+
+	bestBandwidth := -1
+	variantNext := false
+	nextBandwidth := 0
+	scanner := bufio.NewScanner(bytes.NewReader(manifest))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		tag, value, _ := strings.Cut(line, ":")
+		switch {
+		case tag == "#EXT-X-STREAM-INF":
+			variantNext = true
+			nextBandwidth, _ = strconv.Atoi(hlsAttributes(value)["BANDWIDTH"])
+		case tag == "#EXT-X-KEY":
+			if method := hlsAttributes(value)["METHOD"]; method != "" && method != "NONE" {
+				return nil, fmt.Errorf("hls stream is encrypted with %s", method)
+			}
+		case tag == "#EXT-X-BYTERANGE":
+			return nil, errors.New("hls byte range segments are not supported")
+		case tag == "#EXT-X-MAP":
+			attributes := hlsAttributes(value)
+			if _, ok := attributes["BYTERANGE"]; ok {
+				return nil, errors.New("hls byte range segments are not supported")
+			}
+			initUrl, err := resolve(attributes["URI"])
+			if err != nil {
+				return nil, err
+			}
+			if len(playlist.segments.urls) >= maxManifestSegments {
+				return nil, errTooManySegments
+			}
+			// Fragmented mp4 streams have an initialization segment.
+			playlist.segments.urls = append(playlist.segments.urls, initUrl)
+			playlist.segments.ext, playlist.segments.mime = "mp4", "video/mp4"
+		case strings.HasPrefix(line, "#"):
+			continue
+		case variantNext:
+			variantNext = false
+			if nextBandwidth > bestBandwidth {
+				variantUrl, err := resolve(line)
+				if err != nil {
+					return nil, err
+				}
+				playlist.variant, bestBandwidth = variantUrl, nextBandwidth
+			}
+		default:
+			if len(playlist.segments.urls) >= maxManifestSegments {
+				return nil, errTooManySegments
+			}
+			segmentUrl, err := resolve(line)
+			if err != nil {
+				return nil, err
+			}
+			playlist.segments.urls = append(playlist.segments.urls, segmentUrl)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading hls playlist: %w", err)
+	}
+
+	if playlist.variant == "" && len(playlist.segments.urls) == 0 {
+		return nil, errors.New("hls playlist has no segments")
+	}
+
+	return playlist, nil
+}
+
+type dashMpd struct {
+	Type     string       `xml:"type,attr"`
+	Duration string       `xml:"mediaPresentationDuration,attr"`
+	BaseUrl  string       `xml:"BaseURL"`
+	Periods  []dashPeriod `xml:"Period"`
+}
+
+type dashPeriod struct {
+	Duration       string              `xml:"duration,attr"`
+	BaseUrl        string              `xml:"BaseURL"`
+	AdaptationSets []dashAdaptationSet `xml:"AdaptationSet"`
+}
+
+type dashAdaptationSet struct {
+	MimeType        string               `xml:"mimeType,attr"`
+	ContentType     string               `xml:"contentType,attr"`
+	BaseUrl         string               `xml:"BaseURL"`
+	SegmentTemplate *dashSegmentTemplate `xml:"SegmentTemplate"`
+	SegmentList     *dashSegmentList     `xml:"SegmentList"`
+	Representations []dashRepresentation `xml:"Representation"`
+}
+
+type dashRepresentation struct {
+	Id              string               `xml:"id,attr"`
+	Bandwidth       int                  `xml:"bandwidth,attr"`
+	MimeType        string               `xml:"mimeType,attr"`
+	BaseUrl         string               `xml:"BaseURL"`
+	SegmentTemplate *dashSegmentTemplate `xml:"SegmentTemplate"`
+	SegmentList     *dashSegmentList     `xml:"SegmentList"`
+}
+
+type dashSegmentTemplate struct {
+	Initialization string                `xml:"initialization,attr"`
+	Media          string                `xml:"media,attr"`
+	StartNumber    *int                  `xml:"startNumber,attr"`
+	Timescale      int                   `xml:"timescale,attr"`
+	Duration       int                   `xml:"duration,attr"`
+	Timeline       []dashTimelineSegment `xml:"SegmentTimeline>S"`
+}
+
+type dashTimelineSegment struct {
+	Time     *int64 `xml:"t,attr"`
+	Duration int64  `xml:"d,attr"`
+	Repeat   int    `xml:"r,attr"`
+}
+
+type dashSegmentList struct {
+	Initialization *struct {
+		SourceUrl string `xml:"sourceURL,attr"`
+	} `xml:"Initialization"`
+	SegmentUrls []struct {
+		Media string `xml:"media,attr"`
+	} `xml:"SegmentURL"`
+}
+
+var dashTemplatePattern = regexp.MustCompile(`\$(RepresentationID|Number|Bandwidth|Time)(%0(\d+)d)?\$`)
+
+// parseDashManifest picks the video representation with the highest bandwidth
+// of the first period, or the audio one when there is no video, and lists its
+// segments. Live manifests change while they are read, they are rejected.
+func parseDashManifest(manifest []byte, base *url.URL) (*mediaSegments, error) {
+	var mpd dashMpd
+	if err := xml.Unmarshal(manifest, &mpd); err != nil {
+		return nil, fmt.Errorf("error parsing dash manifest: %w", err)
+	}
+	if mpd.Type == "dynamic" {
+		return nil, errors.New("live dash streams are not supported")
+	}
+	if len(mpd.Periods) == 0 {
+		return nil, errors.New("dash manifest has no periods")
+	}
+	period := mpd.Periods[0]
+
+	var set *dashAdaptationSet
+	var representation *dashRepresentation
+	bestScore := -1
+	for i := range period.AdaptationSets {
+		candidateSet := &period.AdaptationSets[i]
+		for j := range candidateSet.Representations {
+			candidate := &candidateSet.Representations[j]
+			mimeType := candidate.MimeType
+			if mimeType == "" {
+				mimeType = candidateSet.MimeType
+			}
+
+			// Any video beats any audio, bandwidth breaks the ties.
+			score := candidate.Bandwidth
+			if strings.HasPrefix(mimeType, "video/") || candidateSet.ContentType == "video" {
+				score += math.MaxInt32
+			} else if !strings.HasPrefix(mimeType, "audio/") && candidateSet.ContentType != "audio" {
+				continue
+			}
+			if score > bestScore {
+				set, representation, bestScore = candidateSet, candidate, score
+			}
+		}
+	}
+	if representation == nil {
+		return nil, errors.New("dash manifest has no video or audio representation")
+	}
+
+	for _, baseUrl := range []string{mpd.BaseUrl, period.BaseUrl, set.BaseUrl, representation.BaseUrl} {
+		if strings.TrimSpace(baseUrl) == "" {
+			continue
+		}
+		resolved, err := base.Parse(strings.TrimSpace(baseUrl))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing dash base url %q: %w", baseUrl, err)
+		}
+		base = resolved
+	}
+
+	mimeType := representation.MimeType
+	if mimeType == "" {
+		mimeType = set.MimeType
+	}
+	segments := &mediaSegments{ext: "mp4", mime: mimeType}
+	if strings.HasSuffix(mimeType, "/webm") {
+		segments.ext = "webm"
+	}
+	if segments.mime == "" {
+		segments.mime = "video/mp4"
+	}
+
+	resolve := func(reference string) error {
+		if len(segments.urls) >= maxManifestSegments {
+			return errTooManySegments
+		}
+		resolved, err := base.Parse(reference)
+		if err != nil {
+			return fmt.Errorf("error parsing dash segment url %q: %w", reference, err)
+		}
+		segments.urls = append(segments.urls, resolved.String())
+
+		return nil
+	}
+
+	template := representation.SegmentTemplate
+	if template == nil {
+		template = set.SegmentTemplate
+	}
+	list := representation.SegmentList
+	if list == nil {
+		list = set.SegmentList
+	}
+
+	switch {
+	case template != nil:
+		duration := period.Duration
+		if duration == "" {
+			duration = mpd.Duration
+		}
+		references, err := dashTemplateUrls(template, representation, duration)
+		if err != nil {
+			return nil, err
+		}
+		for _, reference := range references {
+			if err = resolve(reference); err != nil {
+				return nil, err
+			}
+		}
+	case list != nil:
+		if list.Initialization != nil && list.Initialization.SourceUrl != "" {
+			if err := resolve(list.Initialization.SourceUrl); err != nil {
+				return nil, err
+			}
+		}
+		for _, segmentUrl := range list.SegmentUrls {
+			if err := resolve(segmentUrl.Media); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		// Representations without segments are a single file at their base url.
+		segments.urls = append(segments.urls, base.String())
+	}
+
+	if len(segments.urls) == 0 {
+		return nil, errors.New("dash representation has no segments")
+	}
+
+	return segments, nil
+}
+
+// dashTemplateUrls expands the segment template, numbering the segments by
+// its timeline or, without one, by the duration of the period.
+func dashTemplateUrls(template *dashSegmentTemplate, representation *dashRepresentation, periodDuration string) ([]string, error) {
+	expand := func(pattern string, number int, time int64) string {
+		return dashTemplatePattern.ReplaceAllStringFunc(pattern, func(match string) string {
+			parts := dashTemplatePattern.FindStringSubmatch(match)
+			var value string
+			switch parts[1] {
+			case "RepresentationID":
+				return representation.Id
+			case "Number":
+				value = strconv.Itoa(number)
+			case "Bandwidth":
+				value = strconv.Itoa(representation.Bandwidth)
+			case "Time":
+				value = strconv.FormatInt(time, 10)
+			}
+			if width, err := strconv.Atoi(parts[3]); err == nil && len(value) < width {
+				value = strings.Repeat("0", width-len(value)) + value
+			}
+
+			return value
+		})
+	}
+
+	if template.Media == "" {
+		return nil, errors.New("dash segment template has no media")
+	}
+
+	urls := make([]string, 0)
+	if template.Initialization != "" {
+		urls = append(urls, expand(template.Initialization, 0, 0))
+	}
+
+	number := 1
+	if template.StartNumber != nil {
+		number = *template.StartNumber
+	}
+
+	if len(template.Timeline) > 0 {
+		// The segments are counted before expanding them, repeats are not bounded.
+		var count int64
+		for _, segment := range template.Timeline {
+			if segment.Repeat < 0 {
+				return nil, errors.New("open ended dash segment timelines are not supported")
+			}
+			count += int64(segment.Repeat) + 1
+			if count > maxManifestSegments {
+				return nil, errTooManySegments
+			}
+		}
+
+		var time int64
+		for _, segment := range template.Timeline {
+			if segment.Time != nil {
+				time = *segment.Time
+			}
+			for i := 0; i <= segment.Repeat; i++ {
+				urls = append(urls, expand(template.Media, number, time))
+				number++
+				time += segment.Duration
+			}
+		}
+
+		return urls, nil
+	}
+
+	if template.Duration <= 0 {
+		return nil, errors.New("dash segment template has no duration or timeline")
+	}
+	seconds, err := parseIsoDuration(periodDuration)
+	if err != nil {
+		return nil, err
+	}
+	timescale := template.Timescale
+	if timescale <= 0 {
+		timescale = 1
+	}
+	segmentSeconds := float64(template.Duration) / float64(timescale)
+	segmentCount := math.Ceil(seconds / segmentSeconds)
+	if segmentCount > maxManifestSegments {
+		return nil, errTooManySegments
+	}
+	count := int(segmentCount)
+	for i := 0; i < count; i++ {
+		urls = append(urls, expand(template.Media, number+i, int64(i*template.Duration)))
+	}
+
+	return urls, nil
+}
+
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// parseIsoDuration returns the seconds of an ISO 8601 duration like PT1M30.5S,
+// as found in the dash manifests.
+func parseIsoDuration(duration string) (float64, error) {
+	match := isoDurationPattern.FindStringSubmatch(strings.TrimSpace(duration))
+	if match == nil || duration == "P" || duration == "PT" {
+		return 0, fmt.Errorf("invalid dash duration %q", duration)
+	}
+
+	var seconds float64
+	for i, unit := range []float64{86400, 3600, 60, 1} {
+		if match[i+1] == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(match[i+1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid dash duration %q: %w", duration, err)
+		}
+		seconds += value * unit
+	}
+
+	return seconds, nil
+}
@@ -0,0 +1,306 @@
+package browser_automator
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectManifest(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		head        string
+		want        manifestKind
+	}{
+		{name: "Hls type", contentType: "application/vnd.apple.mpegurl; charset=utf-8", want: hlsManifest},
+		{name: "Hls as text", contentType: "text/plain", head: "#EXTM3U\n#EXT-X-VERSION:3", want: hlsManifest},
+		{name: "Dash type", contentType: "application/dash+xml", want: dashManifest},
+		{name: "Dash as xml", contentType: "application/xml", head: `<?xml version="1.0"?><MPD type="static">`, want: dashManifest},
+		{name: "Video", contentType: "video/mp4", head: "\x00\x00\x00\x18ftypmp42", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectManifest(tt.contentType, []byte(tt.head)); got != tt.want {
+				t.Errorf("detectManifest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHlsPlaylist(t *testing.T) {
+	base, _ := url.Parse("https://cdn.example.com/videos/intro/master.m3u8")
+
+	tests := []struct {
+		name     string
+		manifest string
+		want     *hlsPlaylist
+		wantErr  bool
+	}{
+		{
+			name: "Master playlist",
+			manifest: `#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360,CODECS="avc1.4d401e,mp4a.40.2"
+360p/index.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=2500000,RESOLUTION=1280x720
+720p/index.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=1200000,RESOLUTION=854x480
+480p/index.m3u8
+`,
+			want: &hlsPlaylist{
+				variant:  "https://cdn.example.com/videos/intro/720p/index.m3u8",
+				segments: mediaSegments{ext: "ts", mime: "video/mp2t"},
+			},
+		},
+		{
+			name: "Media playlist",
+			manifest: `#EXTM3U
+#EXT-X-TARGETDURATION:10
+#EXTINF:10.0,
+segment0.ts
+#EXTINF:10.0,
+/videos/intro/segment1.ts
+#EXT-X-ENDLIST
+`,
+			want: &hlsPlaylist{segments: mediaSegments{
+				urls: []string{"https://cdn.example.com/videos/intro/segment0.ts", "https://cdn.example.com/videos/intro/segment1.ts"},
+				ext:  "ts",
+				mime: "video/mp2t",
+			}},
+		},
+		{
+			name: "Fragmented mp4",
+			manifest: `#EXTM3U
+#EXT-X-MAP:URI="init.mp4"
+#EXTINF:6.0,
+chunk0.m4s
+`,
+			want: &hlsPlaylist{segments: mediaSegments{
+				urls: []string{"https://cdn.example.com/videos/intro/init.mp4", "https://cdn.example.com/videos/intro/chunk0.m4s"},
+				ext:  "mp4",
+				mime: "video/mp4",
+			}},
+		},
+		{
+			name: "Encrypted",
+			manifest: `#EXTM3U
+#EXT-X-KEY:METHOD=AES-128,URI="key.bin"
+#EXTINF:10.0,
+segment0.ts
+`,
+			wantErr: true,
+		},
+		{
+			name: "Byte ranges",
+			manifest: `#EXTM3U
+#EXTINF:10.0,
+#EXT-X-BYTERANGE:75232@0
+video.ts
+`,
+			wantErr: true,
+		},
+		{
+			name:     "Without segments",
+			manifest: "#EXTM3U\n#EXT-X-ENDLIST\n",
+			wantErr:  true,
+		},
+		{
+			name:     "Too many segments",
+			manifest: "#EXTM3U\n" + strings.Repeat("#EXTINF:1,\ns.ts\n", maxManifestSegments+1),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseHlsPlaylist([]byte(tt.manifest), base)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseHlsPlaylist() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseHlsPlaylist() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDashManifest(t *testing.T) {
+	base, _ := url.Parse("https://cdn.example.com/videos/intro/manifest.mpd")
+
+	tests := []struct {
+		name     string
+		manifest string
+		want     *mediaSegments
+		wantErr  bool
+	}{
+		{
+			name: "Template with timeline",
+			manifest: `<?xml version="1.0"?>
+<MPD type="static" mediaPresentationDuration="PT12S">
+  <Period>
+    <AdaptationSet contentType="audio" mimeType="audio/mp4">
+      <Representation id="audio" bandwidth="128000"/>
+    </AdaptationSet>
+    <AdaptationSet mimeType="video/mp4">
+      <SegmentTemplate initialization="$RepresentationID$/init.mp4" media="$RepresentationID$/$Time$.m4s" timescale="1000">
+        <SegmentTimeline>
+          <S t="0" d="4000" r="1"/>
+          <S d="4000"/>
+        </SegmentTimeline>
+      </SegmentTemplate>
+      <Representation id="360p" bandwidth="800000"/>
+      <Representation id="720p" bandwidth="2500000"/>
+    </AdaptationSet>
+  </Period>
+</MPD>`,
+			want: &mediaSegments{
+				urls: []string{
+					"https://cdn.example.com/videos/intro/720p/init.mp4",
+					"https://cdn.example.com/videos/intro/720p/0.m4s",
+					"https://cdn.example.com/videos/intro/720p/4000.m4s",
+					"https://cdn.example.com/videos/intro/720p/8000.m4s",
+				},
+				ext:  "mp4",
+				mime: "video/mp4",
+			},
+		},
+		{
+			name: "Template with duration",
+			manifest: `<MPD mediaPresentationDuration="PT9.5S">
+  <Period>
+    <AdaptationSet mimeType="video/webm">
+      <Representation id="v" bandwidth="1000">
+        <SegmentTemplate media="seg_$Number%03d$.webm" startNumber="0" duration="4" timescale="1"/>
+      </Representation>
+    </AdaptationSet>
+  </Period>
+</MPD>`,
+			want: &mediaSegments{
+				urls: []string{
+					"https://cdn.example.com/videos/intro/seg_000.webm",
+					"https://cdn.example.com/videos/intro/seg_001.webm",
+					"https://cdn.example.com/videos/intro/seg_002.webm",
+				},
+				ext:  "webm",
+				mime: "video/webm",
+			},
+		},
+		{
+			name: "Segment list with base url",
+			manifest: `<MPD mediaPresentationDuration="PT8S">
+  <BaseURL>https://media.example.com/intro/</BaseURL>
+  <Period>
+    <AdaptationSet mimeType="video/mp4">
+      <Representation id="v" bandwidth="1000">
+        <SegmentList>
+          <Initialization sourceURL="init.mp4"/>
+          <SegmentURL media="1.m4s"/>
+          <SegmentURL media="2.m4s"/>
+        </SegmentList>
+      </Representation>
+    </AdaptationSet>
+  </Period>
+</MPD>`,
+			want: &mediaSegments{
+				urls: []string{
+					"https://media.example.com/intro/init.mp4",
+					"https://media.example.com/intro/1.m4s",
+					"https://media.example.com/intro/2.m4s",
+				},
+				ext:  "mp4",
+				mime: "video/mp4",
+			},
+		},
+		{
+			name: "Single file representation",
+			manifest: `<MPD mediaPresentationDuration="PT8S">
+  <Period>
+    <AdaptationSet mimeType="video/mp4">
+      <Representation id="v" bandwidth="1000"><BaseURL>intro_720p.mp4</BaseURL></Representation>
+    </AdaptationSet>
+  </Period>
+</MPD>`,
+			want: &mediaSegments{
+				urls: []string{"https://cdn.example.com/videos/intro/intro_720p.mp4"},
+				ext:  "mp4",
+				mime: "video/mp4",
+			},
+		},
+		{
+			name:     "Live",
+			manifest: `<MPD type="dynamic"><Period/></MPD>`,
+			wantErr:  true,
+		},
+		{
+			name: "Timeline with too many repeats",
+			manifest: `<MPD>
+  <Period>
+    <AdaptationSet mimeType="video/mp4">
+      <Representation id="v" bandwidth="1000">
+        <SegmentTemplate media="$Number$.m4s"><SegmentTimeline><S d="1" r="1000000000"/></SegmentTimeline></SegmentTemplate>
+      </Representation>
+    </AdaptationSet>
+  </Period>
+</MPD>`,
+			wantErr: true,
+		},
+		{
+			name: "Duration with too many segments",
+			manifest: `<MPD mediaPresentationDuration="PT100000000H">
+  <Period>
+    <AdaptationSet mimeType="video/mp4">
+      <Representation id="v" bandwidth="1000">
+        <SegmentTemplate media="$Number$.m4s" duration="1" timescale="1000"/>
+      </Representation>
+    </AdaptationSet>
+  </Period>
+</MPD>`,
+			wantErr: true,
+		},
+		{
+			name:     "Without representations",
+			manifest: `<MPD><Period><AdaptationSet mimeType="text/vtt"/></Period></MPD>`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDashManifest([]byte(tt.manifest), base)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDashManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDashManifest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIsoDuration(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     float64
+		wantErr  bool
+	}{
+		{duration: "PT1M30.5S", want: 90.5},
+		{duration: "PT1H", want: 3600},
+		{duration: "P1DT1S", want: 86401},
+		{duration: "PT", wantErr: true},
+		{duration: "90s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duration, func(t *testing.T) {
+			got, err := parseIsoDuration(tt.duration)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseIsoDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseIsoDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package browser_automator
+
+import (
+	models2 "automator-go/robot/entities/models"
+	"fmt"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// networkRule is a rule ready to match requests, with its pattern compiled
+// and its mock fixture read.
+type networkRule struct {
+	rule    models2.NetworkRule
+	pattern *regexp.Regexp
+	body    []byte
+}
+
+// compileNetworkRules reads the mock fixtures from fixturesDir.
+func compileNetworkRules(rules []models2.NetworkRule, fixturesDir string) ([]networkRule, error) {
+	compiled := make([]networkRule, 0, len(rules))
+	for _, rule := range rules {
+		pattern, err := regexp.Compile(proto.PatternToReg(rule.Url))
+		if err != nil {
+			return nil, fmt.Errorf("error compiling network rule url %q: %w", rule.Url, err)
+		}
+
+		networkRule := networkRule{rule: rule, pattern: pattern}
+		if rule.Mock != nil {
+			networkRule.body = []byte(rule.Mock.Body)
+			if rule.Mock.Fixture != "" {
+				networkRule.body, err = readFixture(fixturesDir, rule.Mock.Fixture)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		compiled = append(compiled, networkRule)
+	}
+
+	return compiled, nil
+}
+
+// readFixture reads a fixture of the fixtures directory. The tasks come from
+// the queue, so the fixtures can't point outside of it, not even through
+// symlinks.
+func readFixture(fixturesDir string, fixture string) ([]byte, error) {
+	root, err := filepath.Abs(fixturesDir)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error resolving mock fixtures dir: %w", err)
+	}
+
+	path, err := filepath.EvalSymlinks(filepath.Join(root, filepath.Clean(string(filepath.Separator)+fixture)))
+	if err != nil {
+		return nil, fmt.Errorf("error resolving mock fixture %q: %w", fixture, err)
+	}
+	if !strings.HasPrefix(path, root+string(filepath.Separator)) {
+		return nil, fmt.Errorf("mock fixture %q is outside of the fixtures dir", fixture)
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading mock fixture: %w", err)
+	}
+
+	return body, nil
+}
+
+func (nr *networkRule) matches(url string, resourceType proto.NetworkResourceType) bool {
+	if nr.rule.ResourceType != "" && nr.rule.ResourceType != string(resourceType) {
+		return false
+	}
+
+	return nr.pattern.MatchString(url)
+}
+
+// applyNetworkRules intercepts every request of the page, applying the first
+// rule matching it. Requests matching none continue untouched. The returned
+// router must be stopped when the page is done.
+func applyNetworkRules(page *rod.Page, rules []networkRule) (*rod.HijackRouter, error) {
+	router := page.HijackRequests()
+	err := router.Add("*", "", func(ctx *rod.Hijack) {
+		handleRequest(ctx, rules)
+	})
+	if err != nil {
+		_ = router.Stop()
+		return nil, fmt.Errorf("error intercepting requests: %w", err)
+	}
+	go router.Run()
+
+	return router, nil
+}
+
+func handleRequest(ctx *rod.Hijack, rules []networkRule) {
+	url := ctx.Request.URL().String()
+	for _, rule := range rules {
+		if !rule.matches(url, ctx.Request.Type()) {
+			continue
+		}
+
+		switch rule.rule.Action {
+		case models2.BlockRequest:
+			ctx.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+		case models2.OverrideHeaders:
+			ctx.ContinueRequest(&proto.FetchContinueRequest{Headers: overrideHeaders(ctx.Request.Headers(), rule.rule.Headers)})
+		case models2.MockResponse:
+			status := rule.rule.Mock.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			ctx.Response.Payload().ResponseCode = status
+			for name, value := range rule.rule.Mock.Headers {
+				ctx.Response.SetHeader(name, value)
+			}
+			ctx.Response.SetBody(rule.body)
+		default:
+			ctx.ContinueRequest(&proto.FetchContinueRequest{})
+		}
+
+		return
+	}
+
+	ctx.ContinueRequest(&proto.FetchContinueRequest{})
+}
+
+func overrideHeaders(headers proto.NetworkHeaders, overrides map[string]string) []*proto.FetchHeaderEntry {
+	overridden := make(map[string]bool, len(overrides))
+	for name := range overrides {
+		overridden[strings.ToLower(name)] = true
+	}
+
+	entries := make([]*proto.FetchHeaderEntry, 0, len(headers)+len(overrides))
+	for name, value := range headers {
+		if !overridden[strings.ToLower(name)] {
+			entries = append(entries, &proto.FetchHeaderEntry{Name: name, Value: value.String()})
+		}
+	}
+	for name, value := range overrides {
+		entries = append(entries, &proto.FetchHeaderEntry{Name: name, Value: value})
+	}
+
+	return entries
+}
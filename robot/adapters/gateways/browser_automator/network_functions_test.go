@@ -0,0 +1,118 @@
+package browser_automator
+
+import (
+	models2 "automator-go/robot/entities/models"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/gson"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNetworkRule_Matches(t *testing.T) {
+	tests := []struct {
+		name         string
+		rule         models2.NetworkRule
+		url          string
+		resourceType proto.NetworkResourceType
+		want         bool
+	}{
+		{
+			name:         "Url pattern",
+			rule:         models2.NetworkRule{Url: "*://*.doubleclick.net/*", Action: models2.BlockRequest},
+			url:          "https://ad.doubleclick.net/ddm/activity",
+			resourceType: proto.NetworkResourceTypeScript,
+			want:         true,
+		},
+		{
+			name:         "Other url",
+			rule:         models2.NetworkRule{Url: "*://*.doubleclick.net/*", Action: models2.BlockRequest},
+			url:          "https://en.wikipedia.org/wiki/Tony_Bennett",
+			resourceType: proto.NetworkResourceTypeDocument,
+			want:         false,
+		},
+		{
+			name:         "Resource type",
+			rule:         models2.NetworkRule{Url: "*", ResourceType: "Font", Action: models2.BlockRequest},
+			url:          "https://fonts.gstatic.com/s/roboto.woff2",
+			resourceType: proto.NetworkResourceTypeFont,
+			want:         true,
+		},
+		{
+			name:         "Other resource type",
+			rule:         models2.NetworkRule{Url: "*", ResourceType: "Font", Action: models2.BlockRequest},
+			url:          "https://en.wikipedia.org/wiki/Tony_Bennett",
+			resourceType: proto.NetworkResourceTypeDocument,
+			want:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := compileNetworkRules([]models2.NetworkRule{tt.rule}, t.TempDir())
+			if err != nil {
+				t.Fatalf("compileNetworkRules() error = %v", err)
+			}
+			if got := rules[0].matches(tt.url, tt.resourceType); got != tt.want {
+				t.Errorf("networkRule.matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverrideHeaders(t *testing.T) {
+	headers := proto.NetworkHeaders{}
+	headers["accept-language"] = gson.New("en")
+	headers["User-Agent"] = gson.New("robot")
+
+	entries := overrideHeaders(headers, map[string]string{"Accept-Language": "es-AR"})
+
+	got := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		got[entry.Name] = entry.Value
+	}
+	if len(got) != 2 || got["Accept-Language"] != "es-AR" || got["User-Agent"] != "robot" {
+		t.Errorf("overrideHeaders() = %v", got)
+	}
+}
+
+func TestReadFixture(t *testing.T) {
+	root := t.TempDir()
+	fixturesDir := filepath.Join(root, "fixtures")
+	if err := os.MkdirAll(filepath.Join(fixturesDir, "api"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(fixturesDir, "api", "summary.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".env"), []byte("API_KEY=secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, ".env"), filepath.Join(fixturesDir, "env.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		fixture string
+		want    string
+		wantErr bool
+	}{
+		{name: "Fixture", fixture: "api/summary.json", want: "{}"},
+		{name: "Fixture going up", fixture: "../.env", wantErr: true},
+		{name: "Absolute fixture", fixture: filepath.Join(root, ".env"), wantErr: true},
+		{name: "Fixture linking outside", fixture: "env.json", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readFixture(fixturesDir, tt.fixture)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readFixture() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("readFixture() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package browser_automator
+
+import (
+	models2 "automator-go/robot/entities/models"
+	"context"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxRunEvents limits the events kept for a run, pages logging in a loop
+	// would fill the run report otherwise.
+	maxRunEvents = 200
+	// maxRunEventMessage limits the length of the event messages.
+	maxRunEventMessage = 1000
+)
+
+// listenPage runs the callbacks on the events of the page, as EachEvent does,
+// until the returned function is called. The domains of the events are
+// enabled before it returns.
+func listenPage(page *rod.Page, callbacks ...interface{}) (stop func()) {
+	ctx, cancel := context.WithCancel(page.GetContext())
+	wait := page.Context(ctx).EachEvent(callbacks...)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wait()
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// pageEventCollector keeps the console messages, uncaught exceptions and
+// failed requests of a page, adding them as events of the task span too.
+type pageEventCollector struct {
+	mu     sync.Mutex
+	span   trace.Span
+	events []models2.RunEvent
+	// dropped counts the events over maxRunEvents.
+	dropped int
+	// requests keeps the urls of the requests in flight, the failure events
+	// only have their id.
+	requests map[proto.NetworkRequestID]string
+	stop     func()
+}
+
+func newPageEventCollector(span trace.Span) *pageEventCollector {
+	return &pageEventCollector{
+		span:     span,
+		events:   make([]models2.RunEvent, 0),
+		requests: make(map[proto.NetworkRequestID]string),
+	}
+}
+
+func startPageEventCollector(page *rod.Page, span trace.Span) *pageEventCollector {
+	collector := newPageEventCollector(span)
+	collector.stop = listenPage(
+		page,
+		collector.onConsoleAPICalled,
+		collector.onExceptionThrown,
+		collector.onRequestWillBeSent,
+		collector.onResponseReceived,
+		collector.onLoadingFinished,
+		collector.onLoadingFailed,
+	)
+
+	return collector
+}
+
+// Stop ends the collection, returning the events collected and how many were
+// dropped over the limit.
+func (c *pageEventCollector) Stop() ([]models2.RunEvent, int) {
+	c.stop()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.events, c.dropped
+}
+
+func (c *pageEventCollector) add(event models2.RunEvent) {
+	event.Message = truncateMessage(event.Message, maxRunEventMessage)
+
+	if len(c.events) >= maxRunEvents {
+		c.dropped++
+		return
+	}
+	c.events = append(c.events, event)
+
+	c.span.AddEvent(string(event.Kind), trace.WithTimestamp(event.Time), trace.WithAttributes(
+		attribute.String("level", event.Level),
+		attribute.String("message", event.Message),
+		attribute.String("url", event.Url),
+	))
+}
+
+func (c *pageEventCollector) onConsoleAPICalled(e *proto.RuntimeConsoleAPICalled) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	args := make([]string, 0, len(e.Args))
+	for _, arg := range e.Args {
+		args = append(args, remoteObjectText(arg))
+	}
+
+	c.add(models2.RunEvent{
+		Kind:    models2.ConsoleEvent,
+		Level:   string(e.Type),
+		Message: strings.Join(args, " "),
+		Url:     stackTraceUrl(e.StackTrace),
+		Time:    runtimeTime(e.Timestamp),
+	})
+}
+
+func (c *pageEventCollector) onExceptionThrown(e *proto.RuntimeExceptionThrown) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	details := e.ExceptionDetails
+	message := details.Text
+	if details.Exception != nil && details.Exception.Description != "" {
+		message = details.Exception.Description
+	}
+	url := details.URL
+	if url == "" {
+		url = stackTraceUrl(details.StackTrace)
+	}
+
+	c.add(models2.RunEvent{
+		Kind:    models2.ExceptionEvent,
+		Level:   "error",
+		Message: message,
+		Url:     url,
+		Time:    runtimeTime(e.Timestamp),
+	})
+}
+
+func (c *pageEventCollector) onRequestWillBeSent(e *proto.NetworkRequestWillBeSent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests[e.RequestID] = e.Request.URL
+}
+
+func (c *pageEventCollector) onResponseReceived(e *proto.NetworkResponseReceived) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e.Response.Status < http.StatusBadRequest {
+		return
+	}
+
+	message := e.Response.StatusText
+	if message == "" {
+		message = http.StatusText(e.Response.Status)
+	}
+
+	c.add(models2.RunEvent{
+		Kind:    models2.RequestFailedEvent,
+		Level:   strconv.Itoa(e.Response.Status),
+		Message: message,
+		Url:     e.Response.URL,
+		Time:    time.Now(),
+	})
+}
+
+func (c *pageEventCollector) onLoadingFinished(e *proto.NetworkLoadingFinished) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.requests, e.RequestID)
+}
+
+func (c *pageEventCollector) onLoadingFailed(e *proto.NetworkLoadingFailed) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	url := c.requests[e.RequestID]
+	delete(c.requests, e.RequestID)
+
+	// Requests canceled by the page or blocked by the network rules are not
+	// failures of the page.
+	if e.Canceled || strings.Contains(e.ErrorText, "ERR_BLOCKED_BY_CLIENT") {
+		return
+	}
+
+	message := e.ErrorText
+	if e.BlockedReason != "" {
+		message += " (" + string(e.BlockedReason) + ")"
+	}
+
+	c.add(models2.RunEvent{
+		Kind:    models2.RequestFailedEvent,
+		Message: message,
+		Url:     url,
+		Time:    time.Now(),
+	})
+}
+
+// remoteObjectText formats a console argument the way the devtools console
+// shows it.
+func remoteObjectText(object *proto.RuntimeRemoteObject) string {
+	switch {
+	case object.Type == proto.RuntimeRemoteObjectTypeString:
+		return object.Value.Str()
+	case object.UnserializableValue != "":
+		return string(object.UnserializableValue)
+	case object.Description != "":
+		return object.Description
+	case !object.Value.Nil():
+		return object.Value.JSON("", "")
+	}
+
+	return string(object.Type)
+}
+
+func stackTraceUrl(stackTrace *proto.RuntimeStackTrace) string {
+	if stackTrace == nil || len(stackTrace.CallFrames) == 0 {
+		return ""
+	}
+
+	return stackTrace.CallFrames[0].URL
+}
+
+// runtimeTime converts the runtime timestamps, milliseconds since the epoch.
+func runtimeTime(timestamp proto.RuntimeTimestamp) time.Time {
+	return time.UnixMicro(int64(float64(timestamp) * 1000))
+}
+
+// truncateMessage cuts the message to at most limit bytes, on a rune boundary
+// so the message stays valid UTF-8.
+func truncateMessage(message string, limit int) string {
+	if len(message) <= limit {
+		return message
+	}
+
+	end := limit
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+
+	return message[:end] + "..."
+}
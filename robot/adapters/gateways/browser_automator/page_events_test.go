@@ -0,0 +1,145 @@
+package browser_automator
+
+import (
+	models2 "automator-go/robot/entities/models"
+	"context"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/gson"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"testing"
+)
+
+func TestPageEventCollector(t *testing.T) {
+	tests := []struct {
+		name    string
+		collect func(c *pageEventCollector)
+		want    []models2.RunEvent
+	}{
+		{
+			name: "Console message",
+			collect: func(c *pageEventCollector) {
+				c.onConsoleAPICalled(&proto.RuntimeConsoleAPICalled{
+					Type: proto.RuntimeConsoleAPICalledTypeWarning,
+					Args: []*proto.RuntimeRemoteObject{
+						{Type: proto.RuntimeRemoteObjectTypeString, Value: gson.New("missing element")},
+						{Type: proto.RuntimeRemoteObjectTypeNumber, Value: gson.New(3)},
+						{Type: proto.RuntimeRemoteObjectTypeUndefined},
+					},
+					StackTrace: &proto.RuntimeStackTrace{CallFrames: []*proto.RuntimeCallFrame{{URL: "https://en.wikipedia.org/app.js"}}},
+				})
+			},
+			want: []models2.RunEvent{
+				{Kind: models2.ConsoleEvent, Level: "warning", Message: "missing element 3 undefined", Url: "https://en.wikipedia.org/app.js"},
+			},
+		},
+		{
+			name: "Uncaught exception",
+			collect: func(c *pageEventCollector) {
+				c.onExceptionThrown(&proto.RuntimeExceptionThrown{
+					ExceptionDetails: &proto.RuntimeExceptionDetails{
+						Text:      "Uncaught",
+						URL:       "https://en.wikipedia.org/app.js",
+						Exception: &proto.RuntimeRemoteObject{Description: "TypeError: x is undefined"},
+					},
+				})
+			},
+			want: []models2.RunEvent{
+				{Kind: models2.ExceptionEvent, Level: "error", Message: "TypeError: x is undefined", Url: "https://en.wikipedia.org/app.js"},
+			},
+		},
+		{
+			name: "Failed requests",
+			collect: func(c *pageEventCollector) {
+				c.onRequestWillBeSent(&proto.NetworkRequestWillBeSent{RequestID: "1", Request: &proto.NetworkRequest{URL: "https://en.wikipedia.org/api"}})
+				c.onLoadingFailed(&proto.NetworkLoadingFailed{RequestID: "1", ErrorText: "net::ERR_CONNECTION_RESET"})
+				c.onResponseReceived(&proto.NetworkResponseReceived{RequestID: "2", Response: &proto.NetworkResponse{URL: "https://en.wikipedia.org/missing", Status: 404}})
+			},
+			want: []models2.RunEvent{
+				{Kind: models2.RequestFailedEvent, Message: "net::ERR_CONNECTION_RESET", Url: "https://en.wikipedia.org/api"},
+				{Kind: models2.RequestFailedEvent, Level: "404", Message: "Not Found", Url: "https://en.wikipedia.org/missing"},
+			},
+		},
+		{
+			name: "Successful, canceled and blocked requests",
+			collect: func(c *pageEventCollector) {
+				c.onResponseReceived(&proto.NetworkResponseReceived{RequestID: "1", Response: &proto.NetworkResponse{Status: 200}})
+				c.onLoadingFailed(&proto.NetworkLoadingFailed{RequestID: "2", ErrorText: "net::ERR_ABORTED", Canceled: true})
+				c.onLoadingFailed(&proto.NetworkLoadingFailed{RequestID: "3", ErrorText: "net::ERR_BLOCKED_BY_CLIENT"})
+			},
+			want: []models2.RunEvent{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spanRecorder := tracetest.NewSpanRecorder()
+			tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer(tracerName)
+			_, span := tracer.Start(context.Background(), "task")
+
+			collector := newPageEventCollector(span)
+			collector.stop = func() {}
+			tt.collect(collector)
+			events, dropped := collector.Stop()
+			span.End()
+
+			if dropped != 0 {
+				t.Errorf("dropped = %d, want 0", dropped)
+			}
+			if len(events) != len(tt.want) {
+				t.Fatalf("events = %v, want %v", events, tt.want)
+			}
+			for i, want := range tt.want {
+				got := events[i]
+				if got.Kind != want.Kind || got.Level != want.Level || got.Message != want.Message || got.Url != want.Url {
+					t.Errorf("event %d = %+v, want %+v", i, got, want)
+				}
+			}
+
+			spanEvents := spanRecorder.Ended()[0].Events()
+			if len(spanEvents) != len(tt.want) {
+				t.Errorf("span events = %d, want %d", len(spanEvents), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestPageEventCollector_Limit(t *testing.T) {
+	_, span := sdktrace.NewTracerProvider().Tracer(tracerName).Start(context.Background(), "task")
+	collector := newPageEventCollector(span)
+	collector.stop = func() {}
+
+	for i := 0; i < maxRunEvents+5; i++ {
+		collector.onConsoleAPICalled(&proto.RuntimeConsoleAPICalled{
+			Type: proto.RuntimeConsoleAPICalledTypeLog,
+			Args: []*proto.RuntimeRemoteObject{{Type: proto.RuntimeRemoteObjectTypeString, Value: gson.New("tick")}},
+		})
+	}
+
+	events, dropped := collector.Stop()
+	if len(events) != maxRunEvents || dropped != 5 {
+		t.Errorf("events = %d, dropped = %d, want %d and 5", len(events), dropped, maxRunEvents)
+	}
+}
+
+func Test_truncateMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		limit   int
+		want    string
+	}{
+		{name: "Short message", message: "hola", limit: 10, want: "hola"},
+		{name: "Ascii message", message: "hola mundo", limit: 4, want: "hola..."},
+		{name: "Cut inside a rune", message: "año", limit: 2, want: "a..."},
+		{name: "Cut after a rune", message: "año", limit: 3, want: "añ..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateMessage(tt.message, tt.limit); got != tt.want {
+				t.Errorf("truncateMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
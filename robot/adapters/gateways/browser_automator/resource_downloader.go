@@ -0,0 +1,583 @@
+package browser_automator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultMaxResourceSize limits the downloaded resources when
+	// BROWSER_RESOURCE_MAX_SIZE is not set, 100 MiB.
+	defaultMaxResourceSize = 100 << 20
+	// resourceChunkSize is the size of the chunks read from the browser streams.
+	resourceChunkSize = 1 << 20
+	// sniffSize is how much of a resource is read to detect its type.
+	sniffSize = 512
+)
+
+var errResourceTooLarge = errors.New("resource is larger than the maximum size")
+
+// resourceExtensions maps the types of the downloaded resources to the
+// extension they are stored with, the system mime database is not reliable in
+// containers.
+var resourceExtensions = map[string]string{
+	"image/png":                "png",
+	"image/jpeg":               "jpg",
+	"image/gif":                "gif",
+	"image/webp":               "webp",
+	"image/avif":               "avif",
+	"image/svg+xml":            "svg",
+	"image/bmp":                "bmp",
+	"image/x-icon":             "ico",
+	"image/vnd.microsoft.icon": "ico",
+	"video/mp4":                "mp4",
+	"video/webm":               "webm",
+	"video/ogg":                "ogv",
+	"video/quicktime":          "mov",
+	"video/x-matroska":         "mkv",
+	"video/x-msvideo":          "avi",
+	"video/avi":                "avi",
+	"video/mp2t":               "ts",
+	"video/mpeg":               "mpeg",
+	"audio/mpeg":               "mp3",
+	"audio/mp4":                "m4a",
+	"audio/ogg":                "ogg",
+	"audio/wav":                "wav",
+	"audio/webm":               "weba",
+	"audio/aac":                "aac",
+	"application/pdf":          "pdf",
+}
+
+func maxResourceSize() (int64, error) {
+	maxSizeEnv := os.Getenv("BROWSER_RESOURCE_MAX_SIZE")
+	if strings.TrimSpace(maxSizeEnv) == "" {
+		return defaultMaxResourceSize, nil
+	}
+
+	maxSize, err := strconv.ParseInt(strings.TrimSpace(maxSizeEnv), 10, 64)
+	if err != nil || maxSize <= 0 {
+		return 0, fmt.Errorf("error parsing resource max size env: %q is not a positive number of bytes", maxSizeEnv)
+	}
+
+	return maxSize, nil
+}
+
+// limitedWriter fails the writes going over its remaining bytes, so the
+// downloads stop as soon as they are too large.
+type limitedWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (lw *limitedWriter) Write(data []byte) (int, error) {
+	if int64(len(data)) > lw.remaining {
+		return 0, errResourceTooLarge
+	}
+	n, err := lw.w.Write(data)
+	lw.remaining -= int64(n)
+
+	return n, err
+}
+
+// elementSources are the urls an element may load its resource from.
+type elementSources struct {
+	Tag        string `json:"tag"`
+	CurrentSrc string `json:"currentSrc"`
+	Src        string `json:"src"`
+	Srcset     string `json:"srcset"`
+	InPicture  bool   `json:"inPicture"`
+	BaseUri    string `json:"baseUri"`
+	Sources    []struct {
+		Src      string `json:"src"`
+		Srcset   string `json:"srcset"`
+		Playable bool   `json:"playable"`
+	} `json:"sources"`
+	// Manifests are the HLS and DASH manifests loaded by the page, the videos
+	// streamed with media source extensions only have a blob url.
+	Manifests []string `json:"manifests"`
+}
+
+// elementSourcesScript reads the sources of the element. Pictures are read
+// from their img, the <source> children of the picture, video or audio are
+// listed with whether the browser can play them.
+const elementSourcesScript = `() => {
+	const target = this.tagName === 'PICTURE' ? (this.querySelector('img') || this) : this;
+	const parent = target.parentElement;
+	const inPicture = !!parent && parent.tagName === 'PICTURE';
+	const container = inPicture ? parent : target;
+	const playable = (type) => !type || typeof target.canPlayType !== 'function' || target.canPlayType(type) !== '';
+	return {
+		tag: target.tagName.toLowerCase(),
+		currentSrc: target.currentSrc || '',
+		src: target.getAttribute('src') || '',
+		srcset: target.getAttribute('srcset') || '',
+		inPicture: inPicture,
+		baseUri: document.baseURI,
+		sources: Array.from(container.querySelectorAll(':scope > source')).map((source) => ({
+			src: source.getAttribute('src') || '',
+			srcset: source.getAttribute('srcset') || '',
+			playable: playable(source.type),
+		})),
+		manifests: performance.getEntriesByType('resource')
+			.map((entry) => entry.name)
+			.filter((name) => /\.(m3u8|mpd)([?#]|$)/i.test(name)),
+	};
+}`
+
+// resourceUrls returns the urls to try for the resource of the element, the
+// best one first. The largest srcset candidate beats the one the browser chose
+// for the viewport, except in pictures where the sources are art direction.
+func (s *elementSources) resourceUrls() ([]string, error) {
+	base, err := url.Parse(s.BaseUri)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing document base url: %w", err)
+	}
+
+	urls := make([]string, 0)
+	add := func(reference string) {
+		reference = strings.TrimSpace(reference)
+		if reference == "" {
+			return
+		}
+		resolved, err := base.Parse(reference)
+		if err != nil {
+			return
+		}
+		for _, existing := range urls {
+			if existing == resolved.String() {
+				return
+			}
+		}
+		urls = append(urls, resolved.String())
+	}
+
+	if !s.InPicture {
+		add(largestSrcsetCandidate(s.Srcset))
+	}
+	add(s.CurrentSrc)
+	add(s.Src)
+	for _, source := range s.Sources {
+		if source.Playable {
+			add(source.Src)
+			add(largestSrcsetCandidate(source.Srcset))
+		}
+	}
+	if len(urls) > 0 && strings.HasPrefix(urls[0], "blob:") && len(s.Manifests) > 0 {
+		// The last manifest loaded is the one the player is streaming.
+		add(s.Manifests[len(s.Manifests)-1])
+	}
+
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("%s element has no resource url", s.Tag)
+	}
+
+	return urls, nil
+}
+
+// largestSrcsetCandidate returns the candidate with the highest width or
+// pixel density descriptor of a srcset attribute.
+func largestSrcsetCandidate(srcset string) string {
+	best, bestSize := "", -1.0
+	for _, candidate := range splitSrcset(srcset) {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+
+		size := 1.0
+		if len(fields) > 1 {
+			descriptor := fields[len(fields)-1]
+			value, err := strconv.ParseFloat(descriptor[:len(descriptor)-1], 64)
+			if err != nil {
+				continue
+			}
+			switch descriptor[len(descriptor)-1] {
+			case 'w':
+				size = value
+			case 'x':
+				// Densities are compared against widths as if 1x was 1000px wide.
+				size = value * 1000
+			default:
+				continue
+			}
+		}
+
+		if size > bestSize {
+			best, bestSize = fields[0], size
+		}
+	}
+
+	return best
+}
+
+// splitSrcset splits the candidates of a srcset on the commas following a
+// descriptor or followed by whitespace, the urls may have commas themselves.
+func splitSrcset(srcset string) []string {
+	candidates := make([]string, 0)
+	start := 0
+	inUrl := true
+	for i, r := range srcset {
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			if strings.TrimSpace(srcset[start:i]) != "" {
+				inUrl = false
+			}
+		case r == ',' && (!inUrl || i+1 == len(srcset) || strings.ContainsRune(" \t\n", rune(srcset[i+1]))):
+			candidates = append(candidates, srcset[start:i])
+			start, inUrl = i+1, true
+		}
+	}
+	candidates = append(candidates, srcset[start:])
+
+	return candidates
+}
+
+// resourceDownloader downloads the resources through the browser, with the
+// cookies, proxy and network rules of the page, streaming them to a temporary
+// file.
+type resourceDownloader struct {
+	page    *rod.Page
+	maxSize int64
+}
+
+// downloadedResource is a resource saved to a temporary file, owned by the
+// caller.
+type downloadedResource struct {
+	file string
+	ext  string
+	mime string
+}
+
+// download fetches the resource of the element, trying its urls in order.
+// HLS and DASH manifests are replaced by their segments joined in one file.
+func (d *resourceDownloader) download(element *rod.Element) (*downloadedResource, error) {
+	result, err := element.Eval(elementSourcesScript)
+	if err != nil {
+		return nil, fmt.Errorf("error getting element sources: %w", err)
+	}
+	var sources elementSources
+	if err = result.Value.Unmarshal(&sources); err != nil {
+		return nil, fmt.Errorf("error reading element sources: %w", err)
+	}
+
+	urls, err := sources.resourceUrls()
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, resourceUrl := range urls {
+		resource, err := d.downloadUrl(resourceUrl)
+		if err == nil {
+			return resource, nil
+		}
+		errs = append(errs, fmt.Errorf("error downloading %s: %w", shortUrl(resourceUrl), err))
+		if errors.Is(err, errResourceTooLarge) {
+			break
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func (d *resourceDownloader) downloadUrl(resourceUrl string) (resource *downloadedResource, err error) {
+	file, err := os.CreateTemp("", "resource_*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating resource file: %w", err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if err == nil && closeErr != nil {
+			err = fmt.Errorf("error closing resource file: %w", closeErr)
+		}
+		if err != nil {
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	writer := &limitedWriter{w: file, remaining: d.maxSize}
+	contentType, err := d.fetch(resourceUrl, writer)
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, sniffSize)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error reading resource file: %w", err)
+	}
+	head = head[:n]
+
+	resource = &downloadedResource{file: file.Name()}
+	if kind := detectManifest(contentType, head); kind != "" {
+		segments, err := d.manifestSegments(kind, resourceUrl, file)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = file.Truncate(0); err != nil {
+			return nil, fmt.Errorf("error truncating resource file: %w", err)
+		}
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("error truncating resource file: %w", err)
+		}
+		writer.remaining = d.maxSize
+		for i, segmentUrl := range segments.urls {
+			if _, err = d.fetch(segmentUrl, writer); err != nil {
+				return nil, fmt.Errorf("error downloading segment %d of %d: %w", i+1, len(segments.urls), err)
+			}
+		}
+
+		resource.ext, resource.mime = segments.ext, segments.mime
+		return resource, nil
+	}
+
+	resource.mime = detectResourceType(contentType, head)
+	resource.ext = resourceExtension(resource.mime, resourceUrl)
+
+	return resource, nil
+}
+
+// manifestSegments reads the manifest saved in the file and lists the
+// segments of its best stream, following the variants of HLS master playlists.
+func (d *resourceDownloader) manifestSegments(kind manifestKind, manifestUrl string, file *os.File) (*mediaSegments, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+	if info.Size() > maxManifestSize {
+		return nil, fmt.Errorf("manifest is larger than %d bytes", maxManifestSize)
+	}
+	manifest := make([]byte, info.Size())
+	if _, err = file.ReadAt(manifest, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+
+	base, err := url.Parse(manifestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing manifest url: %w", err)
+	}
+
+	if kind == dashManifest {
+		return parseDashManifest(manifest, base)
+	}
+
+	playlist, err := parseHlsPlaylist(manifest, base)
+	if err != nil {
+		return nil, err
+	}
+	if playlist.variant == "" {
+		return &playlist.segments, nil
+	}
+
+	var variant bytes.Buffer
+	if _, err = d.fetch(playlist.variant, &limitedWriter{w: &variant, remaining: maxManifestSize}); err != nil {
+		return nil, fmt.Errorf("error downloading hls variant: %w", err)
+	}
+	base, err = url.Parse(playlist.variant)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing hls variant url: %w", err)
+	}
+	playlist, err = parseHlsPlaylist(variant.Bytes(), base)
+	if err != nil {
+		return nil, err
+	}
+	if playlist.variant != "" {
+		return nil, errors.New("hls variant is a master playlist")
+	}
+
+	return &playlist.segments, nil
+}
+
+// fetch writes the resource at the url, returning its content type. Blob urls
+// are read from the page, data urls decoded and the rest loaded by the
+// browser network stack.
+func (d *resourceDownloader) fetch(resourceUrl string, w io.Writer) (string, error) {
+	switch {
+	case strings.HasPrefix(resourceUrl, "data:"):
+		return writeDataUrl(resourceUrl, w)
+	case strings.HasPrefix(resourceUrl, "blob:"):
+		return d.fetchBlob(resourceUrl, w)
+	}
+
+	loaded, err := proto.NetworkLoadNetworkResource{
+		FrameID: d.page.FrameID,
+		URL:     resourceUrl,
+		Options: &proto.NetworkLoadNetworkResourceOptions{IncludeCredentials: true},
+	}.Call(d.page)
+	if err != nil {
+		return "", fmt.Errorf("error loading resource: %w", err)
+	}
+
+	resource := loaded.Resource
+	if !resource.Success {
+		if resource.Stream != "" {
+			_ = proto.IOClose{Handle: resource.Stream}.Call(d.page)
+		}
+		if resource.HTTPStatusCode != nil {
+			return "", fmt.Errorf("resource responded with status %d", int(*resource.HTTPStatusCode))
+		}
+		return "", fmt.Errorf("error loading resource: %s", resource.NetErrorName)
+	}
+
+	if err = d.readStream(resource.Stream, w); err != nil {
+		return "", err
+	}
+
+	var contentType string
+	for name, value := range resource.Headers {
+		if strings.EqualFold(name, "Content-Type") {
+			contentType = value.String()
+		}
+	}
+
+	return contentType, nil
+}
+
+// fetchBlob reads the blob of the url in the page. Blob urls of media source
+// streams can't be fetched, their manifests are tried next.
+func (d *resourceDownloader) fetchBlob(blobUrl string, w io.Writer) (string, error) {
+	blob, err := d.page.Evaluate(rod.Eval(`(url) => fetch(url).then((response) => response.blob())`, blobUrl).ByObject().ByPromise())
+	if err != nil {
+		return "", fmt.Errorf("error fetching blob: %w", err)
+	}
+	defer func() { _ = d.page.Release(blob) }()
+
+	blobType, err := d.page.Evaluate(rod.Eval(`function() { return this.type }`).This(blob))
+	if err != nil {
+		return "", fmt.Errorf("error reading blob type: %w", err)
+	}
+
+	resolved, err := proto.IOResolveBlob{ObjectID: blob.ObjectID}.Call(d.page)
+	if err != nil {
+		return "", fmt.Errorf("error resolving blob: %w", err)
+	}
+
+	if err = d.readStream(proto.IOStreamHandle("blob:"+resolved.UUID), w); err != nil {
+		return "", err
+	}
+
+	return blobType.Value.Str(), nil
+}
+
+func (d *resourceDownloader) readStream(handle proto.IOStreamHandle, w io.Writer) error {
+	defer func() { _ = proto.IOClose{Handle: handle}.Call(d.page) }()
+
+	size := resourceChunkSize
+	for {
+		chunk, err := proto.IORead{Handle: handle, Size: &size}.Call(d.page)
+		if err != nil {
+			return fmt.Errorf("error reading resource stream: %w", err)
+		}
+
+		data := []byte(chunk.Data)
+		if chunk.Base64Encoded {
+			data, err = base64.StdEncoding.DecodeString(chunk.Data)
+			if err != nil {
+				return fmt.Errorf("error decoding resource stream: %w", err)
+			}
+		}
+		if _, err = w.Write(data); err != nil {
+			return err
+		}
+
+		if chunk.EOF {
+			return nil
+		}
+	}
+}
+
+// writeDataUrl decodes a data url, returning its media type.
+func writeDataUrl(dataUrl string, w io.Writer) (string, error) {
+	header, data, ok := strings.Cut(strings.TrimPrefix(dataUrl, "data:"), ",")
+	if !ok {
+		return "", errors.New("invalid data url")
+	}
+
+	mediaType, isBase64 := strings.CutSuffix(header, ";base64")
+	var decoded []byte
+	var err error
+	if isBase64 {
+		decoded, err = base64.StdEncoding.DecodeString(data)
+	} else {
+		var unescaped string
+		unescaped, err = url.PathUnescape(data)
+		decoded = []byte(unescaped)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error decoding data url: %w", err)
+	}
+
+	if _, err = w.Write(decoded); err != nil {
+		return "", err
+	}
+
+	return mediaType, nil
+}
+
+// detectResourceType trusts the content type sent with the resource unless it
+// is missing or generic, sniffing the magic bytes of the content then.
+func detectResourceType(contentType string, head []byte) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && mediaType != "application/octet-stream" && mediaType != "binary/octet-stream" && mediaType != "text/plain" {
+		return mediaType
+	}
+
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if sniffed != "application/octet-stream" {
+		return sniffed
+	}
+
+	// Formats the standard sniffer misses.
+	switch {
+	case len(head) >= 12 && string(head[4:8]) == "ftyp" && (string(head[8:12]) == "avif" || string(head[8:12]) == "avis"):
+		return "image/avif"
+	case len(head) >= 12 && string(head[4:8]) == "ftyp" && string(head[8:10]) == "qt":
+		return "video/quicktime"
+	case len(head) >= 12 && string(head[4:8]) == "ftyp":
+		return "video/mp4"
+	case len(head) > 188 && head[0] == 0x47 && head[188] == 0x47:
+		// Transport streams are 188 bytes packets starting with a sync byte.
+		return "video/mp2t"
+	}
+
+	return sniffed
+}
+
+// resourceExtension returns the extension of the type, or the one in the url
+// when the type is unknown.
+func resourceExtension(mediaType string, resourceUrl string) string {
+	if extension, ok := resourceExtensions[mediaType]; ok {
+		return extension
+	}
+
+	if parsedUrl, err := url.Parse(resourceUrl); err == nil && parsedUrl.Scheme != "data" && parsedUrl.Scheme != "blob" {
+		extension := strings.TrimPrefix(strings.ToLower(path.Ext(parsedUrl.Path)), ".")
+		if extension != "" && len(extension) <= 5 {
+			return extension
+		}
+	}
+
+	return "bin"
+}
+
+// shortUrl keeps the errors readable when the url is a data url.
+func shortUrl(resourceUrl string) string {
+	if len(resourceUrl) > 100 {
+		return resourceUrl[:100] + "..."
+	}
+
+	return resourceUrl
+}
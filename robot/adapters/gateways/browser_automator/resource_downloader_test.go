@@ -0,0 +1,196 @@
+package browser_automator
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestElementSources_ResourceUrls(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources elementSources
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Largest srcset candidate",
+			sources: elementSources{
+				Tag:        "img",
+				CurrentSrc: "https://en.wikipedia.org/img/tony_320.jpg",
+				Src:        "/img/tony_320.jpg",
+				Srcset:     "/img/tony_320.jpg 320w, /img/tony_1280.jpg 1280w, /img/tony_640.jpg 640w",
+				BaseUri:    "https://en.wikipedia.org/wiki/Tony_Bennett",
+			},
+			want: []string{"https://en.wikipedia.org/img/tony_1280.jpg", "https://en.wikipedia.org/img/tony_320.jpg"},
+		},
+		{
+			name: "Picture keeps the source chosen by the browser",
+			sources: elementSources{
+				Tag:        "img",
+				CurrentSrc: "https://en.wikipedia.org/img/tony_mobile.webp",
+				Srcset:     "img/tony_2x.jpg 2x",
+				InPicture:  true,
+				BaseUri:    "https://en.wikipedia.org/wiki/Tony_Bennett",
+			},
+			want: []string{"https://en.wikipedia.org/img/tony_mobile.webp"},
+		},
+		{
+			name: "Video source children",
+			sources: elementSources{
+				Tag:     "video",
+				BaseUri: "https://example.com/watch",
+				Sources: []struct {
+					Src      string `json:"src"`
+					Srcset   string `json:"srcset"`
+					Playable bool   `json:"playable"`
+				}{
+					{Src: "clip.ogv", Playable: false},
+					{Src: "clip.mp4", Playable: true},
+				},
+			},
+			want: []string{"https://example.com/clip.mp4"},
+		},
+		{
+			name: "Media source stream",
+			sources: elementSources{
+				Tag:        "video",
+				CurrentSrc: "blob:https://example.com/5d5b3e0c",
+				BaseUri:    "https://example.com/watch",
+				Manifests:  []string{"https://cdn.example.com/ad.m3u8", "https://cdn.example.com/clip.m3u8"},
+			},
+			want: []string{"blob:https://example.com/5d5b3e0c", "https://cdn.example.com/clip.m3u8"},
+		},
+		{
+			name:    "Without sources",
+			sources: elementSources{Tag: "div", BaseUri: "https://example.com"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.sources.resourceUrls()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resourceUrls() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resourceUrls() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLargestSrcsetCandidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		srcset string
+		want   string
+	}{
+		{name: "Widths", srcset: "a.jpg 480w, b.jpg 1080w,c.jpg 800w", want: "b.jpg"},
+		{name: "Densities", srcset: "a.jpg, b.jpg 2x, c.jpg 1.5x", want: "b.jpg"},
+		{name: "Commas in urls", srcset: "img.jpg?size=1,2 1x, img.jpg?size=3,4 3x", want: "img.jpg?size=3,4"},
+		{name: "Empty", srcset: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := largestSrcsetCandidate(tt.srcset); got != tt.want {
+				t.Errorf("largestSrcsetCandidate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectResourceType(t *testing.T) {
+	tsPacket := make([]byte, 189)
+	tsPacket[0], tsPacket[188] = 0x47, 0x47
+
+	tests := []struct {
+		name        string
+		contentType string
+		head        []byte
+		want        string
+	}{
+		{name: "Content type", contentType: "image/webp", head: []byte("not sniffed"), want: "image/webp"},
+		{name: "Generic content type", contentType: "application/octet-stream", head: []byte("\x89PNG\r\n\x1a\n"), want: "image/png"},
+		{name: "Missing content type", head: []byte("GIF89a"), want: "image/gif"},
+		{name: "Mp4", head: []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"), want: "video/mp4"},
+		{name: "Quicktime", head: []byte("\x00\x00\x00\x14ftypqt  \x00\x00\x00\x00"), want: "video/quicktime"},
+		{name: "Avif", head: []byte("\x00\x00\x00\x1cftypavif\x00\x00\x00\x00mif1"), want: "image/avif"},
+		{name: "Transport stream", head: tsPacket, want: "video/mp2t"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectResourceType(tt.contentType, tt.head); got != tt.want {
+				t.Errorf("detectResourceType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceExtension(t *testing.T) {
+	tests := []struct {
+		name        string
+		mediaType   string
+		resourceUrl string
+		want        string
+	}{
+		{name: "Known type", mediaType: "video/webm", resourceUrl: "https://example.com/clip", want: "webm"},
+		{name: "Url extension", mediaType: "application/x-unknown", resourceUrl: "https://example.com/model.GLB?v=2", want: "glb"},
+		{name: "Blob url", mediaType: "application/x-unknown", resourceUrl: "blob:https://example.com/5d5b3e0c", want: "bin"},
+		{name: "Without extension", mediaType: "application/x-unknown", resourceUrl: "https://example.com/download", want: "bin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resourceExtension(tt.mediaType, tt.resourceUrl); got != tt.want {
+				t.Errorf("resourceExtension() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteDataUrl(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataUrl  string
+		wantType string
+		want     string
+		wantErr  bool
+	}{
+		{name: "Base64", dataUrl: "data:image/gif;base64,R0lGODlh", wantType: "image/gif", want: "GIF89a"},
+		{name: "Escaped", dataUrl: "data:image/svg+xml,%3Csvg%2F%3E", wantType: "image/svg+xml", want: "<svg/>"},
+		{name: "Invalid", dataUrl: "data:image/gif;base64", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data bytes.Buffer
+			gotType, err := writeDataUrl(tt.dataUrl, &data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("writeDataUrl() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotType != tt.wantType || data.String() != tt.want {
+				t.Errorf("writeDataUrl() = %q, %q, want %q, %q", gotType, data.String(), tt.wantType, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitedWriter(t *testing.T) {
+	var data bytes.Buffer
+	writer := &limitedWriter{w: &data, remaining: 5}
+
+	if _, err := writer.Write([]byte("abc")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if _, err := writer.Write([]byte("def")); !errors.Is(err, errResourceTooLarge) {
+		t.Errorf("Write() error = %v, want %v", err, errResourceTooLarge)
+	}
+	if data.String() != "abc" {
+		t.Errorf("written = %q, want %q", data.String(), "abc")
+	}
+}
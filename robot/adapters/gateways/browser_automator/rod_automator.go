@@ -1,11 +1,18 @@
 package browser_automator
 
 import (
+	"automator-go/robot/adapters/gateways/driver"
 	models2 "automator-go/robot/entities/models"
 	"automator-go/robot/usecases/task"
+	"automator-go/utils"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.uber.org/zap"
 	"os"
 	"strings"
@@ -13,127 +20,384 @@ import (
 )
 
 type RodAutomator struct {
-	browser  *rod.Browser
-	pagePool rod.PagePool
-	logger   *otelzap.LoggerWithCtx
+	farm         *BrowserFarm
+	sessionStore task.SessionStore
+	blocklist    []models2.NetworkRule
+	fixturesDir  string
+	ctx          context.Context
+	logger       *otelzap.LoggerWithCtx
 }
 
-func NewRodAutomator(browser *rod.Browser, pagePool rod.PagePool, logger *otelzap.LoggerWithCtx) *RodAutomator {
-	return &RodAutomator{browser: browser, pagePool: pagePool, logger: logger}
+// NewRodAutomator receives a nil sessionStore when sessions are not
+// configured, tasks using them fail then. The blocklist rules apply to every
+// task after its own network rules. The mock fixtures of the rules are read
+// from fixturesDir.
+func NewRodAutomator(
+	farm *BrowserFarm,
+	sessionStore task.SessionStore,
+	blocklist []models2.NetworkRule,
+	fixturesDir string,
+	ctx context.Context,
+	logger *otelzap.LoggerWithCtx,
+) *RodAutomator {
+	return &RodAutomator{
+		farm:         farm,
+		sessionStore: sessionStore,
+		blocklist:    blocklist,
+		fixturesDir:  fixturesDir,
+		ctx:          ctx,
+		logger:       logger,
+	}
 }
 
-func (at *RodAutomator) createPage() *rod.Page {
-	return at.browser.MustPage()
-}
+// openPage creates the page of the task in a new incognito browser context, or
+// in the browser of its profile when it has one. The returned function closes
+// the page, disposing the incognito context with its cookies and storage.
+func (at *RodAutomator) openPage(lease *BrowserLease, taskToRun *models2.Task) (*rod.Page, func() error, error) {
+	if taskToRun.Profile != "" {
+		profile, err := lease.Profiles.Get(taskToRun.Profile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		page, err := profile.Page(proto.TargetCreateTarget{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating page: %w", err)
+		}
 
-func (at *RodAutomator) Run(taskToRun *models2.Task) (*[]task.RawMedia, error) {
-	at.logger.Debug("Getting page from pool")
-	page := at.pagePool.Get(at.createPage)
-	defer at.pagePool.Put(page)
+		return page, page.Close, nil
+	}
 
-	err := page.Navigate(taskToRun.Url)
+	incognito, err := lease.Browser.Incognito()
 	if err != nil {
-		return nil, fmt.Errorf("error navigating to url: %w", err)
+		return nil, nil, fmt.Errorf("error creating incognito browser context: %w", err)
 	}
-	at.logger.Debug("Page initialized and navigated to url")
 
-	pageTimeOutEnv := os.Getenv("BROWSER_PAGE_TIMEOUT_BY_TASK")
-	if strings.TrimSpace(pageTimeOutEnv) == "" {
-		pageTimeOutEnv = "15s"
+	page, err := incognito.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		_ = incognito.Close()
+		return nil, nil, fmt.Errorf("error creating page: %w", err)
 	}
-	pageTimeout, err := time.ParseDuration(pageTimeOutEnv)
+
+	return page, incognito.Close, nil
+}
+
+// Run runs the task in a new page. A failed task still returns the har, the
+// video and the page events recorded until it failed, with the snapshot of the
+// page taken when its action failed.
+func (at *RodAutomator) Run(taskToRun *models2.Task, strategies []*models2.Strategy) (result *task.RunResult, err error) {
+	// The page slots of the farm limit how many tasks run at the same time on
+	// each browser. Tasks with a profile run in the browser of their profile,
+	// they still take a slot.
+	at.logger.Debug("Waiting for a free page in the browser farm")
+	lease, err := at.farm.Acquire()
 	if err != nil {
-		return nil, fmt.Errorf("error parsing page timeout: %w", err)
+		return nil, fmt.Errorf("error waiting for a browser: %w", err)
 	}
-	page = page.Timeout(pageTimeout)
-	at.logger.Debug("Set page timeout")
+	defer lease.Release()
+	at.logger.Debug("Running task on browser", zap.String("endpoint", lease.Endpoint))
 
-	err = page.WaitStable(800 * time.Millisecond)
+	page, closePage, err := at.openPage(lease, taskToRun)
 	if err != nil {
-		return nil, fmt.Errorf("error waiting for page to be stable: %w", err)
+		return nil, err
 	}
-	at.logger.Debug("Page is stable and loaded")
+	defer func() {
+		if err := closePage(); err != nil {
+			at.logger.Error("Error closing page", zap.Error(err))
+		}
+	}()
+	at.logger.Debug("Page created", zap.String("profile", taskToRun.Profile))
 
-	rawMedias := make([]task.RawMedia, 0)
+	// The spans of the actions are children of the task span, they start from
+	// the page context.
+	spanCtx, span := utils.StartSpan(page.GetContext(), tracerName, "task "+taskToRun.Id)
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("task.id", taskToRun.Id),
+		attribute.String("task.url", taskToRun.Url),
+		attribute.String("browser.endpoint", lease.Endpoint),
+	)
+	page = page.Context(spanCtx)
 
-	for _, action := range taskToRun.Actions {
-		switch action.Type {
-		case models2.Navigate:
-			at.logger.Debug("Navigating to url", zap.String("selector", action.Value))
-			err = navigate(page, action)
-			if err != nil {
-				return nil, fmt.Errorf("error navigating xpath: %w", err)
-			}
-			at.logger.Debug("Navigated to url", zap.String("selector", action.Value))
-		case models2.Click:
-			at.logger.Debug("Clicking on element", zap.String("selector", action.Value))
-			err = click(page, action)
-			if err != nil {
-				return nil, fmt.Errorf("error clicking xpath: %w", err)
-			}
-			at.logger.Debug("Clicked on element", zap.String("selector", action.Value))
-		case models2.ScrollDown:
-			at.logger.Debug("Scrolling down")
-			err = scrollDown(page, action)
-			if err != nil {
-				return nil, fmt.Errorf("error scrolling down: %w", err)
-			}
-			at.logger.Debug("Scrolled down")
-		case models2.Capture:
-			at.logger.Debug("Capturing element", zap.String("selector", action.Value))
-			rawMedia, err := capture(page, action)
+	collector := startPageEventCollector(page, span)
+	defer func() {
+		events, dropped := collector.Stop()
+		if dropped > 0 {
+			span.SetAttributes(attribute.Int("task.events.dropped", dropped))
+			at.logger.Warn("Dropped page events over the limit", zap.Int("dropped", dropped))
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		if result == nil {
+			result = &task.RunResult{}
+		}
+		result.Events = events
+		result.Failure = failureSnapshot(err)
+		at.logger.Debug("Collected page events", zap.Int("events", len(events)))
+	}()
+
+	if taskToRun.RecordHar {
+		recorder := startHarRecorder(page, taskToRun.HarFullCapture)
+		at.logger.Debug("Recording har")
+		defer func() {
+			har, err := recorder.Stop()
 			if err != nil {
-				return nil, fmt.Errorf("error capturing element: %w", err)
+				at.logger.Error("Error recording har", zap.Error(err))
+				return
 			}
-			rawMedias = append(rawMedias, *rawMedia)
-			at.logger.Debug("Captured element", zap.String("selector", action.Value))
-		case models2.WaitSeconds:
-			at.logger.Debug("Waiting seconds", zap.String("seconds", action.Value))
-			err = waitSeconds(action)
-			if err != nil {
-				return nil, fmt.Errorf("error waiting seconds: %w", err)
+			if result == nil {
+				result = &task.RunResult{}
 			}
-			at.logger.Debug("Waited seconds", zap.String("seconds", action.Value))
-		case models2.WriteInput:
-			at.logger.Debug("Writing input", zap.String("input", action.Value))
-			err = writeInput(page, action)
+			result.Har = har
+		}()
+	}
+
+	if taskToRun.Video != nil {
+		recorder, err := startScreencastRecorder(page, taskToRun.Video)
+		if err != nil {
+			return nil, err
+		}
+		at.logger.Debug("Recording video", zap.String("format", taskToRun.Video.FormatName()))
+		defer func() {
+			video, err := recorder.Stop()
 			if err != nil {
-				return nil, fmt.Errorf("error writing input: %w", err)
+				at.logger.Error("Error recording video", zap.Error(err))
+				return
 			}
-			at.logger.Debug("Wrote input", zap.String("input", action.Value))
-		case models2.ClearInput:
-			at.logger.Debug("Clearing input")
-			err = clearInput(page, action)
-			if err != nil {
-				return nil, fmt.Errorf("error clearing input: %w", err)
+			if result == nil {
+				result = &task.RunResult{}
 			}
-			at.logger.Debug("Cleared input")
-		case models2.SelectOptions:
-			at.logger.Debug("Selecting options", zap.String("options", action.Value))
-			err = selectOptions(page, action)
-			if err != nil {
-				return nil, fmt.Errorf("error selecting options: %w", err)
+			result.Video = video
+		}()
+	}
+
+	if taskToRun.Browser != nil {
+		err = applyBrowserConfig(page, taskToRun.Browser, taskToRun.Url)
+		// Emulation overrides are discarded with the page, but permissions are
+		// kept by the browser context of the profile.
+		defer func() {
+			if err := revokeBrowserPermissions(page, taskToRun.Browser, taskToRun.Url); err != nil {
+				at.logger.Error("Error revoking browser permissions", zap.Error(err))
 			}
-			at.logger.Debug("Selected options", zap.String("options", action.Value))
-		case models2.WriteTime:
-			at.logger.Debug("Writing time on input", zap.String("input", action.Value))
-			err = writeTime(page, action)
-			if err != nil {
-				return nil, fmt.Errorf("error writing time on input: %w", err)
+		}()
+		if err != nil {
+			return nil, fmt.Errorf("error applying browser config: %w", err)
+		}
+		at.logger.Debug("Browser config applied", zap.String("device", taskToRun.Browser.Device))
+	}
+
+	// Task rules go first, so they can exempt urls from the blocklist.
+	rules := append(append([]models2.NetworkRule(nil), taskToRun.Network...), at.blocklist...)
+	if len(rules) > 0 {
+		compiledRules, err := compileNetworkRules(rules, at.fixturesDir)
+		if err != nil {
+			return nil, err
+		}
+		router, err := applyNetworkRules(page, compiledRules)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := router.Stop(); err != nil {
+				at.logger.Error("Error stopping request interception", zap.Error(err))
 			}
-			at.logger.Debug("Wrote time on input", zap.String("input", action.Value))
-		case models2.DownloadResource:
-			at.logger.Debug("Downloading resource", zap.String("selector", action.Value))
-			rawMedia, err := downloadResource(page, action)
-			if err != nil {
-				return nil, fmt.Errorf("error downloading resource: %w", err)
+		}()
+		at.logger.Debug("Network rules applied", zap.Int("rules", len(rules)))
+	}
+
+	pageTimeout, err := taskPageTimeout()
+	if err != nil {
+		return nil, err
+	}
+	runner := driver.NewRunner(at.browserHandlers(page), pageTimeout, func(action models2.TaskAction, err error) error {
+		return at.snapshotFailure(page, action, err)
+	}, at.logger)
+
+	var stopRestoring func() error
+	if taskToRun.Session != nil {
+		stopRestoring, err = at.prepareSession(page, runner, taskToRun, pageTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("error preparing session: %w", err)
+		}
+	}
+
+	// The session storage is restored only on the first document opened.
+	open := func(url string) error {
+		err := at.openUrl(page, url, pageTimeout)
+		if stopRestoring != nil {
+			if err := stopRestoring(); err != nil {
+				at.logger.Error("Error removing session restore script", zap.Error(err))
 			}
-			rawMedias = append(rawMedias, *rawMedia)
-			at.logger.Debug("Downloaded resource", zap.String("selector", action.Value))
-		default:
-			return nil, fmt.Errorf("unknown action type: %s", action.Type.String())
+			stopRestoring = nil
 		}
+
+		return err
+	}
+
+	result = &task.RunResult{
+		Medias:    make([]task.RawMedia, 0),
+		Variables: make(map[string]interface{}),
+	}
+
+	err = runner.Run(newRodPage(page), open, taskToRun, strategies, result)
+	if err != nil {
+		at.Release(taskToRun, result)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Release removes the resource files the storage did not take over.
+func (at *RodAutomator) Release(_ *models2.Task, result *task.RunResult) {
+	for _, media := range result.Medias {
+		if media.ResourceFile != "" {
+			_ = os.Remove(media.ResourceFile)
+		}
+	}
+}
+
+func taskPageTimeout() (time.Duration, error) {
+	pageTimeOutEnv := os.Getenv("BROWSER_PAGE_TIMEOUT_BY_TASK")
+	if strings.TrimSpace(pageTimeOutEnv) == "" {
+		pageTimeOutEnv = "15s"
+	}
+	pageTimeout, err := time.ParseDuration(pageTimeOutEnv)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing page timeout: %w", err)
+	}
+
+	return pageTimeout, nil
+}
+
+// openUrl navigates to the url and waits for the page to be stable.
+func (at *RodAutomator) openUrl(page *rod.Page, url string, pageTimeout time.Duration) error {
+	err := page.Navigate(url)
+	if err != nil {
+		return fmt.Errorf("error navigating to url: %w", err)
+	}
+	at.logger.Debug("Page initialized and navigated to url", zap.String("url", url))
+
+	stablePage := page.Timeout(pageTimeout)
+	err = stablePage.WaitStable(800 * time.Millisecond)
+	stablePage.CancelTimeout()
+	if err != nil {
+		return fmt.Errorf("error waiting for page to be stable: %w", err)
+	}
+	at.logger.Debug("Page is stable and loaded")
+
+	return nil
+}
+
+// sessionTarget returns the session the action saves or loads: the one named
+// in its value or the session of the task.
+func sessionTarget(taskToRun *models2.Task, action models2.TaskAction) (string, time.Duration) {
+	name := action.Value
+	ttl := models2.DefaultSessionTTL
+	if taskToRun.Session != nil {
+		if name == "" {
+			name = taskToRun.Session.Name
+		}
+		ttl = taskToRun.Session.TtlDuration()
+	}
+
+	return name, ttl
+}
+
+func (at *RodAutomator) requireSessionStore() error {
+	if at.sessionStore == nil {
+		return errors.New("session store is not configured")
+	}
+
+	return nil
+}
+
+func (at *RodAutomator) saveSession(page *rod.Page, name string, ttl time.Duration) error {
+	if err := at.requireSessionStore(); err != nil {
+		return err
+	}
+
+	session, err := captureSession(page, name, ttl)
+	if err != nil {
+		return err
+	}
+
+	return at.sessionStore.Save(session, at.ctx)
+}
+
+func (at *RodAutomator) loadSession(page *rod.Page, name string) error {
+	if err := at.requireSessionStore(); err != nil {
+		return err
+	}
+
+	session, err := at.sessionStore.Get(name, at.ctx)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return fmt.Errorf("session %q not found", name)
+	}
+	if session.IsExpired(time.Now()) {
+		return fmt.Errorf("session %q expired at %s", name, session.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return loadSession(page, session)
+}
+
+// prepareSession restores the task session before the page navigates to the
+// task url. When it is missing or stale, the login task is run in the page and
+// its resulting state is saved as the new session. The returned function, if
+// any, stops restoring the session storage on the next documents.
+func (at *RodAutomator) prepareSession(
+	page *rod.Page,
+	runner *driver.Runner,
+	taskToRun *models2.Task,
+	pageTimeout time.Duration,
+) (func() error, error) {
+	if err := at.requireSessionStore(); err != nil {
+		return nil, err
+	}
+
+	taskSession := taskToRun.Session
+	session, err := at.sessionStore.Get(taskSession.Name, at.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if session != nil && !session.IsExpired(time.Now()) {
+		if err = restoreCookies(page, session); err != nil {
+			return nil, err
+		}
+		at.logger.Debug("Session restored", zap.String("session", taskSession.Name))
+
+		return restoreStorageOnNavigation(page, session)
+	}
+
+	if taskSession.LoginTask == nil {
+		at.logger.Warn("Session is missing or stale and the task has no login task", zap.String("session", taskSession.Name))
+		return nil, nil
+	}
+
+	at.logger.Debug("Running login task", zap.String("session", taskSession.Name))
+	err = at.openUrl(page, taskSession.LoginTask.Url, pageTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("error running login task: %w", err)
+	}
+
+	// Medias and variables of the login task are not part of the task result.
+	loginResult := &task.RunResult{Variables: make(map[string]interface{})}
+	err = runner.RunActions(newRodPage(page), taskSession.LoginTask, taskSession.LoginTask.Actions, loginResult)
+	if err != nil {
+		return nil, fmt.Errorf("error running login task: %w", err)
+	}
+
+	err = at.saveSession(page, taskSession.Name, taskSession.TtlDuration())
+	if err != nil {
+		return nil, fmt.Errorf("error saving session: %w", err)
 	}
+	at.logger.Debug("Logged in and saved session", zap.String("session", taskSession.Name))
 
-	return &rawMedias, nil
+	return nil, nil
 }
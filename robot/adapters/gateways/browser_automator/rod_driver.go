@@ -0,0 +1,420 @@
+package browser_automator
+
+import (
+	"automator-go/robot/adapters/gateways/driver"
+	"automator-go/robot/entities/models"
+	"automator-go/robot/entities/validation"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"time"
+)
+
+// dragStartTimeout is how long the drag of a draggable element is waited after
+// the mouse moves it.
+const dragStartTimeout = time.Second
+
+// rodPage provides the driver primitives on a browser page, so the actions
+// shared by the drivers run on it too.
+type rodPage struct {
+	page *rod.Page
+}
+
+func newRodPage(page *rod.Page) *rodPage {
+	return &rodPage{page: page}
+}
+
+func (p *rodPage) Context() context.Context {
+	return p.page.GetContext()
+}
+
+func (p *rodPage) WithContext(ctx context.Context) driver.Page {
+	return newRodPage(p.page.Context(ctx))
+}
+
+func (p *rodPage) Open(url string) error {
+	if err := p.page.Navigate(url); err != nil {
+		return fmt.Errorf("error navigating to url: %w", err)
+	}
+
+	return p.page.WaitLoad()
+}
+
+func (p *rodPage) Url() (string, error) {
+	info, err := p.page.Info()
+	if err != nil {
+		return "", fmt.Errorf("error getting page info: %w", err)
+	}
+
+	return info.URL, nil
+}
+
+func (p *rodPage) Element(selectors []validation.Selector) (driver.Element, error) {
+	element, err := findInChain(p.page, selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rodElement{element: element}, nil
+}
+
+func (p *rodPage) Screenshot() ([]byte, error) {
+	return p.page.Screenshot(false, &proto.PageCaptureScreenshot{
+		Format: proto.PageCaptureScreenshotFormatPng,
+	})
+}
+
+func (p *rodPage) Wait(condition models.WaitCondition) error {
+	return waitFor(p.page, nil, condition)
+}
+
+func (p *rodPage) WaitAfter(condition models.WaitCondition, trigger func() error) error {
+	if condition.Strategy != models.WaitNetworkIdle {
+		if err := trigger(); err != nil {
+			return err
+		}
+
+		return p.Wait(condition)
+	}
+
+	wait := p.page.WaitRequestIdle(networkIdleDuration, nil, nil, nil)
+	if err := trigger(); err != nil {
+		return err
+	}
+	wait()
+
+	return nil
+}
+
+func (p *rodPage) WaitStable() error {
+	timeOutStable, err := stableTimeout()
+	if err != nil {
+		return err
+	}
+
+	return p.page.WaitStable(timeOutStable)
+}
+
+func (p *rodPage) WaitNavigation(trigger func() error) error {
+	wait := p.page.WaitNavigation(proto.PageLifecycleEventNameNetworkAlmostIdle)
+	if err := trigger(); err != nil {
+		return err
+	}
+	wait()
+
+	return nil
+}
+
+func (p *rodPage) WaitUrl(fragment string) error {
+	if fragment != "" {
+		err := p.page.Wait(rod.Eval(`(fragment) => window.location.href.includes(fragment)`, fragment))
+		if err != nil {
+			return fmt.Errorf("error waiting url to match: %w", err)
+		}
+	}
+
+	if err := p.page.WaitLoad(); err != nil {
+		return fmt.Errorf("error waiting page to load: %w", err)
+	}
+
+	return nil
+}
+
+func (p *rodPage) WaitRequestIdle(trigger func() error) error {
+	// Images and media are the lazy loaded content we are waiting for.
+	wait := p.page.WaitRequestIdle(networkIdleDuration, nil, nil, []proto.NetworkResourceType{
+		proto.NetworkResourceTypeWebSocket,
+		proto.NetworkResourceTypeEventSource,
+	})
+	if err := trigger(); err != nil {
+		return err
+	}
+	wait()
+
+	return nil
+}
+
+func (p *rodPage) Scroll(x, y float64, steps int) error {
+	return p.page.Mouse.Scroll(x, y, steps)
+}
+
+func (p *rodPage) ScrollToBottom() (int, error) {
+	result, err := p.page.Eval(`() => {
+		const scrollable = document.scrollingElement;
+		scrollable.scrollTo(scrollable.scrollLeft, scrollable.scrollHeight);
+		return scrollable.scrollHeight;
+	}`)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.Value.Int(), nil
+}
+
+func (p *rodPage) PressKeys(keys []string) error {
+	parsedKeys := parseKeys(keys)
+	modifiers, key := parsedKeys[:len(parsedKeys)-1], parsedKeys[len(parsedKeys)-1]
+
+	return p.page.KeyActions().Press(modifiers...).Type(key).Do()
+}
+
+func (p *rodPage) Evaluate(script string) (interface{}, error) {
+	result, err := p.page.Evaluate(asyncFunction(script))
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Value.Val(), nil
+}
+
+// asyncFunction wraps the script as the body of an async function, awaiting
+// the promise it returns.
+func asyncFunction(script string) *rod.EvalOptions {
+	return rod.Eval("async function() {\n" + script + "\n}").ByPromise()
+}
+
+type rodElement struct {
+	element *rod.Element
+}
+
+func (e *rodElement) Click() error {
+	return e.element.Click(proto.InputMouseButtonLeft, 1)
+}
+
+func (e *rodElement) DoubleClick() error {
+	return e.element.Click(proto.InputMouseButtonLeft, 2)
+}
+
+func (e *rodElement) RightClick() error {
+	return e.element.Click(proto.InputMouseButtonRight, 1)
+}
+
+func (e *rodElement) Hover() error {
+	return e.element.Hover()
+}
+
+func (e *rodElement) Focus() error {
+	return e.element.Focus()
+}
+
+func (e *rodElement) Input(text string) error {
+	return e.element.Input(text)
+}
+
+func (e *rodElement) Clear() error {
+	if err := e.element.SelectAllText(); err != nil {
+		return fmt.Errorf("error selecting text on input: %w", err)
+	}
+
+	return e.element.Input("")
+}
+
+func (e *rodElement) InputTime(value time.Time) error {
+	return e.element.InputTime(value)
+}
+
+func (e *rodElement) Select(options []string) error {
+	return e.element.Select(options, true, rod.SelectorTypeText)
+}
+
+func (e *rodElement) SetFiles(paths []string) error {
+	return e.element.SetFiles(paths)
+}
+
+func (e *rodElement) ScrollIntoView() error {
+	return e.element.ScrollIntoView()
+}
+
+func (e *rodElement) ScrollToBottom() (int, error) {
+	result, err := e.element.Eval(`() => {
+		this.scrollTo(this.scrollLeft, this.scrollHeight);
+		return this.scrollHeight;
+	}`)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.Value.Int(), nil
+}
+
+// DragTo drags the element over the target and drops it there. Draggable
+// elements go through the HTML5 drag events (dragstart, dragover, drop...),
+// which raw mouse events don't trigger, the others are dragged with the
+// mouse, as the drag and drop libraries handling mouse events expect.
+func (e *rodElement) DragTo(target driver.Element) error {
+	rodTarget, ok := target.(*rodElement)
+	if !ok {
+		return errors.New("drop target is not a browser element")
+	}
+
+	shape, err := rodTarget.element.Shape()
+	if err != nil {
+		return fmt.Errorf("error getting drop target shape: %w", err)
+	}
+
+	point := shape.OnePointInside()
+	if point == nil {
+		return fmt.Errorf("drop target is not visible")
+	}
+
+	draggable, err := e.element.Property("draggable")
+	if err != nil {
+		return fmt.Errorf("error getting element draggable: %w", err)
+	}
+	if draggable.Bool() {
+		return e.dragHtml5(*point)
+	}
+
+	return e.dragMouse(*point)
+}
+
+func (e *rodElement) dragMouse(point proto.Point) error {
+	if err := e.element.Hover(); err != nil {
+		return fmt.Errorf("error hovering dragged element: %w", err)
+	}
+
+	mouse := e.element.Page().Mouse
+	if err := mouse.Down(proto.InputMouseButtonLeft, 1); err != nil {
+		return fmt.Errorf("error pressing mouse on dragged element: %w", err)
+	}
+
+	if err := mouse.MoveLinear(point, 10); err != nil {
+		return fmt.Errorf("error moving dragged element: %w", err)
+	}
+
+	if err := mouse.Up(proto.InputMouseButtonLeft, 1); err != nil {
+		return fmt.Errorf("error dropping element: %w", err)
+	}
+
+	return nil
+}
+
+// dragHtml5 intercepts the drag the mouse starts on the element, so its data
+// is dispatched with the drag events over the point.
+func (e *rodElement) dragHtml5(point proto.Point) error {
+	page := e.element.Page()
+	if err := (proto.InputSetInterceptDrags{Enabled: true}).Call(page); err != nil {
+		return fmt.Errorf("error intercepting drags: %w", err)
+	}
+	defer func() {
+		_ = proto.InputSetInterceptDrags{Enabled: false}.Call(page)
+	}()
+
+	// The drag starts while the mouse moves, it never does when the page
+	// cancels it, so the wait stops shortly after the move.
+	dragCtx, stopDrag := context.WithCancel(page.GetContext())
+	defer stopDrag()
+	var data *proto.InputDragData
+	waitDrag := page.Context(dragCtx).EachEvent(func(intercepted *proto.InputDragIntercepted) bool {
+		data = intercepted.Data
+		return true
+	})
+
+	if err := e.element.Hover(); err != nil {
+		return fmt.Errorf("error hovering dragged element: %w", err)
+	}
+
+	mouse := page.Mouse
+	if err := mouse.Down(proto.InputMouseButtonLeft, 1); err != nil {
+		return fmt.Errorf("error pressing mouse on dragged element: %w", err)
+	}
+
+	if err := mouse.MoveLinear(point, 10); err != nil {
+		return fmt.Errorf("error moving dragged element: %w", err)
+	}
+
+	timer := time.AfterFunc(dragStartTimeout, stopDrag)
+	waitDrag()
+	timer.Stop()
+	if data == nil {
+		_ = mouse.Up(proto.InputMouseButtonLeft, 1)
+		return fmt.Errorf("drag did not start within %s, the page may have cancelled it", dragStartTimeout)
+	}
+
+	for _, eventType := range []proto.InputDispatchDragEventType{
+		proto.InputDispatchDragEventTypeDragEnter,
+		proto.InputDispatchDragEventTypeDragOver,
+		proto.InputDispatchDragEventTypeDrop,
+	} {
+		err := proto.InputDispatchDragEvent{Type: eventType, X: point.X, Y: point.Y, Data: data}.Call(page)
+		if err != nil {
+			return fmt.Errorf("error dispatching %s event: %w", eventType, err)
+		}
+	}
+
+	if err := mouse.Up(proto.InputMouseButtonLeft, 1); err != nil {
+		return fmt.Errorf("error dropping element: %w", err)
+	}
+
+	return nil
+}
+
+func (e *rodElement) Wait(condition models.WaitCondition) error {
+	return waitFor(e.element.Page(), e.element, condition)
+}
+
+func (e *rodElement) Evaluate(script string) (interface{}, error) {
+	result, err := e.element.Evaluate(asyncFunction(script))
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Value.Val(), nil
+}
+
+func (e *rodElement) Text() (string, error) {
+	return e.element.Text()
+}
+
+func (e *rodElement) Attribute(name string) (string, bool, error) {
+	value, err := e.element.Attribute(name)
+	if err != nil || value == nil {
+		return "", false, err
+	}
+
+	return *value, true, nil
+}
+
+func (e *rodElement) Screenshot() ([]byte, error) {
+	return e.element.Screenshot(proto.PageCaptureScreenshotFormatPng, 0)
+}
+
+func (e *rodElement) Box() (driver.Box, error) {
+	shape, err := e.element.Shape()
+	if err != nil {
+		return driver.Box{}, fmt.Errorf("error getting element shape: %w", err)
+	}
+
+	box := shape.Box()
+
+	return driver.Box{X: box.X, Y: box.Y, Width: box.Width, Height: box.Height}, nil
+}
+
+func (e *rodElement) Resource() ([]byte, string, error) {
+	if err := e.element.WaitLoad(); err != nil {
+		return nil, "", fmt.Errorf("error waiting resource to load: %w", err)
+	}
+
+	src, err := e.element.Property("currentSrc")
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting element currentSrc: %w", err)
+	}
+
+	resource, err := e.element.Resource()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return resource, src.String(), nil
+}
+
+func (e *rodElement) ResourceFile() (string, string, error) {
+	downloaded, err := downloadElementResource(e.element)
+	if err != nil {
+		return "", "", err
+	}
+
+	return downloaded.file, downloaded.ext, nil
+}
@@ -0,0 +1,206 @@
+package browser_automator
+
+import (
+	models2 "automator-go/robot/entities/models"
+	"automator-go/robot/usecases/task"
+	"bytes"
+	"fmt"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	xdraw "golang.org/x/image/draw"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"sync"
+)
+
+const (
+	// maxVideoFrames limits the frames kept in memory while recording, the
+	// oldest ones are dropped first since the last ones show how the task
+	// ended.
+	maxVideoFrames = 600
+	// screencastQuality is the jpeg quality of the frames sent by the browser.
+	screencastQuality = 70
+	// Gif delays are in hundredths of a second. Browsers play delays under
+	// minGifDelay slower than asked, lastGifDelay holds the last frame.
+	minGifDelay  = 2
+	lastGifDelay = 100
+	// Gifs hold every frame decoded in memory until they are encoded, so
+	// they are sampled down to maxGifFrames and scaled down to maxGifWidth.
+	maxGifFrames = 150
+	maxGifWidth  = 640
+)
+
+// screencastFrame is a jpeg frame sent by the browser, timestamp is in seconds
+// since the epoch.
+type screencastFrame struct {
+	data      []byte
+	timestamp float64
+}
+
+// screencastRecorder records the frames the browser sends while the page
+// content changes, encoding them into a video when it stops.
+type screencastRecorder struct {
+	mu     sync.Mutex
+	page   *rod.Page
+	config *models2.VideoConfig
+	frames []screencastFrame
+	stop   func()
+}
+
+func startScreencastRecorder(page *rod.Page, config *models2.VideoConfig) (*screencastRecorder, error) {
+	recorder := &screencastRecorder{page: page, config: config}
+	recorder.stop = listenPage(page, recorder.onScreencastFrame)
+
+	quality := screencastQuality
+	screencast := proto.PageStartScreencast{
+		Format:  proto.PageStartScreencastFormatJpeg,
+		Quality: &quality,
+	}
+	if config.MaxWidth > 0 {
+		screencast.MaxWidth = &config.MaxWidth
+	}
+	if config.MaxHeight > 0 {
+		screencast.MaxHeight = &config.MaxHeight
+	}
+
+	if err := screencast.Call(page); err != nil {
+		recorder.stop()
+		return nil, fmt.Errorf("error starting screencast: %w", err)
+	}
+
+	return recorder, nil
+}
+
+func (r *screencastRecorder) onScreencastFrame(e *proto.PageScreencastFrame) {
+	// The browser waits for the ack before sending the next frame.
+	_ = proto.PageScreencastFrameAck{SessionID: e.SessionID}.Call(r.page)
+
+	var timestamp float64
+	if e.Metadata != nil {
+		timestamp = float64(e.Metadata.Timestamp)
+	}
+
+	r.addFrame(screencastFrame{data: e.Data, timestamp: timestamp})
+}
+
+func (r *screencastRecorder) addFrame(frame screencastFrame) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.frames) >= maxVideoFrames {
+		r.frames = r.frames[1:]
+	}
+	r.frames = append(r.frames, frame)
+}
+
+// Stop ends the recording and encodes the frames in the format of the config.
+// It returns a nil video when the browser sent no frames.
+func (r *screencastRecorder) Stop() (*task.RunVideo, error) {
+	stopErr := proto.PageStopScreencast{}.Call(r.page)
+	r.stop()
+	if stopErr != nil {
+		return nil, fmt.Errorf("error stopping screencast: %w", stopErr)
+	}
+
+	r.mu.Lock()
+	frames := r.frames
+	r.mu.Unlock()
+
+	return encodeVideo(frames, r.config.FormatName())
+}
+
+func encodeVideo(frames []screencastFrame, format string) (*task.RunVideo, error) {
+	if len(frames) == 0 {
+		return nil, nil
+	}
+
+	switch format {
+	case models2.MjpegVideo:
+		return &task.RunVideo{Ext: models2.MjpegVideo, Data: encodeMjpeg(frames)}, nil
+	case models2.GifVideo:
+		data, err := encodeGif(frames)
+		if err != nil {
+			return nil, err
+		}
+		return &task.RunVideo{Ext: models2.GifVideo, Data: data}, nil
+	}
+
+	return nil, fmt.Errorf("unknown video format %q", format)
+}
+
+// encodeMjpeg concatenates the frames, players read mjpeg as a stream of
+// jpeg images.
+func encodeMjpeg(frames []screencastFrame) []byte {
+	var video bytes.Buffer
+	for _, frame := range frames {
+		video.Write(frame.data)
+	}
+
+	return video.Bytes()
+}
+
+// encodeGif plays the frames at the pace they were sent. They are mapped to
+// the Plan 9 palette, gifs only have 256 colors. The frames differ in size
+// when the viewport changes, the gif fits the biggest.
+func encodeGif(frames []screencastFrame) ([]byte, error) {
+	frames = sampleGifFrames(frames)
+	animation := &gif.GIF{Config: image.Config{ColorModel: color.Palette(palette.Plan9)}}
+	for i, frame := range frames {
+		img, err := jpeg.Decode(bytes.NewReader(frame.data))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding frame %d: %w", i, err)
+		}
+
+		paletted := image.NewPaletted(gifBounds(img.Bounds()), palette.Plan9)
+		xdraw.ApproxBiLinear.Scale(paletted, paletted.Rect, img, img.Bounds(), xdraw.Src, nil)
+
+		delay := lastGifDelay
+		if i < len(frames)-1 {
+			delay = int((frames[i+1].timestamp - frame.timestamp) * 100)
+			if delay < minGifDelay {
+				delay = minGifDelay
+			}
+		}
+
+		animation.Config.Width = max(animation.Config.Width, paletted.Rect.Dx())
+		animation.Config.Height = max(animation.Config.Height, paletted.Rect.Dy())
+		animation.Image = append(animation.Image, paletted)
+		animation.Delay = append(animation.Delay, delay)
+	}
+
+	var video bytes.Buffer
+	if err := gif.EncodeAll(&video, animation); err != nil {
+		return nil, fmt.Errorf("error encoding gif: %w", err)
+	}
+
+	return video.Bytes(), nil
+}
+
+// sampleGifFrames keeps maxGifFrames frames evenly spread over the recording,
+// with the first and the last ones. The kept frames last until the next one.
+func sampleGifFrames(frames []screencastFrame) []screencastFrame {
+	if len(frames) <= maxGifFrames {
+		return frames
+	}
+
+	sampled := make([]screencastFrame, 0, maxGifFrames)
+	for i := 0; i < maxGifFrames; i++ {
+		sampled = append(sampled, frames[i*(len(frames)-1)/(maxGifFrames-1)])
+	}
+
+	return sampled
+}
+
+// gifBounds scales the bounds of a frame down to maxGifWidth, keeping its
+// aspect ratio.
+func gifBounds(bounds image.Rectangle) image.Rectangle {
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxGifWidth {
+		return image.Rect(0, 0, width, height)
+	}
+
+	return image.Rect(0, 0, maxGifWidth, max(1, height*maxGifWidth/width))
+}
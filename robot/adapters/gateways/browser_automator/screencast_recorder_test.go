@@ -0,0 +1,156 @@
+package browser_automator
+
+import (
+	models2 "automator-go/robot/entities/models"
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"testing"
+)
+
+func jpegFrame(t *testing.T, width, height int, timestamp float64) screencastFrame {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, x%height, color.RGBA{R: 255, A: 255})
+	}
+
+	var data bytes.Buffer
+	if err := jpeg.Encode(&data, img, nil); err != nil {
+		t.Fatalf("error encoding frame: %v", err)
+	}
+
+	return screencastFrame{data: data.Bytes(), timestamp: timestamp}
+}
+
+func TestEncodeVideo(t *testing.T) {
+	frames := []screencastFrame{
+		jpegFrame(t, 64, 48, 1700000000),
+		jpegFrame(t, 64, 48, 1700000000.5),
+		// The viewport changed.
+		jpegFrame(t, 48, 64, 1700000000.501),
+	}
+
+	tests := []struct {
+		name       string
+		frames     []screencastFrame
+		format     string
+		wantVideo  bool
+		wantDelays []int
+		wantErr    bool
+	}{
+		{
+			name:       "Gif",
+			frames:     frames,
+			format:     models2.GifVideo,
+			wantVideo:  true,
+			wantDelays: []int{50, minGifDelay, lastGifDelay},
+		},
+		{
+			name:      "Mjpeg",
+			frames:    frames,
+			format:    models2.MjpegVideo,
+			wantVideo: true,
+		},
+		{
+			name:      "Without frames",
+			frames:    nil,
+			format:    models2.GifVideo,
+			wantVideo: false,
+		},
+		{
+			name:    "Invalid frame",
+			frames:  []screencastFrame{{data: []byte("not a jpeg")}},
+			format:  models2.GifVideo,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video, err := encodeVideo(tt.frames, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("encodeVideo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (video != nil) != tt.wantVideo {
+				t.Fatalf("encodeVideo() = %v, wantVideo %v", video, tt.wantVideo)
+			}
+			if video == nil {
+				return
+			}
+			if video.Ext != tt.format {
+				t.Errorf("video extension = %v, want %v", video.Ext, tt.format)
+			}
+
+			switch tt.format {
+			case models2.GifVideo:
+				animation, err := gif.DecodeAll(bytes.NewReader(video.Data))
+				if err != nil {
+					t.Fatalf("error decoding gif: %v", err)
+				}
+				if len(animation.Image) != len(tt.frames) {
+					t.Errorf("gif frames = %d, want %d", len(animation.Image), len(tt.frames))
+				}
+				for i, delay := range tt.wantDelays {
+					if animation.Delay[i] != delay {
+						t.Errorf("gif delay %d = %d, want %d", i, animation.Delay[i], delay)
+					}
+				}
+				if animation.Config.Width != 64 || animation.Config.Height != 64 {
+					t.Errorf("gif size = %dx%d, want 64x64", animation.Config.Width, animation.Config.Height)
+				}
+			case models2.MjpegVideo:
+				if !bytes.HasPrefix(video.Data, tt.frames[0].data) || !bytes.HasSuffix(video.Data, tt.frames[len(tt.frames)-1].data) {
+					t.Errorf("mjpeg does not hold the frames in order")
+				}
+			}
+		})
+	}
+}
+
+func TestScreencastRecorder_AddFrame(t *testing.T) {
+	recorder := &screencastRecorder{}
+	for i := 0; i < maxVideoFrames+10; i++ {
+		recorder.addFrame(screencastFrame{timestamp: float64(i)})
+	}
+
+	if len(recorder.frames) != maxVideoFrames {
+		t.Fatalf("frames = %d, want %d", len(recorder.frames), maxVideoFrames)
+	}
+	if recorder.frames[0].timestamp != 10 {
+		t.Errorf("first frame = %v, want the oldest frames dropped", recorder.frames[0].timestamp)
+	}
+}
+
+func TestEncodeGif_Bounded(t *testing.T) {
+	frame := jpegFrame(t, maxGifWidth*2, 200, 1700000000)
+	frames := make([]screencastFrame, 0, maxGifFrames*3)
+	for i := 0; i < maxGifFrames*3; i++ {
+		frames = append(frames, screencastFrame{data: frame.data, timestamp: 1700000000 + float64(i)/10})
+	}
+
+	data, err := encodeGif(frames)
+	if err != nil {
+		t.Fatalf("encodeGif() error = %v", err)
+	}
+	animation, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("error decoding gif: %v", err)
+	}
+
+	if len(animation.Image) != maxGifFrames {
+		t.Errorf("gif frames = %d, want %d", len(animation.Image), maxGifFrames)
+	}
+	if animation.Config.Width != maxGifWidth || animation.Config.Height != 100 {
+		t.Errorf("gif size = %dx%d, want %dx100", animation.Config.Width, animation.Config.Height, maxGifWidth)
+	}
+	// The sampled frames still play for as long as the recording.
+	duration := 0
+	for _, delay := range animation.Delay[:len(animation.Delay)-1] {
+		duration += delay
+	}
+	if want := (len(frames) - 1) * 10; duration < want-maxGifFrames || duration > want {
+		t.Errorf("gif duration = %d, want about %d", duration, want)
+	}
+}
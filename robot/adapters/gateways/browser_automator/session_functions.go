@@ -0,0 +1,154 @@
+package browser_automator
+
+import (
+	"automator-go/robot/entities/models"
+	"encoding/json"
+	"fmt"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"strings"
+	"time"
+)
+
+const dumpStorageJS = `() => {
+	const dump = (storage) => {
+		const items = {};
+		for (let i = 0; i < storage.length; i++) {
+			const key = storage.key(i);
+			items[key] = storage.getItem(key);
+		}
+		return items;
+	};
+	return {origin: location.origin, local: dump(localStorage), session: dump(sessionStorage)};
+}`
+
+const restoreStorageJS = `(storage) => {
+	const items = storage[location.origin];
+	if (!items) {
+		return;
+	}
+	for (const [key, value] of Object.entries(items.local || {})) {
+		localStorage.setItem(key, value);
+	}
+	for (const [key, value] of Object.entries(items.session || {})) {
+		sessionStorage.setItem(key, value);
+	}
+}`
+
+type originStorage struct {
+	Origin  string            `json:"origin"`
+	Local   map[string]string `json:"local"`
+	Session map[string]string `json:"session"`
+}
+
+// captureSession reads the cookies of the browser context of the page and the
+// storage of the origin the page is in.
+func captureSession(page *rod.Page, name string, ttl time.Duration) (*models.Session, error) {
+	cookies, err := page.Browser().GetCookies()
+	if err != nil {
+		return nil, fmt.Errorf("error getting cookies: %w", err)
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		Name:      name,
+		Cookies:   make([]models.SessionCookie, 0, len(cookies)),
+		Storage:   make(map[string]models.SessionStorage),
+		SavedAt:   now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	for _, cookie := range cookies {
+		sessionCookie := models.SessionCookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			HttpOnly: cookie.HTTPOnly,
+			Secure:   cookie.Secure,
+			SameSite: string(cookie.SameSite),
+		}
+		if !cookie.Session {
+			sessionCookie.Expires = float64(cookie.Expires)
+		}
+		session.Cookies = append(session.Cookies, sessionCookie)
+	}
+
+	result, err := page.Eval(dumpStorageJS)
+	if err != nil {
+		return nil, fmt.Errorf("error reading storage: %w", err)
+	}
+
+	var storage originStorage
+	if err = result.Value.Unmarshal(&storage); err != nil {
+		return nil, fmt.Errorf("error reading storage: %w", err)
+	}
+
+	// Pages without an origin, like about:blank, have no storage to keep.
+	if strings.HasPrefix(storage.Origin, "http") {
+		session.Storage[storage.Origin] = models.SessionStorage{Local: storage.Local, Session: storage.Session}
+	}
+
+	return session, nil
+}
+
+func restoreCookies(page *rod.Page, session *models.Session) error {
+	if len(session.Cookies) == 0 {
+		return nil
+	}
+
+	cookies := make([]*proto.NetworkCookieParam, 0, len(session.Cookies))
+	for _, cookie := range session.Cookies {
+		cookies = append(cookies, &proto.NetworkCookieParam{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HttpOnly,
+			SameSite: proto.NetworkCookieSameSite(cookie.SameSite),
+			Expires:  proto.TimeSinceEpoch(cookie.Expires),
+		})
+	}
+
+	if err := page.Browser().SetCookies(cookies); err != nil {
+		return fmt.Errorf("error setting cookies: %w", err)
+	}
+
+	return nil
+}
+
+// restoreStorageOnNavigation fills the storage of the session origins as soon
+// as a document of them is created, before the scripts of the page run. The
+// returned function stops doing it for the next documents.
+func restoreStorageOnNavigation(page *rod.Page, session *models.Session) (func() error, error) {
+	storage, err := json.Marshal(session.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling storage: %w", err)
+	}
+
+	remove, err := page.EvalOnNewDocument(fmt.Sprintf("(%s)(%s)", restoreStorageJS, storage))
+	if err != nil {
+		return nil, fmt.Errorf("error restoring storage: %w", err)
+	}
+
+	return remove, nil
+}
+
+// loadSession restores the session in the current page and reloads it, so
+// the site sees the restored state.
+func loadSession(page *rod.Page, session *models.Session) error {
+	if err := restoreCookies(page, session); err != nil {
+		return err
+	}
+
+	if _, err := page.Eval(restoreStorageJS, session.Storage); err != nil {
+		return fmt.Errorf("error restoring storage: %w", err)
+	}
+
+	if err := page.Reload(); err != nil {
+		return fmt.Errorf("error reloading page: %w", err)
+	}
+
+	return page.WaitLoad()
+}
@@ -0,0 +1,93 @@
+package browser_automator
+
+import (
+	"automator-go/robot/adapters/gateways/driver"
+	"automator-go/robot/entities/models"
+	"fmt"
+	"github.com/go-rod/rod"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	networkIdleDuration = 500 * time.Millisecond
+)
+
+func stableTimeout() (time.Duration, error) {
+	timeOutStableEnv := os.Getenv("BROWSER_WAIT_STABLE_TIMEOUT")
+	if strings.TrimSpace(timeOutStableEnv) == "" {
+		timeOutStableEnv = "5s"
+	}
+
+	timeOutStable, err := time.ParseDuration(timeOutStableEnv)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing timeout stable env: %w", err)
+	}
+
+	return timeOutStable, nil
+}
+
+// waitFor blocks until the condition is met. The element may be nil for
+// actions that don't target one, in which case the condition is checked on
+// the page.
+func waitFor(page *rod.Page, element *rod.Element, condition models.WaitCondition) error {
+	switch condition.Strategy {
+	case models.WaitAttached:
+		// Elements are already attached once they are found.
+		return nil
+	case models.WaitVisible:
+		if element == nil {
+			return fmt.Errorf("visible wait strategy requires an element")
+		}
+		if err := element.WaitVisible(); err != nil {
+			return fmt.Errorf("error waiting element to be visible: %w", err)
+		}
+	case models.WaitStable:
+		if element != nil {
+			if err := element.WaitStableRAF(); err != nil {
+				return fmt.Errorf("error waiting element to be stable: %w", err)
+			}
+
+			return nil
+		}
+
+		timeOutStable, err := stableTimeout()
+		if err != nil {
+			return err
+		}
+		if err = page.WaitStable(timeOutStable); err != nil {
+			return fmt.Errorf("error waiting page to be stable: %w", err)
+		}
+	case models.WaitNetworkIdle:
+		page.WaitRequestIdle(networkIdleDuration, nil, nil, nil)()
+	case models.WaitPredicate:
+		if strings.TrimSpace(condition.Predicate) == "" {
+			return fmt.Errorf("predicate wait strategy requires a predicate")
+		}
+
+		var err error
+		if element != nil {
+			err = element.Wait(rod.Eval(condition.Predicate))
+		} else {
+			err = page.Wait(rod.Eval(condition.Predicate))
+		}
+		if err != nil {
+			return fmt.Errorf("error waiting predicate: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown wait strategy: %s", condition.Strategy.String())
+	}
+
+	return nil
+}
+
+// waitForElement waits for the element to be visible when the action has no
+// wait condition.
+func waitForElement(page driver.Page, action models.TaskAction) error {
+	if action.WaitFor == nil {
+		action.WaitFor = &models.WaitCondition{Strategy: models.WaitVisible}
+	}
+
+	return driver.WaitForElement(page, action)
+}
@@ -13,14 +13,16 @@ import (
 
 type TaskQueueConsumerFromJSONFile struct {
 	taskController *tasks.TaskController
+	scriptPolicy   models.ScriptPolicy
 	logger         *otelzap.LoggerWithCtx
 }
 
 func NewTaskQueueConsumerFromJSONFile(
 	taskController *tasks.TaskController,
+	scriptPolicy models.ScriptPolicy,
 	logger *otelzap.LoggerWithCtx,
 ) TaskQueueConsumerFromJSONFile {
-	return TaskQueueConsumerFromJSONFile{taskController: taskController, logger: logger}
+	return TaskQueueConsumerFromJSONFile{taskController: taskController, scriptPolicy: scriptPolicy, logger: logger}
 }
 
 func (t TaskQueueConsumerFromJSONFile) ConsumeTasks() []error {
@@ -37,10 +39,27 @@ func (t TaskQueueConsumerFromJSONFile) ConsumeTasks() []error {
 		return []error{err}
 	}
 
-	wg := sync.WaitGroup{}
-	wg.Add(len(tasksToProcess))
-	errorsChan := make(chan error, len(tasksToProcess))
+	t.logger.Debug("Validating tasks")
+	validTasks := make([]models.Task, 0, len(tasksToProcess))
+	validationErrors := make([]error, 0)
 	for _, task := range tasksToProcess {
+		err := task.Validate()
+		if err == nil {
+			err = t.taskController.CheckStrategies(&task, t.scriptPolicy, models.FileSource)
+		}
+		if err != nil {
+			t.logger.Error("Invalid task", zap.String("task_id", task.Id), zap.Error(err))
+			validationErrors = append(validationErrors, fmt.Errorf("invalid task: %s: %w", task.Id, err))
+			continue
+		}
+
+		validTasks = append(validTasks, task)
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(validTasks))
+	errorsChan := make(chan error, len(validTasks))
+	for _, task := range validTasks {
 		taskToProcess := task
 		go func() {
 			defer func() {
@@ -60,7 +79,7 @@ func (t TaskQueueConsumerFromJSONFile) ConsumeTasks() []error {
 
 	t.logger.Debug("Finished processing all tasks")
 
-	errors := make([]error, 0)
+	errors := validationErrors
 	for {
 		err, ok := <-errorsChan
 		if !ok {
@@ -14,6 +14,7 @@ import (
 type RabbitTaskQueueConsumer struct {
 	ch             *amqp.Channel
 	taskController *tasks.TaskController
+	scriptPolicy   models.ScriptPolicy
 	logger         *otelzap.LoggerWithCtx
 	ctx            context.Context
 	queueName      string
@@ -23,6 +24,7 @@ type RabbitTaskQueueConsumer struct {
 func NewRabbitTaskQueueConsumer(
 	ch *amqp.Channel,
 	taskController *tasks.TaskController,
+	scriptPolicy models.ScriptPolicy,
 	logger *otelzap.LoggerWithCtx,
 	ctx context.Context,
 	queueName string,
@@ -31,6 +33,7 @@ func NewRabbitTaskQueueConsumer(
 	return RabbitTaskQueueConsumer{
 		ch:             ch,
 		taskController: taskController,
+		scriptPolicy:   scriptPolicy,
 		logger:         logger,
 		ctx:            ctx,
 		queueName:      queueName,
@@ -78,6 +81,20 @@ func (t RabbitTaskQueueConsumer) ConsumeTasks() []error {
 				continue
 			}
 
+			err = taskToProcess.Validate()
+			if err == nil {
+				err = t.taskController.CheckStrategies(&taskToProcess, t.scriptPolicy, models.QueueSource)
+			}
+			if err != nil {
+				t.logger.Error("Invalid task", zap.String("task_id", taskToProcess.Id), zap.Error(err))
+				err := delivery.Nack(false, false)
+				if err != nil {
+					t.logger.Error("Error nacknowledging message", zap.Error(err))
+				}
+
+				continue
+			}
+
 			// Process tasks concurrently
 			go func() {
 				err = t.taskController.ProcessTask(&taskToProcess)
@@ -0,0 +1,109 @@
+package differ
+
+import (
+	"automator-go/robot/usecases/differ"
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// pixelTolerance is the difference of a color channel, out of 255, under
+// which the pixels are taken for the same. It absorbs the noise of the
+// antialiasing and of the image compression.
+const pixelTolerance = 24
+
+// changedColor marks the changed pixels in the diff image.
+var changedColor = color.NRGBA{R: 255, A: 255}
+
+type PixelDiffHandler struct {
+	logger *otelzap.LoggerWithCtx
+}
+
+func NewPixelDiffHandler(logger *otelzap.LoggerWithCtx) *PixelDiffHandler {
+	return &PixelDiffHandler{
+		logger: logger,
+	}
+}
+
+// Diff draws the capture faded to gray with the changed pixels in red. Images
+// of different sizes are compared on a canvas fitting both, the pixels only
+// covered by one of them are changed.
+func (h *PixelDiffHandler) Diff(baseline []byte, capture []byte) (*differ.ImageDiff, error) {
+	baselineImage, _, err := image.Decode(bytes.NewReader(baseline))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding baseline: %w", err)
+	}
+	captureImage, _, err := image.Decode(bytes.NewReader(capture))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding capture: %w", err)
+	}
+
+	bounds := image.Rect(0, 0,
+		max(baselineImage.Bounds().Dx(), captureImage.Bounds().Dx()),
+		max(baselineImage.Bounds().Dy(), captureImage.Bounds().Dy()),
+	)
+	if bounds.Empty() {
+		return nil, errors.New("images have no pixels")
+	}
+
+	diffImage := image.NewNRGBA(bounds)
+	changed := 0
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			baselinePixel, inBaseline := pixelAt(baselineImage, x, y)
+			capturePixel, inCapture := pixelAt(captureImage, x, y)
+			if inBaseline && inCapture && !pixelChanged(baselinePixel, capturePixel) {
+				diffImage.Set(x, y, faded(capturePixel))
+				continue
+			}
+			changed++
+			diffImage.Set(x, y, changedColor)
+		}
+	}
+
+	var encoded bytes.Buffer
+	if err = png.Encode(&encoded, diffImage); err != nil {
+		return nil, fmt.Errorf("error encoding diff image: %w", err)
+	}
+	ratio := float64(changed) / float64(bounds.Dx()*bounds.Dy())
+	h.logger.Debug("Compared capture with baseline", zap.Int("changed_pixels", changed), zap.Float64("ratio", ratio))
+
+	return &differ.ImageDiff{Image: encoded.Bytes(), Ratio: ratio}, nil
+}
+
+// pixelAt reads the pixel at x, y of the image from its top left corner,
+// reporting whether the image covers it.
+func pixelAt(img image.Image, x int, y int) (color.Color, bool) {
+	point := img.Bounds().Min.Add(image.Pt(x, y))
+	if !point.In(img.Bounds()) {
+		return nil, false
+	}
+
+	return img.At(point.X, point.Y), true
+}
+
+func pixelChanged(a color.Color, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	for _, channels := range [][2]uint32{{r1, r2}, {g1, g2}, {b1, b2}, {a1, a2}} {
+		difference := int(channels[0]>>8) - int(channels[1]>>8)
+		if difference > pixelTolerance || difference < -pixelTolerance {
+			return true
+		}
+	}
+
+	return false
+}
+
+// faded lightens the gray level of the unchanged pixels, so the changes stand
+// out while the element is still recognizable.
+func faded(c color.Color) color.Color {
+	gray := color.GrayModel.Convert(c).(color.Gray)
+
+	return color.Gray{Y: 255 - (255-gray.Y)/4}
+}
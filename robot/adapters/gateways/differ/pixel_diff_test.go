@@ -0,0 +1,70 @@
+package differ
+
+import (
+	"bytes"
+	"context"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"testing"
+)
+
+func encodePng(t *testing.T, img image.Image) []byte {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		t.Fatalf("error encoding png: %v", err)
+	}
+
+	return encoded.Bytes()
+}
+
+func TestPixelDiffHandler_Diff(t *testing.T) {
+	logger := otelzap.New(zap.NewExample(), otelzap.WithMinLevel(zap.DebugLevel)).Ctx(context.Background())
+
+	baseline := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	draw.Draw(baseline, baseline.Bounds(), image.White, image.Point{}, draw.Src)
+	// Changes under the tolerance, like compression noise, are ignored.
+	noisy := image.NewRGBA(baseline.Bounds())
+	draw.Draw(noisy, noisy.Bounds(), &image.Uniform{C: color.RGBA{R: 250, G: 245, B: 255, A: 255}}, image.Point{}, draw.Src)
+	changed := image.NewRGBA(baseline.Bounds())
+	draw.Draw(changed, changed.Bounds(), baseline, image.Point{}, draw.Src)
+	draw.Draw(changed, image.Rect(0, 0, 5, 5), image.Black, image.Point{}, draw.Src)
+	taller := image.NewRGBA(image.Rect(0, 0, 10, 20))
+	draw.Draw(taller, taller.Bounds(), image.White, image.Point{}, draw.Src)
+
+	tests := []struct {
+		name      string
+		baseline  []byte
+		capture   []byte
+		wantRatio float64
+		wantErr   bool
+	}{
+		{name: "Same image", baseline: encodePng(t, baseline), capture: encodePng(t, baseline), wantRatio: 0},
+		{name: "Noise", baseline: encodePng(t, baseline), capture: encodePng(t, noisy), wantRatio: 0},
+		{name: "Changed quarter", baseline: encodePng(t, baseline), capture: encodePng(t, changed), wantRatio: 0.25},
+		{name: "Different sizes", baseline: encodePng(t, baseline), capture: encodePng(t, taller), wantRatio: 0.5},
+		{name: "Invalid capture", baseline: encodePng(t, baseline), capture: []byte("invalid"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewPixelDiffHandler(&logger)
+			got, err := handler.Diff(tt.baseline, tt.capture)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PixelDiffHandler.Diff() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Ratio != tt.wantRatio {
+				t.Errorf("PixelDiffHandler.Diff() ratio = %v, want %v", got.Ratio, tt.wantRatio)
+			}
+			if _, err := png.Decode(bytes.NewReader(got.Image)); err != nil {
+				t.Errorf("PixelDiffHandler.Diff() image is not a png: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,611 @@
+package driver
+
+import (
+	"automator-go/robot/entities/models"
+	"automator-go/robot/entities/validation"
+	"automator-go/robot/usecases/task"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	_ "golang.org/x/image/webp"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExtractText returns the text of the element with the whitespace collapsed,
+// as it reads on the page.
+func ExtractText(page Page, action models.TaskAction) (string, error) {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return "", err
+	}
+
+	text, err := element.Text()
+	if err != nil {
+		return "", fmt.Errorf("error getting element text: %w", err)
+	}
+
+	return strings.Join(strings.Fields(text), " "), nil
+}
+
+// FollowLink opens the href of the element, without clicking it.
+func FollowLink(page Page, action models.TaskAction) error {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return err
+	}
+
+	href, ok, err := element.Attribute("href")
+	if err != nil {
+		return fmt.Errorf("error getting link href: %w", err)
+	}
+	if !ok {
+		return errors.New("element is not a link")
+	}
+
+	linkUrl, err := ResolveUrl(page, href)
+	if err != nil {
+		return err
+	}
+
+	return page.Open(linkUrl)
+}
+
+func WaitForElement(page Page, action models.TaskAction) error {
+	_, err := FindElement(page, action)
+
+	return err
+}
+
+// WaitSeconds sleeps the seconds of the action, until the context is done.
+// Waits longer than the timeout of the action fail right away, a zero timeout
+// doesn't bound them.
+func WaitSeconds(ctx context.Context, action models.TaskAction, timeout time.Duration) error {
+	seconds, err := strconv.ParseInt(action.Value, 10, 64)
+	if err != nil || seconds < 1 {
+		return fmt.Errorf("seconds must be a positive integer, got %q", action.Value)
+	}
+
+	wait := time.Duration(seconds) * time.Second
+	if timeout > 0 && wait > timeout {
+		return fmt.Errorf("waiting %s exceeds the action timeout of %s", wait, timeout)
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("error waiting %s: %w", wait, ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// DownloadResource captures the resource of the element. Images are the media
+// themselves, other resources use the screenshot of the element as media, so
+// they need a driver that renders the page.
+func DownloadResource(page Page, action models.TaskAction) (*task.RawMedia, error) {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, resourceUrl, err := element.Resource()
+	if err != nil {
+		return nil, fmt.Errorf("error getting element resource: %w", err)
+	}
+
+	media, err := pngImage(resource)
+	if err != nil {
+		media, err = element.Screenshot()
+		if err != nil {
+			return nil, fmt.Errorf("error capturing element: %w", err)
+		}
+	}
+
+	screenshot, err := page.Screenshot()
+	if err != nil && !errors.Is(err, ErrNotSupported) {
+		return nil, fmt.Errorf("error capturing page: %w", err)
+	}
+
+	box, err := element.Box()
+	if err != nil && !errors.Is(err, ErrNotSupported) {
+		return nil, fmt.Errorf("error getting element box: %w", err)
+	}
+
+	pageUrl, err := page.Url()
+	if err != nil {
+		return nil, err
+	}
+
+	return &task.RawMedia{
+		Ext:        resourceExtension(resourceUrl),
+		Media:      media,
+		Screenshot: screenshot,
+		Resource:   resource,
+		Height:     box.Height,
+		Width:      box.Width,
+		X:          box.X,
+		Y:          box.Y,
+		Url:        pageUrl,
+	}, nil
+}
+
+// ResolveUrl resolves a reference found in the page, like an href, against
+// the url of the page.
+func ResolveUrl(page Page, reference string) (string, error) {
+	pageUrl, err := page.Url()
+	if err != nil {
+		return "", err
+	}
+
+	base, err := url.Parse(pageUrl)
+	if err != nil {
+		return "", fmt.Errorf("error parsing page url: %w", err)
+	}
+	resolved, err := base.Parse(strings.TrimSpace(reference))
+	if err != nil {
+		return "", fmt.Errorf("error parsing url %q: %w", reference, err)
+	}
+
+	return resolved.String(), nil
+}
+
+// pngImage decodes the image and encodes it as png, the format the medias
+// are hashed and stored in.
+func pngImage(resource []byte) ([]byte, error) {
+	decoded, _, err := image.Decode(bytes.NewReader(resource))
+	if err != nil {
+		return nil, err
+	}
+
+	var encoded bytes.Buffer
+	if err = png.Encode(&encoded, decoded); err != nil {
+		return nil, err
+	}
+
+	return encoded.Bytes(), nil
+}
+
+func resourceExtension(resourceUrl string) string {
+	parsedUrl, err := url.Parse(resourceUrl)
+	if err != nil {
+		return "bin"
+	}
+
+	extension := strings.TrimPrefix(strings.ToLower(path.Ext(parsedUrl.Path)), ".")
+	if extension == "" {
+		return "bin"
+	}
+
+	return extension
+}
+
+func Click(page Page, action models.TaskAction) error {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return err
+	}
+
+	if err = element.Click(); err != nil {
+		return fmt.Errorf("error clicking element: %w", err)
+	}
+
+	return nil
+}
+
+// Navigate clicks the element and waits for the navigation it starts. A
+// network idle wait applies to the navigation instead of the element, so it
+// listens to the requests from before the click.
+func Navigate(page Page, action models.TaskAction) error {
+	waitIdle := action.WaitFor != nil && action.WaitFor.Strategy == models.WaitNetworkIdle
+	search := action
+	if waitIdle {
+		search.WaitFor = nil
+	}
+
+	element, err := FindElement(page, search)
+	if err != nil {
+		return err
+	}
+
+	navigate := func() error {
+		return page.WaitNavigation(func() error {
+			if err := element.Click(); err != nil {
+				return fmt.Errorf("error clicking element: %w", err)
+			}
+
+			return nil
+		})
+	}
+	if waitIdle {
+		err = page.WaitAfter(*action.WaitFor, navigate)
+	} else {
+		err = navigate()
+	}
+	if err != nil {
+		return fmt.Errorf("error navigating: %w", err)
+	}
+
+	return nil
+}
+
+func DoubleClick(page Page, action models.TaskAction) error {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return err
+	}
+
+	if err = element.DoubleClick(); err != nil {
+		return fmt.Errorf("error double clicking element: %w", err)
+	}
+
+	return nil
+}
+
+func RightClick(page Page, action models.TaskAction) error {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return err
+	}
+
+	if err = element.RightClick(); err != nil {
+		return fmt.Errorf("error right clicking element: %w", err)
+	}
+
+	return nil
+}
+
+func Hover(page Page, action models.TaskAction) error {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return err
+	}
+
+	if err = element.Hover(); err != nil {
+		return fmt.Errorf("error hovering element: %w", err)
+	}
+
+	return nil
+}
+
+// DragAndDrop drags the element of the selector and drops it on the element
+// of the value.
+func DragAndDrop(page Page, action models.TaskAction) error {
+	source, err := FindElement(page, action)
+	if err != nil {
+		return err
+	}
+
+	target, err := FindElement(page, models.TaskAction{Value: action.Value, WaitFor: action.WaitFor})
+	if err != nil {
+		return fmt.Errorf("error getting drop target: %w", err)
+	}
+
+	if err = source.DragTo(target); err != nil {
+		return fmt.Errorf("error dragging element: %w", err)
+	}
+
+	return nil
+}
+
+func WriteInput(page Page, action models.TaskAction) error {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return err
+	}
+
+	if err = element.Input(action.Value); err != nil {
+		return fmt.Errorf("error writing input: %w", err)
+	}
+
+	return nil
+}
+
+func ClearInput(page Page, action models.TaskAction) error {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return err
+	}
+
+	if err = element.Clear(); err != nil {
+		return fmt.Errorf("error clearing input: %w", err)
+	}
+
+	return nil
+}
+
+func SelectOptions(page Page, action models.TaskAction) error {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return err
+	}
+
+	if err = element.Select(strings.Split(action.Value, ",")); err != nil {
+		return fmt.Errorf("error selecting option: %w", err)
+	}
+
+	return nil
+}
+
+func WriteTime(page Page, action models.TaskAction) error {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return err
+	}
+
+	timeToWrite, err := time.Parse(models.TimeLayout, action.Value)
+	if err != nil {
+		return fmt.Errorf("error parsing time: %w", err)
+	}
+
+	if err = element.InputTime(timeToWrite); err != nil {
+		return fmt.Errorf("error writing time: %w", err)
+	}
+
+	return nil
+}
+
+// UploadFile sets the comma separated paths of the value on the file input.
+func UploadFile(page Page, action models.TaskAction) error {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return err
+	}
+
+	paths := strings.Split(action.Value, ",")
+	for i, path := range paths {
+		paths[i] = strings.TrimSpace(path)
+	}
+
+	if err = element.SetFiles(paths); err != nil {
+		return fmt.Errorf("error uploading files: %w", err)
+	}
+
+	return nil
+}
+
+// PressKey presses the key combination of the value, on the selected element
+// when there is one.
+func PressKey(page Page, action models.TaskAction) error {
+	if err := validation.ValidateKeyCombination(action.Value); err != nil {
+		return err
+	}
+
+	keys := strings.Split(action.Value, validation.KeySeparator)
+	for i, key := range keys {
+		keys[i] = strings.TrimSpace(key)
+	}
+
+	if action.Selector != "" {
+		element, err := FindElement(page, action)
+		if err != nil {
+			return err
+		}
+
+		if err = element.Focus(); err != nil {
+			return fmt.Errorf("error focusing element: %w", err)
+		}
+	}
+
+	if err := page.PressKeys(keys); err != nil {
+		return fmt.Errorf("error pressing keys: %w", err)
+	}
+
+	return nil
+}
+
+// scrollBy dispatches one mouse wheel event of scrollStep pixels per step in
+// the given direction, over the selected element when there is one so
+// scrollable containers get them instead of the page.
+func scrollBy(page Page, action models.TaskAction, directionX, directionY float64) error {
+	parsedSteps, err := strconv.ParseInt(action.Value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("error parsing scroll steps: %w", err)
+	}
+
+	if action.Selector != "" {
+		element, err := FindElement(page, action)
+		if err != nil {
+			return err
+		}
+
+		if err = element.Hover(); err != nil {
+			return fmt.Errorf("error hovering scrollable element: %w", err)
+		}
+	} else if action.WaitFor != nil {
+		if err = page.Wait(*action.WaitFor); err != nil {
+			return err
+		}
+	}
+
+	steps := int(parsedSteps)
+	offset := float64(steps) * scrollStep
+	if err = page.Scroll(directionX*offset, directionY*offset, steps); err != nil {
+		return fmt.Errorf("error scrolling: %w", err)
+	}
+
+	return nil
+}
+
+func ScrollDown(page Page, action models.TaskAction) error {
+	return scrollBy(page, action, 0, 1)
+}
+
+func ScrollUp(page Page, action models.TaskAction) error {
+	return scrollBy(page, action, 0, -1)
+}
+
+func ScrollLeft(page Page, action models.TaskAction) error {
+	return scrollBy(page, action, -1, 0)
+}
+
+func ScrollRight(page Page, action models.TaskAction) error {
+	return scrollBy(page, action, 1, 0)
+}
+
+func ScrollTo(page Page, action models.TaskAction) error {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return err
+	}
+
+	if err = element.ScrollIntoView(); err != nil {
+		return fmt.Errorf("error scrolling element into view: %w", err)
+	}
+
+	return nil
+}
+
+// ScrollToBottom scrolls the page, or the selected container, to its bottom
+// until its height stops growing or the maximum number of scrolls is reached,
+// waiting for the lazy loaded content on every scroll.
+func ScrollToBottom(page Page, action models.TaskAction) error {
+	maxScrolls := defaultMaxScrolls
+	if action.Value != "" {
+		parsedScrolls, err := strconv.ParseInt(action.Value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("error parsing max scrolls: %w", err)
+		}
+		maxScrolls = int(parsedScrolls)
+	}
+
+	scroll := page.ScrollToBottom
+	if action.Selector != "" {
+		element, err := FindElement(page, action)
+		if err != nil {
+			return err
+		}
+
+		scroll = element.ScrollToBottom
+	} else if action.WaitFor != nil {
+		if err := page.Wait(*action.WaitFor); err != nil {
+			return err
+		}
+	}
+
+	previousHeight := -1
+	for i := 0; i < maxScrolls; i++ {
+		var height int
+		err := page.WaitRequestIdle(func() error {
+			var err error
+			if height, err = scroll(); err != nil {
+				return fmt.Errorf("error scrolling to bottom: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if height == previousHeight {
+			break
+		}
+		previousHeight = height
+	}
+
+	return nil
+}
+
+// Capture takes the screenshot of the element as media. Actions without a
+// wait condition wait for the page to be stable first.
+func Capture(page Page, action models.TaskAction) (*task.RawMedia, error) {
+	element, err := FindElement(page, action)
+	if err != nil {
+		return nil, err
+	}
+
+	// Actions with an explicit wait condition already waited on FindElement.
+	if action.WaitFor == nil {
+		if err = page.WaitStable(); err != nil {
+			return nil, fmt.Errorf("error waiting element to load: %w", err)
+		}
+	}
+
+	return CaptureElement(page, element)
+}
+
+// CaptureElement returns the screenshot of the element as media, along with
+// the screenshot of the page and the box of the element in it.
+func CaptureElement(page Page, element Element) (*task.RawMedia, error) {
+	media, err := element.Screenshot()
+	if err != nil {
+		return nil, fmt.Errorf("error capturing element: %w", err)
+	}
+
+	screenshot, err := page.Screenshot()
+	if err != nil {
+		return nil, fmt.Errorf("error capturing page: %w", err)
+	}
+
+	box, err := element.Box()
+	if err != nil {
+		return nil, fmt.Errorf("error getting element box: %w", err)
+	}
+
+	pageUrl, err := page.Url()
+	if err != nil {
+		return nil, err
+	}
+
+	return &task.RawMedia{
+		Ext:        "png",
+		Media:      media,
+		Screenshot: screenshot,
+		Height:     box.Height,
+		Width:      box.Width,
+		X:          box.X,
+		Y:          box.Y,
+		Url:        pageUrl,
+	}, nil
+}
+
+// EvaluateScript runs the action value as the body of an async function, with
+// the selected element bound as `this` when there is a selector, and returns
+// its JSON result.
+func EvaluateScript(page Page, action models.TaskAction) (interface{}, error) {
+	if action.Selector == "" {
+		result, err := page.Evaluate(action.Value)
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating script: %w", err)
+		}
+
+		return result, nil
+	}
+
+	element, err := FindElement(page, action)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := element.Evaluate(action.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating script on element: %w", err)
+	}
+
+	return result, nil
+}
+
+// WaitForNavigation waits for the url to contain the value, when there is
+// one, and for the page to load and then meet the wait condition, the
+// network being idle by default.
+func WaitForNavigation(page Page, action models.TaskAction) error {
+	condition := models.WaitCondition{Strategy: models.WaitNetworkIdle}
+	if action.WaitFor != nil {
+		condition = *action.WaitFor
+	}
+
+	return page.WaitAfter(condition, func() error {
+		return page.WaitUrl(strings.TrimSpace(action.Value))
+	})
+}
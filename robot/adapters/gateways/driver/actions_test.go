@@ -0,0 +1,199 @@
+package driver
+
+import (
+	"automator-go/robot/entities/models"
+	"automator-go/robot/entities/validation"
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// MockPage only provides the primitives the tested actions use, the others
+// panic on the nil embedded Page.
+type MockPage struct {
+	Page
+	Heights     []int
+	Scrolls     int
+	IdleWaits   int
+	PressedKeys []string
+	Found       *MockElement
+	Events      []string
+}
+
+func (m *MockPage) Context() context.Context {
+	return context.Background()
+}
+
+func (m *MockPage) WithContext(context.Context) Page {
+	return m
+}
+
+func (m *MockPage) Element([]validation.Selector) (Element, error) {
+	return m.Found, nil
+}
+
+func (m *MockPage) ScrollToBottom() (int, error) {
+	height := m.Heights[min(m.Scrolls, len(m.Heights)-1)]
+	m.Scrolls++
+
+	return height, nil
+}
+
+// WaitRequestIdle counts the waits that listen to the requests of a scroll.
+func (m *MockPage) WaitRequestIdle(trigger func() error) error {
+	scrolls := m.Scrolls
+	if err := trigger(); err != nil {
+		return err
+	}
+	if m.Scrolls > scrolls {
+		m.IdleWaits++
+	}
+
+	return nil
+}
+
+func (m *MockPage) WaitAfter(condition models.WaitCondition, trigger func() error) error {
+	m.Events = append(m.Events, "listen "+condition.Strategy.String())
+	if err := trigger(); err != nil {
+		return err
+	}
+	m.Events = append(m.Events, "wait "+condition.Strategy.String())
+
+	return nil
+}
+
+func (m *MockPage) WaitUrl(fragment string) error {
+	m.Events = append(m.Events, "url "+fragment)
+
+	return nil
+}
+
+func (m *MockPage) PressKeys(keys []string) error {
+	m.PressedKeys = keys
+
+	return nil
+}
+
+type MockElement struct {
+	Element
+	Focused bool
+}
+
+func (m *MockElement) Focus() error {
+	m.Focused = true
+
+	return nil
+}
+
+func TestScrollToBottom(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		heights     []int
+		wantScrolls int
+	}{
+		{
+			name:        "Stops when the height stops growing",
+			heights:     []int{1000, 2000, 3000, 3000},
+			wantScrolls: 4,
+		},
+		{
+			name:        "Stops at the maximum scrolls",
+			value:       "2",
+			heights:     []int{1000, 2000, 3000},
+			wantScrolls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := &MockPage{Heights: tt.heights}
+			err := ScrollToBottom(page, models.TaskAction{Type: models.ScrollToBottom, Value: tt.value})
+			if err != nil {
+				t.Fatalf("ScrollToBottom() error = %v", err)
+			}
+			if page.Scrolls != tt.wantScrolls {
+				t.Errorf("ScrollToBottom() scrolled %d times, want %d", page.Scrolls, tt.wantScrolls)
+			}
+			if page.IdleWaits != page.Scrolls {
+				t.Errorf("ScrollToBottom() waited the requests of %d scrolls, want %d", page.IdleWaits, page.Scrolls)
+			}
+		})
+	}
+}
+
+func TestPressKey(t *testing.T) {
+	element := &MockElement{}
+	page := &MockPage{Found: element}
+
+	err := PressKey(page, models.TaskAction{Type: models.PressKey, Selector: "#search", Value: "Control + a"})
+	if err != nil {
+		t.Fatalf("PressKey() error = %v", err)
+	}
+	if !element.Focused {
+		t.Error("PressKey() did not focus the selected element")
+	}
+	if want := []string{"Control", "a"}; !slices.Equal(page.PressedKeys, want) {
+		t.Errorf("PressKey() pressed %v, want %v", page.PressedKeys, want)
+	}
+}
+
+func TestWaitForNavigation(t *testing.T) {
+	page := &MockPage{}
+
+	err := WaitForNavigation(page, models.TaskAction{Type: models.WaitForNavigation, Value: "Tony_Bennett"})
+	if err != nil {
+		t.Fatalf("WaitForNavigation() error = %v", err)
+	}
+	idle := models.WaitNetworkIdle
+	want := []string{"listen " + idle.String(), "url Tony_Bennett", "wait " + idle.String()}
+	if !slices.Equal(page.Events, want) {
+		t.Errorf("WaitForNavigation() events = %v, want %v", page.Events, want)
+	}
+}
+
+func TestWaitSeconds(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		action  models.TaskAction
+		timeout time.Duration
+		wantErr bool
+	}{
+		{
+			name:    "Invalid seconds",
+			ctx:     context.Background(),
+			action:  models.TaskAction{Type: models.WaitSeconds, Value: "0"},
+			wantErr: true,
+		},
+		{
+			name:    "Wait longer than the timeout",
+			ctx:     context.Background(),
+			action:  models.TaskAction{Type: models.WaitSeconds, Value: "60"},
+			timeout: 15 * time.Second,
+			wantErr: true,
+		},
+		{
+			name:    "Cancelled wait",
+			ctx:     cancelled,
+			action:  models.TaskAction{Type: models.WaitSeconds, Value: "60"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			if err := WaitSeconds(tt.ctx, tt.action, tt.timeout); (err != nil) != tt.wantErr {
+				t.Errorf("WaitSeconds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("WaitSeconds() took %s to fail", elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package driver
+
+import (
+	"automator-go/robot/entities/models"
+	"automator-go/robot/entities/validation"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotSupported is returned by the primitives a driver can't provide, like
+// screenshots on drivers that don't render the pages.
+var ErrNotSupported = errors.New("not supported by the driver")
+
+const (
+	// scrollStep is the amount of pixels scrolled by each step of the scroll actions.
+	scrollStep = 100
+	// defaultMaxScrolls limits ScrollToBottom on infinite feeds when no maximum is given.
+	defaultMaxScrolls = 10
+)
+
+// Page is a document opened by a driver. The actions written on these
+// primitives run on every driver.
+type Page interface {
+	// Context of the page, the primitives stop when it is done.
+	Context() context.Context
+	// WithContext returns the page bound to the context, on the same
+	// document.
+	WithContext(ctx context.Context) Page
+	// Open navigates to the url and waits for the document to load.
+	Open(url string) error
+	// Url of the current document, after redirects.
+	Url() (string, error)
+	// Element finds the element selected by the chain, each selector searched
+	// inside the element found by the previous one.
+	Element(selectors []validation.Selector) (Element, error)
+	// Screenshot of the visible part of the page as png.
+	Screenshot() ([]byte, error)
+	// Wait blocks until the condition is met on the page.
+	Wait(condition models.WaitCondition) error
+	// WaitAfter runs the trigger and waits for the condition. The waits on
+	// page events, like the network idle one, listen from before the trigger
+	// so they don't miss the events it causes.
+	WaitAfter(condition models.WaitCondition, trigger func() error) error
+	// WaitStable waits for the page to stop changing.
+	WaitStable() error
+	// WaitNavigation runs the trigger and waits for the navigation it starts.
+	WaitNavigation(trigger func() error) error
+	// WaitUrl waits for the url to contain the fragment, when there is one,
+	// and for the document to load.
+	WaitUrl(fragment string) error
+	// WaitRequestIdle runs the trigger and waits for the requests of the page
+	// to finish, streams aside. It listens from before the trigger, so the
+	// requests the trigger fires are waited too.
+	WaitRequestIdle(trigger func() error) error
+	// Scroll dispatches steps mouse wheel events moving x and y pixels in all.
+	Scroll(x, y float64, steps int) error
+	// ScrollToBottom scrolls the document to its bottom and returns its
+	// scroll height.
+	ScrollToBottom() (int, error)
+	// PressKeys presses the last key of the combination while holding the
+	// others.
+	PressKeys(keys []string) error
+	// Evaluate runs the script as the body of an async function and returns
+	// its JSON result.
+	Evaluate(script string) (interface{}, error)
+}
+
+type Element interface {
+	Click() error
+	DoubleClick() error
+	RightClick() error
+	Hover() error
+	Focus() error
+	Input(text string) error
+	// Clear selects the text of the input and deletes it.
+	Clear() error
+	InputTime(value time.Time) error
+	// Select selects the options by their text.
+	Select(options []string) error
+	// SetFiles sets the files of a file input.
+	SetFiles(paths []string) error
+	ScrollIntoView() error
+	// ScrollToBottom scrolls the element to its bottom and returns its scroll
+	// height.
+	ScrollToBottom() (int, error)
+	// DragTo drags the element and drops it on the target.
+	DragTo(target Element) error
+	// Wait blocks until the condition is met on the element.
+	Wait(condition models.WaitCondition) error
+	// Evaluate runs the script as the body of an async function, with the
+	// element bound as this, and returns its JSON result.
+	Evaluate(script string) (interface{}, error)
+	// Text is the text content of the element and its descendants.
+	Text() (string, error)
+	// Attribute returns false when the element doesn't have it.
+	Attribute(name string) (string, bool, error)
+	// Screenshot of the element as png.
+	Screenshot() ([]byte, error)
+	Box() (Box, error)
+	// Resource returns the content of the resource the element shows, like
+	// the image of an img, and the url it was loaded from.
+	Resource() ([]byte, string, error)
+	// ResourceFile streams the resource the element shows to a temporary
+	// file, for resources too large to keep in memory, and returns the file
+	// with the extension of the resource.
+	ResourceFile() (string, string, error)
+}
+
+type Box struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+func FindElement(page Page, action models.TaskAction) (Element, error) {
+	selectors, err := validation.ParseSelectorChain(action.Target())
+	if err != nil {
+		return nil, err
+	}
+
+	element, err := page.Element(selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	if action.WaitFor != nil {
+		if err = element.Wait(*action.WaitFor); err != nil {
+			return nil, err
+		}
+	}
+
+	return element, nil
+}
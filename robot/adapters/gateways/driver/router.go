@@ -0,0 +1,33 @@
+package driver
+
+import (
+	"automator-go/robot/entities/models"
+	"automator-go/robot/usecases/task"
+	"fmt"
+)
+
+// Router runs every task with the automator of its driver.
+type Router struct {
+	automators map[string]task.AutomatorTaskAdapter
+}
+
+func NewRouter(automators map[string]task.AutomatorTaskAdapter) *Router {
+	return &Router{
+		automators: automators,
+	}
+}
+
+func (r *Router) Run(taskToRun *models.Task, strategies []*models.Strategy) (*task.RunResult, error) {
+	automator, ok := r.automators[taskToRun.DriverName()]
+	if !ok {
+		return nil, fmt.Errorf("driver %s is not available", taskToRun.DriverName())
+	}
+
+	return automator.Run(taskToRun, strategies)
+}
+
+func (r *Router) Release(taskToRun *models.Task, result *task.RunResult) {
+	if automator, ok := r.automators[taskToRun.DriverName()]; ok {
+		automator.Release(taskToRun, result)
+	}
+}
@@ -0,0 +1,340 @@
+package driver
+
+import (
+	"automator-go/robot/entities/models"
+	"automator-go/robot/usecases/task"
+	"automator-go/utils"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tracerName names the spans of the actions.
+const tracerName = "automator-go/robot/adapters/gateways/driver"
+
+// ActionRun is what an action handler runs with. Page is bound to the action
+// timeout and Variables keeps the values extracted by the previous actions.
+type ActionRun struct {
+	Page      Page
+	Task      *models.Task
+	Action    models.TaskAction
+	Variables map[string]interface{}
+	// Timeout is the one of the action, or the one of the page when the action
+	// has none. Zero when the action is not bounded.
+	Timeout time.Duration
+}
+
+// ActionHandler runs an action, returning the media it captured, if any.
+type ActionHandler func(run *ActionRun) (*task.RawMedia, error)
+
+// builtinHandlers run the built-in actions on the primitives of the page, the
+// drivers replace the ones they run on their own.
+var builtinHandlers = map[models.Action]ActionHandler{
+	models.Navigate:          WithoutMedia(func(run *ActionRun) error { return Navigate(run.Page, run.Action) }),
+	models.Click:             WithoutMedia(func(run *ActionRun) error { return Click(run.Page, run.Action) }),
+	models.ScrollDown:        WithoutMedia(func(run *ActionRun) error { return ScrollDown(run.Page, run.Action) }),
+	models.Capture:           func(run *ActionRun) (*task.RawMedia, error) { return Capture(run.Page, run.Action) },
+	models.WaitSeconds:       WithoutMedia(func(run *ActionRun) error { return WaitSeconds(run.Page.Context(), run.Action, run.Timeout) }),
+	models.WriteInput:        WithoutMedia(func(run *ActionRun) error { return WriteInput(run.Page, run.Action) }),
+	models.SelectOptions:     WithoutMedia(func(run *ActionRun) error { return SelectOptions(run.Page, run.Action) }),
+	models.WriteTime:         WithoutMedia(func(run *ActionRun) error { return WriteTime(run.Page, run.Action) }),
+	models.ClearInput:        WithoutMedia(func(run *ActionRun) error { return ClearInput(run.Page, run.Action) }),
+	models.DownloadResource:  func(run *ActionRun) (*task.RawMedia, error) { return DownloadResource(run.Page, run.Action) },
+	models.WaitForElement:    WithoutMedia(func(run *ActionRun) error { return WaitForElement(run.Page, run.Action) }),
+	models.WaitForNavigation: WithoutMedia(func(run *ActionRun) error { return WaitForNavigation(run.Page, run.Action) }),
+	models.PressKey:          WithoutMedia(func(run *ActionRun) error { return PressKey(run.Page, run.Action) }),
+	models.Hover:             WithoutMedia(func(run *ActionRun) error { return Hover(run.Page, run.Action) }),
+	models.DoubleClick:       WithoutMedia(func(run *ActionRun) error { return DoubleClick(run.Page, run.Action) }),
+	models.RightClick:        WithoutMedia(func(run *ActionRun) error { return RightClick(run.Page, run.Action) }),
+	models.DragAndDrop:       WithoutMedia(func(run *ActionRun) error { return DragAndDrop(run.Page, run.Action) }),
+	models.UploadFile:        WithoutMedia(func(run *ActionRun) error { return UploadFile(run.Page, run.Action) }),
+	models.ScrollTo:          WithoutMedia(func(run *ActionRun) error { return ScrollTo(run.Page, run.Action) }),
+	models.ScrollUp:          WithoutMedia(func(run *ActionRun) error { return ScrollUp(run.Page, run.Action) }),
+	models.ScrollToBottom:    WithoutMedia(func(run *ActionRun) error { return ScrollToBottom(run.Page, run.Action) }),
+	models.ScrollLeft:        WithoutMedia(func(run *ActionRun) error { return ScrollLeft(run.Page, run.Action) }),
+	models.ScrollRight:       WithoutMedia(func(run *ActionRun) error { return ScrollRight(run.Page, run.Action) }),
+	models.EvaluateScript:    WithoutMedia(runEvaluateScript),
+	models.ExtractText:       WithoutMedia(runExtractText),
+}
+
+var (
+	actionHandlersMu sync.RWMutex
+	// actionHandlers are the ones set with RegisterActionHandler.
+	actionHandlers = map[models.Action]ActionHandler{}
+)
+
+// RegisterActionHandler sets the handler of an action registered with
+// models.RegisterAction, for every driver. Built-in handlers can be replaced
+// too.
+func RegisterActionHandler(action models.Action, handler ActionHandler) error {
+	if _, ok := action.Spec(); !ok {
+		return fmt.Errorf("action %s is not registered", action.String())
+	}
+	if handler == nil {
+		return fmt.Errorf("handler of action %s is required", action.String())
+	}
+
+	actionHandlersMu.Lock()
+	defer actionHandlersMu.Unlock()
+
+	actionHandlers[action] = handler
+
+	return nil
+}
+
+func WithoutMedia(run func(run *ActionRun) error) ActionHandler {
+	return func(actionRun *ActionRun) (*task.RawMedia, error) {
+		return nil, run(actionRun)
+	}
+}
+
+func runEvaluateScript(run *ActionRun) error {
+	value, err := EvaluateScript(run.Page, run.Action)
+	if err != nil {
+		return err
+	}
+	run.Variables[run.Action.Id] = value
+
+	return nil
+}
+
+func runExtractText(run *ActionRun) error {
+	text, err := ExtractText(run.Page, run.Action)
+	if err != nil {
+		return err
+	}
+	run.Variables[run.Action.Id] = text
+
+	return nil
+}
+
+// ActionTimeout parses the timeout of the action, defaultTimeout when it has
+// none.
+func ActionTimeout(action models.TaskAction, defaultTimeout time.Duration) (time.Duration, error) {
+	if strings.TrimSpace(action.Timeout) == "" {
+		return defaultTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(action.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing action timeout: %w", err)
+	}
+
+	return timeout, nil
+}
+
+// Runner runs the actions of a task and its strategies on the page of a
+// driver, each action in its own span and bound to its timeout.
+type Runner struct {
+	handlers    map[models.Action]ActionHandler
+	pageTimeout time.Duration
+	onFailure   func(action models.TaskAction, err error) error
+	logger      *otelzap.LoggerWithCtx
+}
+
+// NewRunner receives the handlers of the actions the driver runs on its own,
+// the built-in handlers run the others. The actions without a timeout are
+// bounded by pageTimeout, unless it is zero. onFailure, when not nil, wraps
+// the errors of the failed actions, e.g. with a snapshot of the page.
+func NewRunner(
+	handlers map[models.Action]ActionHandler,
+	pageTimeout time.Duration,
+	onFailure func(action models.TaskAction, err error) error,
+	logger *otelzap.LoggerWithCtx,
+) *Runner {
+	return &Runner{
+		handlers:    handlers,
+		pageTimeout: pageTimeout,
+		onFailure:   onFailure,
+		logger:      logger,
+	}
+}
+
+// Run opens the task url and runs the task actions into the result, between
+// the pre and post actions of the strategies. The pre actions run on the
+// strategy url, or on the task one when it has none.
+func (r *Runner) Run(
+	page Page,
+	open func(url string) error,
+	taskToRun *models.Task,
+	strategies []*models.Strategy,
+	result *task.RunResult,
+) error {
+	var err error
+
+	// Post actions run even when the task fails, in reverse order, for the
+	// strategies whose pre actions were run.
+	started := make([]*models.Strategy, 0, len(strategies))
+	for _, strategy := range strategies {
+		err = r.runPreActions(page, open, taskToRun, strategy)
+		if err != nil {
+			err = fmt.Errorf("error running strategy %s: %w", strategy.Id, err)
+			break
+		}
+		started = append(started, strategy)
+	}
+
+	if err == nil {
+		err = open(taskToRun.Url)
+	}
+	if err == nil {
+		err = r.RunActions(page, taskToRun, taskToRun.Actions, result)
+	}
+
+	for i := len(started) - 1; i >= 0; i-- {
+		strategy := started[i]
+		r.logger.Debug("Running strategy post actions", zap.String("strategy", strategy.Id))
+		// Medias and variables of the strategies are not part of the task result.
+		strategyResult := &task.RunResult{Variables: make(map[string]interface{})}
+		postErr := r.RunActions(page, taskToRun, strategy.PostActions, strategyResult)
+		if postErr != nil {
+			err = errors.Join(err, fmt.Errorf("error running strategy %s post actions: %w", strategy.Id, postErr))
+		}
+	}
+
+	return err
+}
+
+func (r *Runner) runPreActions(
+	page Page,
+	open func(url string) error,
+	taskToRun *models.Task,
+	strategy *models.Strategy,
+) error {
+	if len(strategy.PreActions) == 0 {
+		return nil
+	}
+
+	url := strategy.Url
+	if url == "" {
+		url = taskToRun.Url
+	}
+
+	r.logger.Debug("Running strategy pre actions", zap.String("strategy", strategy.Id), zap.String("url", url))
+	if err := open(url); err != nil {
+		return err
+	}
+
+	strategyResult := &task.RunResult{Variables: make(map[string]interface{})}
+
+	return r.RunActions(page, taskToRun, strategy.PreActions, strategyResult)
+}
+
+// RunActions runs the actions in order into the result, stopping at the
+// first one that fails.
+func (r *Runner) RunActions(
+	page Page,
+	taskToRun *models.Task,
+	actions []models.TaskAction,
+	result *task.RunResult,
+) error {
+	for _, action := range actions {
+		rawMedia, err := r.runAction(page, taskToRun, action, result.Variables)
+		if err != nil {
+			if r.onFailure != nil {
+				return r.onFailure(action, err)
+			}
+
+			return err
+		}
+
+		if rawMedia != nil {
+			rawMedia.ActionId = action.Id
+			// Medias keep the variables extracted until they were captured.
+			if len(result.Variables) > 0 {
+				rawMedia.Attributes = make(map[string]interface{}, len(result.Variables))
+				for name, value := range result.Variables {
+					rawMedia.Attributes[name] = value
+				}
+			}
+			result.Medias = append(result.Medias, *rawMedia)
+		}
+	}
+
+	return nil
+}
+
+// handler looks up the handler of the action: the registered one, the one of
+// the driver or the built-in one, in that order.
+func (r *Runner) handler(action models.Action) (ActionHandler, bool) {
+	actionHandlersMu.RLock()
+	handler, ok := actionHandlers[action]
+	actionHandlersMu.RUnlock()
+	if ok {
+		return handler, true
+	}
+
+	if handler, ok = r.handlers[action]; ok {
+		return handler, true
+	}
+
+	handler, ok = builtinHandlers[action]
+
+	return handler, ok
+}
+
+// runAction runs the handler of the action inside its own span, logging when
+// it starts and how long it took.
+func (r *Runner) runAction(
+	page Page,
+	taskToRun *models.Task,
+	action models.TaskAction,
+	variables map[string]interface{},
+) (*task.RawMedia, error) {
+	actionType := action.Type.String()
+	handler, ok := r.handler(action.Type)
+	if !ok {
+		return nil, fmt.Errorf("unknown action type: %s", actionType)
+	}
+
+	timeout, err := ActionTimeout(action, r.pageTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := page.Context()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	ctx, span := utils.StartSpan(ctx, tracerName, "action "+actionType)
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("task.id", taskToRun.Id),
+		attribute.String("action.id", action.Id),
+		attribute.String("action.type", actionType),
+	)
+
+	// Values are not logged, inputs may hold the secrets of the strategies.
+	fields := []zap.Field{zap.String("action", action.Id), zap.String("type", actionType)}
+	r.logger.Debug("Running action", append(fields, zap.Duration("timeout", timeout))...)
+	start := time.Now()
+
+	rawMedia, err := handler(&ActionRun{
+		Page:      page.WithContext(ctx),
+		Task:      taskToRun,
+		Action:    action,
+		Variables: variables,
+		Timeout:   timeout,
+	})
+	fields = append(fields, zap.Duration("duration", time.Since(start)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		r.logger.Debug("Action failed", append(fields, zap.Error(err))...)
+
+		return nil, fmt.Errorf("error running action %s (%s): %w", action.Id, actionType, err)
+	}
+	r.logger.Debug("Ran action", fields...)
+
+	return rawMedia, nil
+}
@@ -0,0 +1,142 @@
+package driver
+
+import (
+	"automator-go/robot/entities/models"
+	"automator-go/robot/usecases/task"
+	"context"
+	"errors"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestRunner_Run(t *testing.T) {
+	logger := otelzap.New(zap.NewNop()).Ctx(context.Background())
+
+	var ran []string
+	record := WithoutMedia(func(run *ActionRun) error {
+		ran = append(ran, run.Action.Id)
+		return nil
+	})
+	handlers := map[models.Action]ActionHandler{
+		models.Click: record,
+		models.Hover: func(run *ActionRun) (*task.RawMedia, error) {
+			ran = append(ran, run.Action.Id)
+			return nil, errors.New("element not found")
+		},
+		models.Capture: func(run *ActionRun) (*task.RawMedia, error) {
+			ran = append(ran, run.Action.Id)
+			return &task.RawMedia{Ext: "png"}, nil
+		},
+	}
+	strategies := []*models.Strategy{
+		{
+			Id:          "login",
+			PreActions:  []models.TaskAction{{Id: "login", Type: models.Click, Value: "#login"}},
+			PostActions: []models.TaskAction{{Id: "logout", Type: models.Click, Value: "#logout"}},
+		},
+		{
+			Id:          "consent",
+			PreActions:  []models.TaskAction{{Id: "accept", Type: models.Click, Value: "#accept"}},
+			PostActions: []models.TaskAction{{Id: "revoke", Type: models.Click, Value: "#revoke"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		actions    []models.TaskAction
+		wantErr    bool
+		wantRan    []string
+		wantOpened []string
+		wantMedias int
+	}{
+		{
+			name:       "Task between the strategies",
+			actions:    []models.TaskAction{{Id: "capture", Type: models.Capture, Value: "#firstHeading"}},
+			wantRan:    []string{"login", "accept", "capture", "revoke", "logout"},
+			wantOpened: []string{"https://en.wikipedia.org", "https://en.wikipedia.org", "https://en.wikipedia.org"},
+			wantMedias: 1,
+		},
+		{
+			name: "Post actions run when the task fails",
+			actions: []models.TaskAction{
+				{Id: "hover", Type: models.Hover, Value: "#missing"},
+				{Id: "capture", Type: models.Capture, Value: "#firstHeading"},
+			},
+			wantErr:    true,
+			wantRan:    []string{"login", "accept", "hover", "revoke", "logout"},
+			wantOpened: []string{"https://en.wikipedia.org", "https://en.wikipedia.org", "https://en.wikipedia.org"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran = nil
+			var opened []string
+			open := func(url string) error {
+				opened = append(opened, url)
+				return nil
+			}
+
+			runner := NewRunner(handlers, 0, nil, &logger)
+			result := &task.RunResult{Variables: make(map[string]interface{})}
+			taskToRun := &models.Task{Id: "1", Url: "https://en.wikipedia.org", Actions: tt.actions}
+			err := runner.Run(&MockPage{}, open, taskToRun, strategies, result)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Runner.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(ran, tt.wantRan) {
+				t.Errorf("Runner.Run() ran %v, want %v", ran, tt.wantRan)
+			}
+			if !slices.Equal(opened, tt.wantOpened) {
+				t.Errorf("Runner.Run() opened %v, want %v", opened, tt.wantOpened)
+			}
+			if len(result.Medias) != tt.wantMedias {
+				t.Errorf("Runner.Run() medias = %d, want %d", len(result.Medias), tt.wantMedias)
+			}
+		})
+	}
+}
+
+func TestRegisterActionHandler(t *testing.T) {
+	actionHandlersMu.RLock()
+	registered := maps.Clone(actionHandlers)
+	actionHandlersMu.RUnlock()
+	t.Cleanup(func() {
+		actionHandlersMu.Lock()
+		defer actionHandlersMu.Unlock()
+
+		actionHandlers = registered
+	})
+
+	logger := otelzap.New(zap.NewNop()).Ctx(context.Background())
+	var ran string
+	driverHandlers := map[models.Action]ActionHandler{
+		models.Click: WithoutMedia(func(*ActionRun) error {
+			ran = "driver"
+			return nil
+		}),
+	}
+	err := RegisterActionHandler(models.Click, WithoutMedia(func(*ActionRun) error {
+		ran = "registered"
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("RegisterActionHandler() error = %v", err)
+	}
+
+	runner := NewRunner(driverHandlers, 0, nil, &logger)
+	actions := []models.TaskAction{{Id: "1", Type: models.Click, Value: "#link"}}
+	if err = runner.RunActions(&MockPage{}, &models.Task{Id: "1"}, actions, &task.RunResult{}); err != nil {
+		t.Fatalf("Runner.RunActions() error = %v", err)
+	}
+	if ran != "registered" {
+		t.Errorf("Runner.RunActions() ran the %s handler, want the registered one", ran)
+	}
+
+	if err = RegisterActionHandler(models.Click, nil); err == nil {
+		t.Error("RegisterActionHandler() without handler error = nil")
+	}
+}
@@ -0,0 +1,399 @@
+package hasher
+
+import (
+	"automator-go/robot/entities/models"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/corona10/goimagehash"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	"image/draw"
+	"image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxResourceHashes limits the hashes of a resource, the frames of long
+	// animations are sampled evenly and videos stop at this many keyframes.
+	maxResourceHashes = 50
+	// keyframeWidth is the width the keyframes are scaled to before hashing,
+	// the hash is computed on a much smaller image anyway.
+	keyframeWidth = 320
+	// waveletSize is the side of the gray image the wavelet hash decomposes,
+	// three levels reduce it to the 64 coefficients of the hash.
+	waveletSize = 64
+	// ffmpegTimeout limits how long ffmpeg takes to extract the keyframes of a
+	// video, it is killed past it so a stuck one doesn't block the consumer.
+	ffmpegTimeout = 2 * time.Minute
+)
+
+type ImageHashHandler struct {
+	logger *otelzap.LoggerWithCtx
+}
+
+func NewImageHashHandler(logger *otelzap.LoggerWithCtx) *ImageHashHandler {
+	return &ImageHashHandler{
+		logger: logger,
+	}
+}
+
+// Hash fingerprints a png, jpeg, gif or webp image with every algorithm of
+// models.HashAlgorithms. The perceptual hashes only hash the first frame of
+// animated images, the sha256 hashes the bytes as they are.
+func (h *ImageHashHandler) Hash(media []byte) ([]models.MediaHash, error) {
+	h.logger.Debug("Hashing image")
+	decoded, _, err := image.Decode(bytes.NewReader(media))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+
+	hashes := make([]models.MediaHash, 0, len(models.HashAlgorithms))
+	for _, algorithm := range models.HashAlgorithms {
+		var value string
+		switch algorithm {
+		case models.PerceptionHash:
+			value, err = imageHashValue(goimagehash.PerceptionHash(decoded))
+		case models.DifferenceHash:
+			value, err = imageHashValue(goimagehash.DifferenceHash(decoded))
+		case models.AverageHash:
+			value, err = imageHashValue(goimagehash.AverageHash(decoded))
+		case models.WaveletHash:
+			value = hashValue(waveletHash(decoded))
+		case models.Sha256Hash:
+			sum := sha256.Sum256(media)
+			value = hex.EncodeToString(sum[:])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error computing %s: %w", algorithm, err)
+		}
+		hashes = append(hashes, models.MediaHash{Algorithm: algorithm, Value: value})
+	}
+	h.logger.Debug("Finished hashing image")
+
+	return hashes, nil
+}
+
+func imageHashValue(hash *goimagehash.ImageHash, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+
+	return hashValue(hash.GetHash()), nil
+}
+
+// resourceHash is the perception hash of a resource image, valued like the
+// ones of the captures so both are searched together.
+func resourceHash(hash *goimagehash.ImageHash) models.MediaHash {
+	return models.MediaHash{Algorithm: models.PerceptionHash, Value: hashValue(hash.GetHash())}
+}
+
+// hashValue formats the 64 bits hashes like goimagehash, without the kind.
+func hashValue(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+// waveletHash is the Haar wavelet hash of the image: its gray levels, scaled
+// to waveletSize, are decomposed until the approximation band is 8x8, and
+// each bit tells whether a coefficient is over their median.
+func waveletHash(img image.Image) uint64 {
+	gray := image.NewGray(image.Rect(0, 0, waveletSize, waveletSize))
+	xdraw.BiLinear.Scale(gray, gray.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+
+	coefficients := make([]float64, len(gray.Pix))
+	for i, level := range gray.Pix {
+		coefficients[i] = float64(level)
+	}
+	// The approximation band of each level is the normalized sum of the 2x2
+	// blocks of the previous one, the detail bands are not needed.
+	for size := waveletSize; size > 8; size /= 2 {
+		half := size / 2
+		approximation := make([]float64, half*half)
+		for y := 0; y < half; y++ {
+			for x := 0; x < half; x++ {
+				top := 2*y*size + 2*x
+				bottom := top + size
+				approximation[y*half+x] = (coefficients[top] + coefficients[top+1] + coefficients[bottom] + coefficients[bottom+1]) / 2
+			}
+		}
+		coefficients = approximation
+	}
+
+	sorted := append([]float64(nil), coefficients...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, coefficient := range coefficients {
+		if coefficient > median {
+			hash |= 1 << uint(len(coefficients)-1-i)
+		}
+	}
+
+	return hash
+}
+
+// HashResource hashes a downloaded resource: still images have one hash,
+// animated gifs and webps one per frame and videos one per keyframe, in play
+// order. Resources that are not images or videos have none.
+func (h *ImageHashHandler) HashResource(resource io.Reader) ([]models.MediaHash, error) {
+	file, isFile := resource.(*os.File)
+	reader := bufio.NewReader(resource)
+	head, err := reader.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error reading resource: %w", err)
+	}
+
+	switch resourceKind(head) {
+	case "image/gif":
+		return h.hashGif(reader)
+	case "image/webp":
+		if isAnimatedWebp(head) {
+			return h.hashWebpAnimation(reader)
+		}
+		fallthrough
+	case "image":
+		decoded, _, err := image.Decode(reader)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding resource image: %w", err)
+		}
+		hash, _ := goimagehash.PerceptionHash(decoded)
+
+		return []models.MediaHash{resourceHash(hash)}, nil
+	case "video":
+		if isFile {
+			return h.hashVideo(file.Name())
+		}
+
+		// Videos are read by ffmpeg from a file, some containers have their
+		// index at the end.
+		spooled, err := os.CreateTemp("", "resource_*")
+		if err != nil {
+			return nil, fmt.Errorf("error creating video file: %w", err)
+		}
+		defer os.Remove(spooled.Name())
+		_, err = io.Copy(spooled, reader)
+		if closeErr := spooled.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error writing video file: %w", err)
+		}
+
+		return h.hashVideo(spooled.Name())
+	}
+
+	return nil, nil
+}
+
+// resourceKind sniffs the resource type, telling gifs and webps apart from
+// the other images.
+func resourceKind(head []byte) string {
+	contentType := http.DetectContentType(head)
+	switch {
+	case contentType == "image/gif" || contentType == "image/webp":
+		return contentType
+	case contentType == "image/png" || contentType == "image/jpeg":
+		return "image"
+	case strings.HasPrefix(contentType, "video/"):
+		return "video"
+	case len(head) >= 12 && string(head[4:8]) == "ftyp":
+		// Mp4 brands unknown to the standard sniffer, like quicktime.
+		return "video"
+	case len(head) > 188 && head[0] == 0x47 && head[188] == 0x47:
+		// Transport streams, like the joined HLS segments.
+		return "video"
+	}
+
+	return ""
+}
+
+// isAnimatedWebp checks the animation flag of the extended webp header.
+func isAnimatedWebp(head []byte) bool {
+	return len(head) >= 21 && string(head[12:16]) == "VP8X" && head[20]&0x02 != 0
+}
+
+// hashGif hashes the frames as they are shown: gif frames only hold what
+// changed, they are drawn over the previous ones following their disposal.
+func (h *ImageHashHandler) hashGif(reader io.Reader) ([]models.MediaHash, error) {
+	animation, err := gif.DecodeAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding resource gif: %w", err)
+	}
+	if len(animation.Image) == 0 {
+		return nil, errors.New("resource gif has no frames")
+	}
+
+	bounds := image.Rect(0, 0, animation.Config.Width, animation.Config.Height)
+	canvas := image.NewRGBA(bounds)
+	sampled := sampleFrames(len(animation.Image), maxResourceHashes)
+	hashes := make([]models.MediaHash, 0, len(sampled))
+	for i, frame := range animation.Image {
+		var previous *image.RGBA
+		disposal := byte(0)
+		if i < len(animation.Disposal) {
+			disposal = animation.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(bounds)
+			draw.Draw(previous, bounds, canvas, image.Point{}, draw.Src)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		if sampled[i] {
+			hash, _ := goimagehash.PerceptionHash(canvas)
+			hashes = append(hashes, resourceHash(hash))
+		}
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+	h.logger.Debug("Hashed gif frames", zap.Int("frames", len(animation.Image)), zap.Int("hashes", len(hashes)))
+
+	return hashes, nil
+}
+
+// hashWebpAnimation hashes the frames as they are shown, drawn over the
+// canvas or replacing their area, like the gif ones.
+func (h *ImageHashHandler) hashWebpAnimation(reader io.Reader) ([]models.MediaHash, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading resource webp: %w", err)
+	}
+	animation, err := decodeWebpAnimation(data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding resource webp: %w", err)
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, animation.width, animation.height))
+	sampled := sampleFrames(len(animation.frames), maxResourceHashes)
+	hashes := make([]models.MediaHash, 0, len(sampled))
+	for i, frame := range animation.frames {
+		op := draw.Src
+		if frame.blend {
+			op = draw.Over
+		}
+		draw.Draw(canvas, frame.bounds, frame.image, frame.image.Bounds().Min, op)
+		if sampled[i] {
+			hash, _ := goimagehash.PerceptionHash(canvas)
+			hashes = append(hashes, resourceHash(hash))
+		}
+
+		if frame.dispose {
+			draw.Draw(canvas, frame.bounds, image.Transparent, image.Point{}, draw.Src)
+		}
+	}
+	h.logger.Debug("Hashed webp frames", zap.Int("frames", len(animation.frames)), zap.Int("hashes", len(hashes)))
+
+	return hashes, nil
+}
+
+// sampleFrames picks up to limit frames spread evenly over the animation,
+// always keeping the first one.
+func sampleFrames(frames int, limit int) []bool {
+	sampled := make([]bool, frames)
+	if frames <= limit {
+		for i := range sampled {
+			sampled[i] = true
+		}
+		return sampled
+	}
+
+	for i := 0; i < limit; i++ {
+		sampled[i*frames/limit] = true
+	}
+
+	return sampled
+}
+
+func ffmpegPath() string {
+	path := os.Getenv("FFMPEG_PATH")
+	if strings.TrimSpace(path) == "" {
+		return "ffmpeg"
+	}
+
+	return path
+}
+
+// hashVideo decodes the keyframes of the video with ffmpeg, which writes them
+// as a stream of png images.
+func (h *ImageHashHandler) hashVideo(path string) ([]models.MediaHash, error) {
+	h.logger.Debug("Hashing video keyframes")
+	ctx, cancel := context.WithTimeout(context.Background(), ffmpegTimeout)
+	defer cancel()
+	command := exec.CommandContext(
+		ctx,
+		ffmpegPath(),
+		"-hide_banner", "-loglevel", "error", "-nostdin",
+		"-skip_frame", "nokey",
+		"-i", path,
+		"-an", "-vsync", "vfr",
+		"-vf", "scale="+strconv.Itoa(keyframeWidth)+":-2",
+		"-frames:v", strconv.Itoa(maxResourceHashes),
+		"-f", "image2pipe", "-c:v", "png", "-",
+	)
+	// Stops waiting for the output of the processes ffmpeg may have started
+	// once it is killed.
+	command.WaitDelay = time.Second
+	var stderr bytes.Buffer
+	command.Stderr = &stderr
+	stdout, err := command.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("error reading ffmpeg output: %w", err)
+	}
+	if err = command.Start(); err != nil {
+		return nil, fmt.Errorf("error starting ffmpeg, it is required to hash videos: %w", err)
+	}
+
+	// The png decoder stops at the end of each image, the buffered reader
+	// keeps what it read past it for the next one.
+	frames := bufio.NewReader(stdout)
+	hashes := make([]models.MediaHash, 0)
+	var decodeErr error
+	for {
+		if _, err := frames.Peek(1); err != nil {
+			break
+		}
+		keyframe, err := png.Decode(frames)
+		if err != nil {
+			decodeErr = fmt.Errorf("error decoding keyframe %d: %w", len(hashes), err)
+			_, _ = io.Copy(io.Discard, frames)
+			break
+		}
+		hash, _ := goimagehash.PerceptionHash(keyframe)
+		hashes = append(hashes, resourceHash(hash))
+	}
+
+	if err = command.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("error extracting keyframes, ffmpeg took longer than %s: %w", ffmpegTimeout, ctx.Err())
+		}
+		return nil, fmt.Errorf("error extracting keyframes: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	h.logger.Debug("Hashed video keyframes", zap.Int("keyframes", len(hashes)))
+
+	return hashes, nil
+}
@@ -0,0 +1,230 @@
+package hasher
+
+import (
+	"automator-go/robot/entities/models"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"golang.org/x/image/webp"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"os"
+	"testing"
+)
+
+func TestImageHashHandler(t *testing.T) {
+	logger := otelzap.New(zap.NewExample(), otelzap.WithMinLevel(zap.DebugLevel)).Ctx(context.Background())
+	imageFile, err := os.Open("../../../testing_resources/test.png")
+	if err != nil {
+		t.Fatalf("error opening image: %v", err)
+	}
+	defer func(imageFile *os.File) {
+		err := imageFile.Close()
+		if err != nil {
+			t.Fatalf("error closing image: %v", err)
+		}
+	}(imageFile)
+
+	// Image to bytes
+	imageBuff := bytes.Buffer{}
+	_, err = imageBuff.ReadFrom(imageFile)
+	if err != nil {
+		t.Fatalf("error reading image: %v", err)
+	}
+	base64Image := imageBuff.Bytes()
+
+	tests := []struct {
+		name       string
+		image      []byte
+		wantErr    bool
+		wantPHash  string
+		wantSha256 string
+	}{
+		{
+			name:    "should return error when image is invalid",
+			image:   []byte("invalid"),
+			wantErr: true,
+		},
+		{
+			name:    "should return error when image is nil",
+			image:   nil,
+			wantErr: true,
+		},
+		{
+			name:       "should return hash when image is valid",
+			image:      base64Image,
+			wantErr:    false,
+			wantPHash:  "8f28f6d738680f07",
+			wantSha256: "51c8e665f94b0a0808170c6ae43495f9b44c8940cb32e96aa66ae184e6034c82",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewImageHashHandler(&logger)
+			got, err := handler.Hash(tt.image)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ImageHashHandler.Hash() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && len(got) != len(models.HashAlgorithms) {
+				t.Errorf("ImageHashHandler.Hash() = %v, want a hash per algorithm", got)
+			}
+			if tt.wantPHash != "" && models.FindHash(got, models.PerceptionHash) != tt.wantPHash {
+				t.Errorf("ImageHashHandler.Hash() = %v, want phash %v", got, tt.wantPHash)
+			}
+			if tt.wantSha256 != "" && models.FindHash(got, models.Sha256Hash) != tt.wantSha256 {
+				t.Errorf("ImageHashHandler.Hash() = %v, want sha256 %v", got, tt.wantSha256)
+			}
+		})
+	}
+}
+
+func TestImageHashHandler_HashResource(t *testing.T) {
+	logger := otelzap.New(zap.NewExample(), otelzap.WithMinLevel(zap.DebugLevel)).Ctx(context.Background())
+
+	still := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 32; x++ {
+			still.Set(x, y, color.White)
+		}
+	}
+	var jpegImage bytes.Buffer
+	if err := jpeg.Encode(&jpegImage, still, nil); err != nil {
+		t.Fatalf("error encoding jpeg: %v", err)
+	}
+
+	// The second frame only covers the left half, it is drawn over the first.
+	animation := &gif.GIF{
+		Image: []*image.Paletted{
+			image.NewPaletted(image.Rect(0, 0, 64, 64), palette.Plan9),
+			image.NewPaletted(image.Rect(0, 0, 32, 64), palette.Plan9),
+		},
+		Delay: []int{10, 10},
+	}
+	draw.Draw(animation.Image[1], animation.Image[1].Rect, image.White, image.Point{}, draw.Src)
+	var gifImage bytes.Buffer
+	if err := gif.EncodeAll(&gifImage, animation); err != nil {
+		t.Fatalf("error encoding gif: %v", err)
+	}
+
+	webpImage, _ := base64.StdEncoding.DecodeString("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+	animatedWebpImage := animatedWebp(t, webpImage, 3)
+
+	tests := []struct {
+		name       string
+		resource   []byte
+		wantHashes int
+		wantErr    bool
+	}{
+		{name: "Jpeg", resource: jpegImage.Bytes(), wantHashes: 1},
+		{name: "Webp", resource: webpImage, wantHashes: 1},
+		{name: "Animated gif", resource: gifImage.Bytes(), wantHashes: 2},
+		{name: "Animated webp", resource: animatedWebpImage, wantHashes: 3},
+		{name: "Corrupted animated webp", resource: animatedWebpImage[:len(animatedWebpImage)-4], wantErr: true},
+		{name: "Other resource", resource: []byte("%PDF-1.7"), wantHashes: 0},
+		{name: "Corrupted image", resource: jpegImage.Bytes()[:100], wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewImageHashHandler(&logger)
+			got, err := handler.HashResource(bytes.NewReader(tt.resource))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ImageHashHandler.HashResource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.wantHashes {
+				t.Errorf("ImageHashHandler.HashResource() = %v, want %d hashes", got, tt.wantHashes)
+			}
+		})
+	}
+
+	t.Run("Gif frames are composed", func(t *testing.T) {
+		handler := NewImageHashHandler(&logger)
+		got, err := handler.HashResource(bytes.NewReader(gifImage.Bytes()))
+		if err != nil {
+			t.Fatalf("ImageHashHandler.HashResource() error = %v", err)
+		}
+		hashes, _ := handler.Hash(jpegImage.Bytes())
+		want := models.MediaHash{Algorithm: models.PerceptionHash, Value: models.FindHash(hashes, models.PerceptionHash)}
+		if got[1] != want || got[0] == got[1] {
+			t.Errorf("ImageHashHandler.HashResource() = %v, want the second frame to be %v", got, want)
+		}
+	})
+}
+
+// animatedWebp repeats the image of a still webp as the frames of an
+// animation.
+func animatedWebp(t *testing.T, still []byte, frames int) []byte {
+	config, err := webp.DecodeConfig(bytes.NewReader(still))
+	if err != nil {
+		t.Fatalf("error decoding webp config: %v", err)
+	}
+	chunk := func(fourCC string, data []byte) []byte {
+		raw := binary.LittleEndian.AppendUint32([]byte(fourCC), uint32(len(data)))
+		raw = append(raw, data...)
+		if len(data)%2 == 1 {
+			raw = append(raw, 0)
+		}
+		return raw
+	}
+	size := func(width int, height int) []byte {
+		return []byte{byte(width - 1), byte((width - 1) >> 8), byte((width - 1) >> 16), byte(height - 1), byte((height - 1) >> 8), byte((height - 1) >> 16)}
+	}
+
+	body := []byte("WEBP")
+	body = append(body, chunk("VP8X", append([]byte{0x02, 0, 0, 0}, size(config.Width, config.Height)...))...)
+	body = append(body, chunk("ANIM", make([]byte, 6))...)
+	for i := 0; i < frames; i++ {
+		header := append(append([]byte{0, 0, 0, 0, 0, 0}, size(config.Width, config.Height)...), 100, 0, 0, 0)
+		body = append(body, chunk("ANMF", append(header, still[12:]...))...)
+	}
+
+	return append(binary.LittleEndian.AppendUint32([]byte("RIFF"), uint32(len(body))), body...)
+}
+
+func TestWaveletHash(t *testing.T) {
+	halves := image.NewGray(image.Rect(0, 0, 128, 96))
+	draw.Draw(halves, image.Rect(64, 0, 128, 96), image.White, image.Point{}, draw.Src)
+	stripes := image.NewGray(image.Rect(0, 0, 100, 100))
+	draw.Draw(stripes, image.Rect(0, 0, 100, 50), image.White, image.Point{}, draw.Src)
+
+	tests := []struct {
+		name  string
+		image image.Image
+		want  uint64
+	}{
+		{name: "Uniform", image: image.NewGray(image.Rect(0, 0, 32, 32)), want: 0},
+		{name: "Bright right half", image: halves, want: 0x0f0f0f0f0f0f0f0f},
+		{name: "Bright top half", image: stripes, want: 0xffffffff00000000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := waveletHash(tt.image); got != tt.want {
+				t.Errorf("waveletHash() = %016x, want %016x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleFrames(t *testing.T) {
+	sampled := sampleFrames(200, maxResourceHashes)
+	count := 0
+	for _, frame := range sampled {
+		if frame {
+			count++
+		}
+	}
+	if count != maxResourceHashes || !sampled[0] {
+		t.Errorf("sampleFrames() sampled %d frames, want %d with the first one", count, maxResourceHashes)
+	}
+}
@@ -0,0 +1,150 @@
+package hasher
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"golang.org/x/image/webp"
+	"image"
+)
+
+// webpFrame is a frame of an animated webp, placed at its offset on the
+// canvas.
+type webpFrame struct {
+	bounds image.Rectangle
+	image  image.Image
+	// blend draws the frame over the canvas instead of replacing its area.
+	blend bool
+	// dispose clears the area of the frame once it was shown.
+	dispose bool
+}
+
+// webpAnimation is an animated webp with its frames decoded.
+type webpAnimation struct {
+	width  int
+	height int
+	frames []webpFrame
+}
+
+type webpChunk struct {
+	fourCC string
+	data   []byte
+	// raw is the whole chunk, header and padding included.
+	raw []byte
+}
+
+// webpChunks splits the chunks of a RIFF payload.
+func webpChunks(data []byte) ([]webpChunk, error) {
+	chunks := make([]webpChunk, 0)
+	for len(data) > 0 {
+		if len(data) < 8 {
+			return nil, errors.New("truncated webp chunk header")
+		}
+		size := int(binary.LittleEndian.Uint32(data[4:8]))
+		if size > len(data)-8 {
+			return nil, errors.New("truncated webp chunk")
+		}
+		end := 8 + size + size%2
+		if end > len(data) {
+			end = len(data)
+		}
+		chunks = append(chunks, webpChunk{fourCC: string(data[:4]), data: data[8 : 8+size], raw: data[:end]})
+		data = data[end:]
+	}
+
+	return chunks, nil
+}
+
+func uint24(data []byte) int {
+	return int(data[0]) | int(data[1])<<8 | int(data[2])<<16
+}
+
+func putUint24(data []byte, value int) {
+	data[0], data[1], data[2] = byte(value), byte(value>>8), byte(value>>16)
+}
+
+// decodeWebpAnimation decodes the ANMF frames of an animated webp. The webp
+// package only decodes still images, so every frame is wrapped as one.
+func decodeWebpAnimation(data []byte) (*webpAnimation, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errors.New("invalid webp header")
+	}
+	chunks, err := webpChunks(data[12:])
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 || chunks[0].fourCC != "VP8X" || len(chunks[0].data) < 10 {
+		return nil, errors.New("animated webp without extended header")
+	}
+
+	animation := &webpAnimation{
+		width:  uint24(chunks[0].data[4:7]) + 1,
+		height: uint24(chunks[0].data[7:10]) + 1,
+	}
+	for _, chunk := range chunks[1:] {
+		if chunk.fourCC != "ANMF" {
+			continue
+		}
+		if len(chunk.data) < 16 {
+			return nil, errors.New("truncated webp frame header")
+		}
+
+		header := chunk.data[:16]
+		x, y := uint24(header[0:3])*2, uint24(header[3:6])*2
+		width, height := uint24(header[6:9])+1, uint24(header[9:12])+1
+		frame, err := decodeWebpFrame(chunk.data[16:], width, height)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding webp frame %d: %w", len(animation.frames), err)
+		}
+
+		animation.frames = append(animation.frames, webpFrame{
+			bounds:  image.Rect(x, y, x+width, y+height),
+			image:   frame,
+			blend:   header[15]&0x02 == 0,
+			dispose: header[15]&0x01 != 0,
+		})
+	}
+	if len(animation.frames) == 0 {
+		return nil, errors.New("animated webp has no frames")
+	}
+
+	return animation, nil
+}
+
+// decodeWebpFrame wraps the bitstream of the frame, with its alpha when it
+// has one, as a still webp.
+func decodeWebpFrame(data []byte, width int, height int) (image.Image, error) {
+	chunks, err := webpChunks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	for _, chunk := range chunks {
+		if chunk.fourCC == "ALPH" {
+			// Alpha chunks need the extended header, flagged with alpha.
+			extended := make([]byte, 18)
+			copy(extended, "VP8X")
+			binary.LittleEndian.PutUint32(extended[4:8], 10)
+			extended[8] = 0x10
+			putUint24(extended[12:15], width-1)
+			putUint24(extended[15:18], height-1)
+			body.Write(extended)
+			break
+		}
+	}
+	for _, chunk := range chunks {
+		if chunk.fourCC == "ALPH" || chunk.fourCC == "VP8 " || chunk.fourCC == "VP8L" {
+			body.Write(chunk.raw)
+		}
+	}
+
+	still := make([]byte, 12, 12+body.Len())
+	copy(still, "RIFF")
+	binary.LittleEndian.PutUint32(still[4:8], uint32(4+body.Len()))
+	copy(still[8:], "WEBP")
+	still = append(still, body.Bytes()...)
+
+	return webp.Decode(bytes.NewReader(still))
+}
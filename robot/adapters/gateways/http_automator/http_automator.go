@@ -0,0 +1,87 @@
+package http_automator
+
+import (
+	"automator-go/robot/adapters/gateways/driver"
+	models2 "automator-go/robot/entities/models"
+	"automator-go/robot/usecases/task"
+	"context"
+	"fmt"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"strings"
+	"time"
+)
+
+// HttpAutomator runs the tasks of the http driver: it fetches the pages
+// without a browser, so it can only run the static actions.
+type HttpAutomator struct {
+	client *http.Client
+	ctx    context.Context
+	logger *otelzap.LoggerWithCtx
+}
+
+func NewHttpAutomator(client *http.Client, ctx context.Context, logger *otelzap.LoggerWithCtx) *HttpAutomator {
+	return &HttpAutomator{
+		client: client,
+		ctx:    ctx,
+		logger: logger,
+	}
+}
+
+// NewHttpClient creates the client of the http driver, with the timeout of
+// HTTP_DRIVER_TIMEOUT for each request.
+func NewHttpClient() (*http.Client, error) {
+	timeoutEnv := os.Getenv("HTTP_DRIVER_TIMEOUT")
+	if strings.TrimSpace(timeoutEnv) == "" {
+		timeoutEnv = "15s"
+	}
+	timeout, err := time.ParseDuration(timeoutEnv)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing http driver timeout: %w", err)
+	}
+
+	return &http.Client{Timeout: timeout}, nil
+}
+
+// staticHandlers are the actions the http driver runs on its own: it follows
+// the links instead of clicking them.
+var staticHandlers = map[models2.Action]driver.ActionHandler{
+	models2.Navigate: driver.WithoutMedia(func(run *driver.ActionRun) error {
+		return driver.FollowLink(run.Page, run.Action)
+	}),
+}
+
+// Run fetches the task url and runs the actions on its document. The actions
+// without a timeout are bounded by the timeout of the client.
+func (at *HttpAutomator) Run(taskToRun *models2.Task, strategies []*models2.Strategy) (*task.RunResult, error) {
+	// Every task has its own cookies, like the browser contexts of the tasks.
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cookie jar: %w", err)
+	}
+	client := *at.client
+	client.Jar = jar
+	page := newHttpPage(&client, at.ctx)
+
+	open := func(url string) error {
+		at.logger.Debug("Fetching page", zap.String("url", url))
+		return page.Open(url)
+	}
+
+	result := &task.RunResult{
+		Medias:    make([]task.RawMedia, 0),
+		Variables: make(map[string]interface{}),
+	}
+	runner := driver.NewRunner(staticHandlers, client.Timeout, nil, at.logger)
+	if err = runner.Run(page, open, taskToRun, strategies, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Release has nothing to free, the http driver keeps the resources in memory.
+func (at *HttpAutomator) Release(*models2.Task, *task.RunResult) {}
@@ -0,0 +1,139 @@
+package http_automator
+
+import (
+	"automator-go/robot/adapters/gateways/driver"
+	"automator-go/robot/entities/models"
+	"context"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// pageUrl is an action out of the tree, registered once as the programs
+// embedding the robot do from an init function.
+var pageUrl = func() models.Action {
+	action, err := models.RegisterAction(models.ActionSpec{Name: "PageUrl", Target: models.PageTarget, Static: true})
+	if err != nil {
+		panic(err)
+	}
+
+	err = driver.RegisterActionHandler(action, driver.WithoutMedia(func(run *driver.ActionRun) error {
+		url, err := run.Page.Url()
+		if err != nil {
+			return err
+		}
+		run.Variables[run.Action.Id] = url
+
+		return nil
+	}))
+	if err != nil {
+		panic(err)
+	}
+
+	return action
+}()
+
+func TestHttpAutomator_Run(t *testing.T) {
+	logger := otelzap.New(zap.NewExample(), otelzap.WithMinLevel(zap.DebugLevel)).Ctx(context.Background())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body>
+			<h1 id="firstHeading">Tony
+				Bennett</h1>
+			<a href="/wiki/Frank_Sinatra">Frank Sinatra</a>
+			<a href="/slow">Slow</a>
+			<img class="portrait" src="/static/test.png">
+		</body></html>`))
+	})
+	mux.HandleFunc("/wiki/Frank_Sinatra", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><h1 id="firstHeading">Frank Sinatra</h1></body></html>`))
+	})
+	mux.HandleFunc("/static/test.png", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "../../../testing_resources/test.png")
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		actions   []models.TaskAction
+		wantErr   bool
+		wantMedia int
+		wantVars  map[string]interface{}
+	}{
+		{
+			name: "Extract text and download resource",
+			actions: []models.TaskAction{
+				{Id: "heading", Type: models.ExtractText, Value: "#firstHeading"},
+				{Id: "portrait", Type: models.DownloadResource, Value: "img.portrait"},
+			},
+			wantMedia: 1,
+			wantVars:  map[string]interface{}{"heading": "Tony Bennett"},
+		},
+		{
+			name: "Follow link",
+			actions: []models.TaskAction{
+				{Id: "link", Type: models.Navigate, Value: "text=Frank Sinatra"},
+				{Id: "heading", Type: models.ExtractText, Value: "//h1"},
+			},
+			wantVars: map[string]interface{}{"heading": "Frank Sinatra"},
+		},
+		{
+			name:     "Registered static action",
+			actions:  []models.TaskAction{{Id: "url", Type: pageUrl}},
+			wantVars: map[string]interface{}{"url": server.URL},
+		},
+		{
+			name:    "Action timeout",
+			actions: []models.TaskAction{{Id: "1", Type: models.Navigate, Value: "text=Slow", Timeout: "10ms"}},
+			wantErr: true,
+		},
+		{
+			name:    "Element not found",
+			actions: []models.TaskAction{{Id: "1", Type: models.WaitForElement, Value: "#missing"}},
+			wantErr: true,
+		},
+		{
+			name:    "Browser action",
+			actions: []models.TaskAction{{Id: "1", Type: models.Click, Value: "a"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := NewHttpAutomator(server.Client(), context.Background(), &logger)
+			result, err := at.Run(&models.Task{Id: "1", Url: server.URL, Driver: models.HttpDriver, Actions: tt.actions}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HttpAutomator.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(result.Medias) != tt.wantMedia {
+				t.Errorf("HttpAutomator.Run() medias = %d, want %d", len(result.Medias), tt.wantMedia)
+			}
+			for _, media := range result.Medias {
+				if media.ActionId == "" {
+					t.Errorf("HttpAutomator.Run() media without the action that captured it")
+				}
+			}
+			for name, want := range tt.wantVars {
+				if result.Variables[name] != want {
+					t.Errorf("HttpAutomator.Run() variable %s = %v, want %v", name, result.Variables[name], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package http_automator
+
+import (
+	"automator-go/robot/adapters/gateways/driver"
+	"automator-go/robot/entities/models"
+	"automator-go/robot/entities/validation"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"golang.org/x/net/html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxBodySize limits the documents and resources read by the http driver.
+const maxBodySize = 64 << 20
+
+// resourceAttributes are where elements reference the resource they show, in
+// the order they are looked up.
+var resourceAttributes = []string{"src", "data-src", "poster", "href"}
+
+// httpPage is the static HTML of a document, fetched without running its
+// scripts.
+type httpPage struct {
+	client      *http.Client
+	ctx         context.Context
+	maxBodySize int
+	document    *httpDocument
+}
+
+// httpDocument is the document opened, shared by the page bound to every
+// context.
+type httpDocument struct {
+	url string
+	doc *html.Node
+}
+
+func newHttpPage(client *http.Client, ctx context.Context) *httpPage {
+	return &httpPage{
+		client:      client,
+		ctx:         ctx,
+		maxBodySize: maxBodySize,
+		document:    &httpDocument{},
+	}
+}
+
+func (p *httpPage) Context() context.Context {
+	return p.ctx
+}
+
+func (p *httpPage) WithContext(ctx context.Context) driver.Page {
+	return &httpPage{
+		client:      p.client,
+		ctx:         ctx,
+		maxBodySize: p.maxBodySize,
+		document:    p.document,
+	}
+}
+
+func (p *httpPage) Open(url string) error {
+	body, finalUrl, err := p.fetch(url)
+	if err != nil {
+		return err
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error parsing document: %w", err)
+	}
+
+	p.document.url = finalUrl
+	p.document.doc = doc
+
+	return nil
+}
+
+func (p *httpPage) Url() (string, error) {
+	if p.document.doc == nil {
+		return "", errors.New("no document opened")
+	}
+
+	return p.document.url, nil
+}
+
+func (p *httpPage) Element(selectors []validation.Selector) (driver.Element, error) {
+	if p.document.doc == nil {
+		return nil, errors.New("no document opened")
+	}
+
+	scope := p.document.doc
+	for i, selector := range selectors {
+		if i > 0 && (scope.Data == "iframe" || scope.Data == "frame") {
+			return nil, fmt.Errorf("stepping into frames is %w", driver.ErrNotSupported)
+		}
+
+		node, err := queryNode(scope, selector)
+		if err != nil {
+			return nil, err
+		}
+		if node == nil {
+			return nil, fmt.Errorf("element not found by selector %q", selector.Value)
+		}
+		scope = node
+	}
+
+	return &httpElement{page: p, node: scope}, nil
+}
+
+func (p *httpPage) Screenshot() ([]byte, error) {
+	return nil, driver.ErrNotSupported
+}
+
+func (p *httpPage) Wait(models.WaitCondition) error {
+	return driver.ErrNotSupported
+}
+
+func (p *httpPage) WaitAfter(models.WaitCondition, func() error) error {
+	return driver.ErrNotSupported
+}
+
+func (p *httpPage) WaitStable() error {
+	return driver.ErrNotSupported
+}
+
+func (p *httpPage) WaitNavigation(func() error) error {
+	return driver.ErrNotSupported
+}
+
+func (p *httpPage) WaitUrl(string) error {
+	return driver.ErrNotSupported
+}
+
+func (p *httpPage) WaitRequestIdle(func() error) error {
+	return driver.ErrNotSupported
+}
+
+func (p *httpPage) Scroll(float64, float64, int) error {
+	return driver.ErrNotSupported
+}
+
+func (p *httpPage) ScrollToBottom() (int, error) {
+	return 0, driver.ErrNotSupported
+}
+
+func (p *httpPage) PressKeys([]string) error {
+	return driver.ErrNotSupported
+}
+
+func (p *httpPage) Evaluate(string) (interface{}, error) {
+	return nil, driver.ErrNotSupported
+}
+
+// fetch gets the url, returning the body and the url it was read from after
+// following the redirects.
+func (p *httpPage) fetch(url string) ([]byte, string, error) {
+	request, err := http.NewRequestWithContext(p.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating request: %w", err)
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting %s: %w", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return nil, "", fmt.Errorf("error getting %s: status %d", url, response.StatusCode)
+	}
+
+	// A byte over the limit tells the bodies that don't fit from the ones
+	// that fill it.
+	body, err := io.ReadAll(io.LimitReader(response.Body, int64(p.maxBodySize)+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading %s: %w", url, err)
+	}
+	if len(body) > p.maxBodySize {
+		return nil, "", fmt.Errorf("error reading %s: body is larger than %d bytes", url, p.maxBodySize)
+	}
+
+	return body, response.Request.URL.String(), nil
+}
+
+func queryNode(scope *html.Node, selector validation.Selector) (*html.Node, error) {
+	query, isXpath := selector.Query()
+	if isXpath {
+		node, err := htmlquery.Query(scope, query)
+		if err != nil {
+			return nil, fmt.Errorf("error getting element by selector: %w", err)
+		}
+
+		return node, nil
+	}
+
+	compiled, err := cascadia.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("error getting element by selector: %w", err)
+	}
+
+	return compiled.MatchFirst(scope), nil
+}
+
+type httpElement struct {
+	page *httpPage
+	node *html.Node
+}
+
+func (e *httpElement) Click() error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) DoubleClick() error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) RightClick() error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) Hover() error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) Focus() error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) Input(string) error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) Clear() error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) InputTime(time.Time) error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) Select([]string) error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) SetFiles([]string) error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) ScrollIntoView() error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) ScrollToBottom() (int, error) {
+	return 0, driver.ErrNotSupported
+}
+
+func (e *httpElement) DragTo(driver.Element) error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) Wait(models.WaitCondition) error {
+	return driver.ErrNotSupported
+}
+
+func (e *httpElement) Evaluate(string) (interface{}, error) {
+	return nil, driver.ErrNotSupported
+}
+
+func (e *httpElement) Text() (string, error) {
+	return htmlquery.InnerText(e.node), nil
+}
+
+func (e *httpElement) Attribute(name string) (string, bool, error) {
+	for _, attribute := range e.node.Attr {
+		if attribute.Key == name {
+			return attribute.Val, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+func (e *httpElement) Screenshot() ([]byte, error) {
+	return nil, driver.ErrNotSupported
+}
+
+func (e *httpElement) Box() (driver.Box, error) {
+	return driver.Box{}, driver.ErrNotSupported
+}
+
+func (e *httpElement) Resource() ([]byte, string, error) {
+	for _, name := range resourceAttributes {
+		reference, ok, _ := e.Attribute(name)
+		if !ok || strings.TrimSpace(reference) == "" {
+			continue
+		}
+
+		resourceUrl, err := driver.ResolveUrl(e.page, reference)
+		if err != nil {
+			return nil, "", err
+		}
+
+		resource, _, err := e.page.fetch(resourceUrl)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return resource, resourceUrl, nil
+	}
+
+	return nil, "", errors.New("element does not reference a resource")
+}
+
+func (e *httpElement) ResourceFile() (string, string, error) {
+	return "", "", driver.ErrNotSupported
+}
@@ -0,0 +1,40 @@
+package http_automator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_httpPage_fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("a", 10)))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		maxBodySize int
+		wantErr     bool
+	}{
+		{name: "Body within the limit", maxBodySize: 10, wantErr: false},
+		{name: "Body over the limit", maxBodySize: 9, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := newHttpPage(server.Client(), context.Background())
+			page.maxBodySize = tt.maxBodySize
+
+			body, _, err := page.fetch(server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("httpPage.fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(body) != 10 {
+				t.Errorf("httpPage.fetch() read %d bytes, want 10", len(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package publisher
+
+import (
+	"automator-go/robot/entities/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+)
+
+// ContentChangedEvent is the type of the messages telling a content changed.
+const ContentChangedEvent = "ContentChanged"
+
+type contentChangedMessage struct {
+	Type   string                `json:"type"`
+	Change *models.ContentChange `json:"change"`
+}
+
+// RabbitChangePublisher publishes the changes on the exchange with the
+// routing key the subscribers bind their queues to.
+type RabbitChangePublisher struct {
+	ch         *amqp.Channel
+	exchange   string
+	routingKey string
+	logger     *otelzap.LoggerWithCtx
+}
+
+func NewRabbitChangePublisher(
+	ch *amqp.Channel,
+	exchange string,
+	routingKey string,
+	logger *otelzap.LoggerWithCtx,
+) *RabbitChangePublisher {
+	return &RabbitChangePublisher{
+		ch:         ch,
+		exchange:   exchange,
+		routingKey: routingKey,
+		logger:     logger,
+	}
+}
+
+func (p *RabbitChangePublisher) PublishContentChanged(change *models.ContentChange, ctx context.Context) error {
+	body, err := json.Marshal(contentChangedMessage{Type: ContentChangedEvent, Change: change})
+	if err != nil {
+		return fmt.Errorf("error marshalling change: %w", err)
+	}
+
+	err = p.ch.PublishWithContext(ctx, p.exchange, p.routingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    change.Id,
+		Type:         ContentChangedEvent,
+		Timestamp:    change.DetectedAt,
+		Body:         body,
+	})
+	if err != nil {
+		return fmt.Errorf("error publishing change: %w", err)
+	}
+	p.logger.Debug("Published content change", zap.String("task_id", change.TaskId), zap.String("action_id", change.ActionId))
+
+	return nil
+}
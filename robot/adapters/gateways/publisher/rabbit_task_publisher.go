@@ -0,0 +1,68 @@
+package publisher
+
+import (
+	"automator-go/robot/entities/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"time"
+)
+
+// RabbitTaskPublisher publishes the tasks of the schedules on the exchange
+// with the routing key the robots consume the tasks from.
+type RabbitTaskPublisher struct {
+	ch         *amqp.Channel
+	exchange   string
+	routingKey string
+	logger     *otelzap.LoggerWithCtx
+}
+
+func NewRabbitTaskPublisher(
+	ch *amqp.Channel,
+	exchange string,
+	routingKey string,
+	logger *otelzap.LoggerWithCtx,
+) *RabbitTaskPublisher {
+	return &RabbitTaskPublisher{
+		ch:         ch,
+		exchange:   exchange,
+		routingKey: routingKey,
+		logger:     logger,
+	}
+}
+
+// PublishTask publishes the task as the consumers expect it, the headers tell
+// the schedule and the run time it was published for.
+func (p *RabbitTaskPublisher) PublishTask(schedule *models.Schedule, runAt time.Time, ctx context.Context) error {
+	body, err := json.Marshal(schedule.Task)
+	if err != nil {
+		return fmt.Errorf("error marshalling task: %w", err)
+	}
+
+	err = p.ch.PublishWithContext(ctx, p.exchange, p.routingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    fmt.Sprintf("%s:%d", schedule.Id, runAt.Unix()),
+		Timestamp:    time.Now(),
+		Headers: amqp.Table{
+			"schedule_id":  schedule.Id,
+			"scheduled_at": runAt.Format(time.RFC3339),
+		},
+		Body: body,
+	})
+	if err != nil {
+		return fmt.Errorf("error publishing task: %w", err)
+	}
+
+	p.logger.Info(
+		"Published scheduled task",
+		zap.String("schedule_id", schedule.Id),
+		zap.String("task_id", schedule.Task.Id),
+		zap.Time("scheduled_at", runAt),
+	)
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+)
+
+// envPrefix keeps the secrets apart from the rest of the environment, the
+// strategies can't read the configuration of the robot.
+const envPrefix = "SECRET_"
+
+// EnvSecrets reads the secrets from the environment, the secret NAME from
+// the variable SECRET_NAME.
+type EnvSecrets struct{}
+
+func NewEnvSecrets() *EnvSecrets {
+	return &EnvSecrets{}
+}
+
+func (s *EnvSecrets) GetSecret(name string) (string, error) {
+	value, ok := os.LookupEnv(envPrefix + name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s%s is not set", envPrefix, name)
+	}
+
+	return value, nil
+}
@@ -2,12 +2,20 @@ package storage
 
 import (
 	"automator-go/robot/usecases/task"
+	"errors"
 	"fmt"
 	"github.com/nlepage/go-cuid2"
 	"github.com/uptrace/opentelemetry-go-extra/otelzap"
+	"go.uber.org/zap"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
+// mediaDir is where the files are saved, relative to the working directory.
+const mediaDir = "media"
+
 type FileStorage struct {
 	MediaExtension string
 	logger         *otelzap.LoggerWithCtx
@@ -24,34 +32,37 @@ func NewFileStorage(extension string, logger *otelzap.LoggerWithCtx) *FileStorag
 	}
 }
 
-func (fsm *FileStorage) SaveMedia(hashWithoutKind string, media *task.RawMedia) (task.StorageMedia, error) {
+// SaveMedia writes the media file once per content, it is named by its hash
+// alone. The screenshot and resource of each capture get their own files.
+func (fsm *FileStorage) SaveMedia(contentHash string, media *task.RawMedia) (task.StorageMedia, error) {
 	fsm.logger.Debug("Saving media files")
 	filenameId, err := cuid2.CreateId()
 	if err != nil {
 		return task.StorageMedia{}, fmt.Errorf("error generating files id: %w", err)
 	}
 
-	mediaFilename := hashWithoutKind + "_" + filenameId + "." + fsm.MediaExtension
-	screenshotFilename := hashWithoutKind + "_" + filenameId + "." + fsm.MediaExtension
+	mediaFilename := contentHash + "." + fsm.MediaExtension
+	screenshotFilename := contentHash + "_" + filenameId + "." + fsm.MediaExtension
 	mediaPath := "./media/media_" + mediaFilename
 	screenshotPath := "./media/screenshot_" + screenshotFilename
 
-	fileMedia, err := os.Create(mediaPath)
-	if err != nil {
+	fileMedia, err := os.OpenFile(mediaPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		fsm.logger.Debug("Media already in file storage", zap.String("hash", contentHash))
+	} else if err != nil {
 		return task.StorageMedia{}, err
-	}
-	defer func(file *os.File) {
-		err := file.Close()
+	} else {
+		_, err = fileMedia.Write(media.Media)
+		if closeErr := fileMedia.Close(); err == nil {
+			err = closeErr
+		}
 		if err != nil {
-			println(err.Error())
+			// A partial file would be taken for the media by the next capture.
+			_ = os.Remove(mediaPath)
+			return task.StorageMedia{}, err
 		}
-	}(fileMedia)
-
-	_, err = fileMedia.Write(media.Media)
-	if err != nil {
-		return task.StorageMedia{}, err
+		fsm.logger.Debug("Saved media to file storage")
 	}
-	fsm.logger.Debug("Saved media to file storage")
 
 	fileScreenshot, err := os.Create(screenshotPath)
 	if err != nil {
@@ -71,8 +82,14 @@ func (fsm *FileStorage) SaveMedia(hashWithoutKind string, media *task.RawMedia)
 	fsm.logger.Debug("Saved screenshot to file storage")
 
 	var resourcePath string
-	if media.Resource != nil && len(media.Resource) > 0 {
-		resourceFilename := hashWithoutKind + "_" + filenameId + "." + media.Ext
+	if media.ResourceFile != "" {
+		resourcePath = "./media/resource_" + contentHash + "_" + filenameId + "." + media.Ext
+		if err = moveFile(media.ResourceFile, resourcePath); err != nil {
+			return task.StorageMedia{}, fmt.Errorf("error saving resource: %w", err)
+		}
+		fsm.logger.Debug("Saved resource to file storage")
+	} else if media.Resource != nil && len(media.Resource) > 0 {
+		resourceFilename := contentHash + "_" + filenameId + "." + media.Ext
 		resourcePath = "./media/resource_" + resourceFilename
 
 		fileResource, err := os.Create(resourcePath)
@@ -100,3 +117,70 @@ func (fsm *FileStorage) SaveMedia(hashWithoutKind string, media *task.RawMedia)
 		Resource:   resourcePath,
 	}, nil
 }
+
+func (fsm *FileStorage) SaveHar(runId string, har []byte) (string, error) {
+	harPath := "./media/har_" + runId + ".har"
+	if err := os.WriteFile(harPath, har, 0o644); err != nil {
+		return "", fmt.Errorf("error saving har: %w", err)
+	}
+	fsm.logger.Debug("Saved har to file storage")
+
+	return harPath, nil
+}
+
+// SaveDiagnostic prefixes the files with diagnostic, they are not captures.
+func (fsm *FileStorage) SaveDiagnostic(runId string, name string, data []byte) (string, error) {
+	diagnosticPath := "./media/diagnostic_" + runId + "_" + filepath.Base(name)
+	if err := os.WriteFile(diagnosticPath, data, 0o644); err != nil {
+		return "", fmt.Errorf("error saving diagnostic %s: %w", name, err)
+	}
+	fsm.logger.Debug("Saved diagnostic to file storage", zap.String("name", name))
+
+	return diagnosticPath, nil
+}
+
+// Open only reads the files inside the media directory, urls come from the
+// database and are not trusted to point there.
+func (fsm *FileStorage) Open(url string) (io.ReadCloser, error) {
+	path := filepath.Clean(url)
+	if !strings.HasPrefix(path, mediaDir+string(filepath.Separator)) {
+		return nil, fmt.Errorf("file %q is not in the media storage", url)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+
+	return file, nil
+}
+
+// moveFile renames the file, copying it when the temporary directory is on
+// another device than the media storage.
+func moveFile(source string, destination string) error {
+	if err := os.Rename(source, destination); err == nil {
+		return nil
+	}
+
+	sourceFile, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close()
+
+	destinationFile, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(destinationFile, sourceFile)
+	if closeErr := destinationFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(destination)
+		return err
+	}
+
+	return os.Remove(source)
+}
@@ -0,0 +1,69 @@
+package bun
+
+import (
+	bunModels "automator-go/robot/adapters/repositories/bun/models"
+	"automator-go/robot/entities/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type Baseline struct {
+	db *bun.DB
+}
+
+func NewBunBaseline(db *bun.DB) *Baseline {
+	return &Baseline{db: db}
+}
+
+func (b *Baseline) GetBaseline(taskId string, actionId string, ctx context.Context) (*models.Baseline, error) {
+	baseline := &bunModels.Baseline{}
+	err := b.db.NewSelect().Model(baseline).
+		Where("task_id = ?", taskId).
+		Where("action_id = ?", actionId).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting baseline: %w", err)
+	}
+
+	return MapBunBaselineToModel(baseline), nil
+}
+
+// SaveBaseline keeps the baseline of the action when the new one is not
+// approved, the first capture of concurrent runs wins.
+func (b *Baseline) SaveBaseline(baseline *models.Baseline, ctx context.Context) error {
+	bunBaseline := bunModels.Baseline{
+		TaskId:    baseline.TaskId,
+		ActionId:  baseline.ActionId,
+		MediaId:   baseline.MediaId,
+		PHash:     baseline.PHash,
+		Sha256:    baseline.Sha256,
+		MediaUrl:  baseline.MediaUrl,
+		UpdatedAt: time.Now(),
+	}
+	query := b.db.NewInsert().Model(&bunBaseline)
+	if baseline.ApprovedAt != nil {
+		bunBaseline.ApprovedAt = bun.NullTime{Time: *baseline.ApprovedAt}
+		query.On("CONFLICT (task_id, action_id) DO UPDATE").
+			Set("media_id = EXCLUDED.media_id").
+			Set("phash = EXCLUDED.phash").
+			Set("sha256 = EXCLUDED.sha256").
+			Set("media_url = EXCLUDED.media_url").
+			Set("approved_at = EXCLUDED.approved_at").
+			Set("updated_at = EXCLUDED.updated_at")
+	} else {
+		query.On("CONFLICT (task_id, action_id) DO NOTHING")
+	}
+
+	if _, err := query.Exec(ctx); err != nil {
+		return fmt.Errorf("error saving baseline: %w", err)
+	}
+
+	return nil
+}
@@ -6,8 +6,8 @@ import (
 	"automator-go/robot/usecases/task"
 	"context"
 	"fmt"
-	"github.com/nlepage/go-cuid2"
 	"github.com/uptrace/bun"
+	"strings"
 	"time"
 )
 
@@ -19,11 +19,8 @@ func NewBunCaptureMedia(db *bun.DB) *CaptureMedia {
 	return &CaptureMedia{db: db}
 }
 
-func (b *CaptureMedia) Save(input task.NewMediaInput, ctx context.Context) error {
-	mediaId, err := cuid2.CreateId()
-	if err != nil {
-		return fmt.Errorf("error generating media id: %w", err)
-	}
+func (b *CaptureMedia) Save(input task.NewMediaInput, ctx context.Context) (string, error) {
+	mediaId := input.Id
 	media := bunModels.Media{
 		ID:            mediaId,
 		Attributes:    input.Attributes,
@@ -38,19 +35,55 @@ func (b *CaptureMedia) Save(input task.NewMediaInput, ctx context.Context) error
 		ScreenshotUrl: input.ScreenshotUrl,
 		ResourceUrl:   input.ResourceUrl,
 		TaskId:        input.TaskId,
+		ActionId:      input.ActionId,
+		RunId:         input.RunId,
+		VisualDiff:    input.VisualDiff,
+	}
+	hashes := make([]bunModels.MediaHash, 0, len(input.Hashes))
+	for _, hash := range input.Hashes {
+		hashes = append(hashes, bunModels.MediaHash{
+			MediaId:   mediaId,
+			Algorithm: string(hash.Algorithm),
+			Value:     hash.Value,
+		})
+	}
+	resourceHashes := make([]bunModels.MediaResourceHash, 0, len(input.ResourceHashes))
+	for i, hash := range input.ResourceHashes {
+		resourceHashes = append(resourceHashes, bunModels.MediaResourceHash{
+			MediaId:   mediaId,
+			Position:  i,
+			Algorithm: string(hash.Algorithm),
+			Value:     hash.Value,
+		})
 	}
 
-	_, err = b.db.NewInsert().Model(&media).Exec(ctx)
+	err := b.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(&media).Exec(ctx); err != nil {
+			return fmt.Errorf("error inserting media: %w", err)
+		}
+		if len(hashes) > 0 {
+			if _, err := tx.NewInsert().Model(&hashes).Exec(ctx); err != nil {
+				return fmt.Errorf("error inserting media hashes: %w", err)
+			}
+		}
+		if len(resourceHashes) > 0 {
+			if _, err := tx.NewInsert().Model(&resourceHashes).Exec(ctx); err != nil {
+				return fmt.Errorf("error inserting media resource hashes: %w", err)
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("error inserting media: %w", err)
+		return "", err
 	}
 
-	return nil
+	return mediaId, nil
 }
 
 func (b *CaptureMedia) GetMedia(mediaId string, ctx context.Context) (*models.Media, error) {
 	media := &bunModels.Media{}
-	err := b.db.NewSelect().Model(media).Where("id = ?", mediaId).Scan(ctx)
+	err := selectMedias(b.db, media).Where("id = ?", mediaId).Scan(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting media: %w", err)
 	}
@@ -58,9 +91,23 @@ func (b *CaptureMedia) GetMedia(mediaId string, ctx context.Context) (*models.Me
 	return MapBunMediaToModel(media), nil
 }
 
+// GetMediaByHash finds the media by the hash of its capture, by any hash of
+// its resource, like a frame of a gif or a keyframe of a video, or by the
+// sha256 of its bytes.
 func (b *CaptureMedia) GetMediaByHash(hash string, ctx context.Context) (*models.Media, error) {
 	media := &bunModels.Media{}
-	err := b.db.NewSelect().Model(media).Where("p_hash = ?", hash).Scan(ctx)
+	err := selectMedias(b.db, media).
+		Where("phash = ?", hash).
+		WhereOr(
+			"EXISTS (SELECT 1 FROM media_resource_hashes WHERE media_id = media.id AND value = ?)",
+			strings.TrimPrefix(strings.ToLower(hash), "p:"),
+		).
+		WhereOr(
+			"EXISTS (SELECT 1 FROM media_hashes WHERE media_id = media.id AND algorithm = ? AND value = ?)",
+			models.Sha256Hash, strings.ToLower(hash),
+		).
+		Limit(1).
+		Scan(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting media: %w", err)
 	}
@@ -70,7 +117,7 @@ func (b *CaptureMedia) GetMediaByHash(hash string, ctx context.Context) (*models
 
 func (b *CaptureMedia) GetMedias(filter *task.MediaFilter, ctx context.Context) ([]*models.Media, error) {
 	medias := &[]bunModels.Media{}
-	query := b.db.NewSelect().Model(medias)
+	query := selectMedias(b.db, medias)
 
 	if filter.Hash != nil {
 		query.Where("phash = ?", *filter.Hash)
@@ -104,3 +151,67 @@ func (b *CaptureMedia) GetMedias(filter *task.MediaFilter, ctx context.Context)
 
 	return mediasModel, nil
 }
+
+// selectMedias selects the medias with their hashes, the ones of the resource
+// in their order.
+func selectMedias(db *bun.DB, model interface{}) *bun.SelectQuery {
+	return db.NewSelect().Model(model).
+		Relation("Hashes").
+		Relation("ResourceHashes", func(query *bun.SelectQuery) *bun.SelectQuery {
+			return query.Order("resource_hash.position ASC")
+		})
+}
+
+// hashDistance is the hamming distance between the hash of the media and the
+// searched one, both 64 bits hashes in hexadecimal.
+const hashDistance = "bit_count(('x' || media_hash.value)::bit(64) # ?::bit(64))"
+
+// searchedHashes are the hashes of the medias and the ones of their
+// resources, so a frame of a gif or a keyframe of a video finds its media.
+const searchedHashes = `JOIN (
+	SELECT media_id, algorithm, value FROM media_hashes
+	UNION ALL
+	SELECT media_id, algorithm, value FROM media_resource_hashes
+) AS media_hash ON media_hash.media_id = media.id`
+
+// GetSimilarMedias compares the perceptual hashes by their hamming distance,
+// sha256 hashes are only compared for equality and have no distance. Medias
+// matching by several hashes are found once, by their closest one.
+func (b *CaptureMedia) GetSimilarMedias(query task.SimilarMediaQuery, ctx context.Context) ([]task.SimilarMedia, error) {
+	medias := make([]bunModels.Media, 0)
+	selectQuery := selectMedias(b.db, &medias).
+		ColumnExpr("media.*").
+		Join(searchedHashes).
+		Where("media_hash.algorithm = ?", query.Hash.Algorithm).
+		GroupExpr("media.id")
+
+	if query.Hash.Algorithm.Perceptual() {
+		searched := "x" + query.Hash.Value
+		selectQuery.
+			ColumnExpr("min("+hashDistance+") AS distance", searched).
+			Where(hashDistance+" <= ?", searched, query.MaxDistance).
+			OrderExpr("distance ASC")
+	} else {
+		selectQuery.
+			ColumnExpr("0 AS distance").
+			Where("media_hash.value = ?", query.Hash.Value)
+	}
+
+	err := selectQuery.
+		OrderExpr("media.created_at DESC").
+		Limit(query.Limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting similar medias: %w", err)
+	}
+
+	similarMedias := make([]task.SimilarMedia, 0, len(medias))
+	for _, media := range medias {
+		similarMedias = append(similarMedias, task.SimilarMedia{
+			Media:    MapBunMediaToModel(&media),
+			Distance: media.Distance,
+		})
+	}
+
+	return similarMedias, nil
+}
@@ -0,0 +1,127 @@
+package bun
+
+import (
+	bunModels "automator-go/robot/adapters/repositories/bun/models"
+	"automator-go/robot/entities/models"
+	"automator-go/robot/usecases/task"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type ContentChange struct {
+	db *bun.DB
+}
+
+func NewBunContentChange(db *bun.DB) *ContentChange {
+	return &ContentChange{db: db}
+}
+
+func (b *ContentChange) GetLastContent(taskId string, actionId string, ctx context.Context) (*models.ContentSnapshot, error) {
+	snapshot := &bunModels.ContentSnapshot{}
+	err := b.db.NewSelect().Model(snapshot).
+		Where("task_id = ?", taskId).
+		Where("action_id = ?", actionId).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting last content: %w", err)
+	}
+
+	return &models.ContentSnapshot{
+		TaskId:   snapshot.TaskId,
+		ActionId: snapshot.ActionId,
+		RunId:    snapshot.RunId,
+		Kind:     models.ContentKind(snapshot.Kind),
+		Value:    snapshot.Value,
+		MediaId:  snapshot.MediaId,
+	}, nil
+}
+
+func (b *ContentChange) SaveLastContent(snapshot *models.ContentSnapshot, ctx context.Context) error {
+	bunSnapshot := bunModels.ContentSnapshot{
+		TaskId:    snapshot.TaskId,
+		ActionId:  snapshot.ActionId,
+		RunId:     snapshot.RunId,
+		Kind:      string(snapshot.Kind),
+		Value:     snapshot.Value,
+		MediaId:   snapshot.MediaId,
+		UpdatedAt: time.Now(),
+	}
+
+	_, err := b.db.NewInsert().
+		Model(&bunSnapshot).
+		On("CONFLICT (task_id, action_id) DO UPDATE").
+		Set("run_id = EXCLUDED.run_id").
+		Set("kind = EXCLUDED.kind").
+		Set("value = EXCLUDED.value").
+		Set("media_id = EXCLUDED.media_id").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("error saving last content: %w", err)
+	}
+
+	return nil
+}
+
+func (b *ContentChange) SaveChange(change *models.ContentChange, ctx context.Context) error {
+	bunChange := bunModels.ContentChange{
+		ID:              change.Id,
+		TaskId:          change.TaskId,
+		ActionId:        change.ActionId,
+		RunId:           change.RunId,
+		PreviousRunId:   change.PreviousRunId,
+		Kind:            string(change.Kind),
+		Previous:        change.Previous,
+		Current:         change.Current,
+		MediaId:         change.MediaId,
+		PreviousMediaId: change.PreviousMediaId,
+		Distance:        change.Distance,
+		DetectedAt:      change.DetectedAt,
+	}
+
+	_, err := b.db.NewInsert().Model(&bunChange).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("error saving content change: %w", err)
+	}
+
+	return nil
+}
+
+func (b *ContentChange) GetChanges(filter *task.ContentChangeFilter, ctx context.Context) ([]*models.ContentChange, error) {
+	changes := make([]bunModels.ContentChange, 0)
+	query := b.db.NewSelect().Model(&changes).Order("detected_at DESC")
+
+	if filter.TaskId != nil {
+		query.Where("task_id = ?", *filter.TaskId)
+	}
+
+	if filter.ActionId != nil {
+		query.Where("action_id = ?", *filter.ActionId)
+	}
+
+	if filter.Since != nil {
+		query.Where("detected_at > ?", *filter.Since)
+	}
+
+	if filter.Limit != nil {
+		query.Limit(int(*filter.Limit))
+	}
+
+	if err := query.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("error getting content changes: %w", err)
+	}
+
+	changesModel := make([]*models.ContentChange, 0, len(changes))
+	for _, change := range changes {
+		changesModel = append(changesModel, MapBunContentChangeToModel(&change))
+	}
+
+	return changesModel, nil
+}
@@ -0,0 +1,79 @@
+package bun
+
+import (
+	"context"
+	"fmt"
+	"github.com/uptrace/bun"
+	"hash/fnv"
+	"sync"
+)
+
+// LeaderLock elects a leader among the instances sharing the database with a
+// PostgreSQL session advisory lock. The lock is held by a connection of its
+// own, so the lead is lost when the connection breaks (e.g. the instance
+// died) and another instance can take it.
+type LeaderLock struct {
+	db   *bun.DB
+	key  int64
+	mu   sync.Mutex
+	conn *bun.Conn
+}
+
+// NewBunLeaderLock elects a leader among the instances using the same name.
+func NewBunLeaderLock(db *bun.DB, name string) *LeaderLock {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+
+	return &LeaderLock{db: db, key: int64(h.Sum64())}
+}
+
+func (l *LeaderLock) TryLead(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		// The lock lives as long as the session holding it.
+		if _, err := l.conn.ExecContext(ctx, "SELECT 1"); err == nil {
+			return true, nil
+		}
+		_ = l.conn.Close()
+		l.conn = nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error getting lock connection: %w", err)
+	}
+
+	var locked bool
+	if err = conn.NewRaw("SELECT pg_try_advisory_lock(?)", l.key).Scan(ctx, &locked); err != nil {
+		_ = conn.Close()
+		return false, fmt.Errorf("error taking leader lock: %w", err)
+	}
+	if !locked {
+		return false, conn.Close()
+	}
+
+	l.conn = &conn
+
+	return true, nil
+}
+
+func (l *LeaderLock) Resign(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	defer func() {
+		_ = l.conn.Close()
+		l.conn = nil
+	}()
+
+	if _, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock(?)", l.key); err != nil {
+		return fmt.Errorf("error releasing leader lock: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type Baseline struct {
+	bun.BaseModel `bun:"table:baselines,alias:baseline"`
+
+	TaskId     string       `bun:"task_id,pk"`
+	ActionId   string       `bun:"action_id,pk"`
+	MediaId    string       `bun:"media_id,notnull"`
+	PHash      string       `bun:"phash,notnull"`
+	Sha256     string       `bun:"sha256,nullzero"`
+	MediaUrl   string       `bun:"media_url,notnull"`
+	ApprovedAt bun.NullTime `bun:"approved_at"`
+	CreatedAt  time.Time    `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt  time.Time    `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+// ContentSnapshot is the last content of each task action.
+type ContentSnapshot struct {
+	bun.BaseModel `bun:"table:content_snapshots,alias:content_snapshot"`
+
+	TaskId    string    `bun:"task_id,pk"`
+	ActionId  string    `bun:"action_id,pk"`
+	RunId     string    `bun:"run_id,notnull"`
+	Kind      string    `bun:"kind,notnull"`
+	Value     string    `bun:"value,notnull"`
+	MediaId   string    `bun:"media_id,nullzero"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type ContentChange struct {
+	bun.BaseModel `bun:"table:content_changes,alias:content_change"`
+
+	ID              string    `bun:"id,pk"`
+	TaskId          string    `bun:"task_id,notnull"`
+	ActionId        string    `bun:"action_id,notnull"`
+	RunId           string    `bun:"run_id,notnull"`
+	PreviousRunId   string    `bun:"previous_run_id,notnull"`
+	Kind            string    `bun:"kind,notnull"`
+	Previous        string    `bun:"previous,notnull"`
+	Current         string    `bun:"current,notnull"`
+	MediaId         string    `bun:"media_id,nullzero"`
+	PreviousMediaId string    `bun:"previous_media_id,nullzero"`
+	Distance        int       `bun:"distance,notnull"`
+	DetectedAt      time.Time `bun:"detected_at,notnull"`
+}
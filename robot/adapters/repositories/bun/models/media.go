@@ -1,6 +1,7 @@
 package models
 
 import (
+	"automator-go/robot/entities/models"
 	"github.com/uptrace/bun"
 	"time"
 )
@@ -8,20 +9,48 @@ import (
 type Media struct {
 	bun.BaseModel `bun:"table:media,alias:media"`
 
-	ID            string                 `bun:"id,pk"`
-	Attributes    map[string]interface{} `bun:"attributes,type:jsonb,nullzero"`
-	Height        float64                `bun:"height,notnull"`
-	Width         float64                `bun:"width,notnull"`
-	X             float64                `bun:"x,notnull"`
-	Y             float64                `bun:"y,notnull"`
-	Url           string                 `bun:"url,notnull"`
-	PHash         string                 `bun:"phash,notnull"`
-	Filename      string                 `bun:"filename,notnull"`
-	MediaUrl      string                 `bun:"media_url,notnull"`
-	ScreenshotUrl string                 `bun:"screenshot_url,notnull"`
-	ResourceUrl   string                 `bun:"resource_url,nullzero"`
-	TaskId        string                 `bun:"task_id,notnull"`
-	CreatedAt     time.Time              `bun:"created_at,nullzero,notnull,default:current_timestamp"`
-	UpdatedAt     time.Time              `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
-	DeletedAt     bun.NullTime           `bun:"deleted_at"`
+	ID             string                 `bun:"id,pk"`
+	Attributes     map[string]interface{} `bun:"attributes,type:jsonb,nullzero"`
+	Height         float64                `bun:"height,notnull"`
+	Width          float64                `bun:"width,notnull"`
+	X              float64                `bun:"x,notnull"`
+	Y              float64                `bun:"y,notnull"`
+	Url            string                 `bun:"url,notnull"`
+	PHash          string                 `bun:"phash,notnull"`
+	Filename       string                 `bun:"filename,notnull"`
+	MediaUrl       string                 `bun:"media_url,notnull"`
+	ScreenshotUrl  string                 `bun:"screenshot_url,notnull"`
+	ResourceUrl    string                 `bun:"resource_url,nullzero"`
+	TaskId         string                 `bun:"task_id,notnull"`
+	RunId          string                 `bun:"run_id,nullzero"`
+	ActionId       string                 `bun:"action_id,nullzero"`
+	VisualDiff     *models.VisualDiff     `bun:"visual_diff,type:jsonb,nullzero"`
+	CreatedAt      time.Time              `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt      time.Time              `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	DeletedAt      bun.NullTime           `bun:"deleted_at"`
+	Hashes         []MediaHash            `bun:"rel:has-many,join:id=media_id"`
+	ResourceHashes []MediaResourceHash    `bun:"rel:has-many,join:id=media_id"`
+	// Distance is only selected by the similarity queries.
+	Distance int `bun:"distance,scanonly"`
+}
+
+type MediaHash struct {
+	bun.BaseModel `bun:"table:media_hashes,alias:media_hash"`
+
+	MediaId   string    `bun:"media_id,pk"`
+	Algorithm string    `bun:"algorithm,pk"`
+	Value     string    `bun:"value,notnull"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
+
+// MediaResourceHash is a hash of the resource of a media, Position is the
+// frame or the keyframe it hashes.
+type MediaResourceHash struct {
+	bun.BaseModel `bun:"table:media_resource_hashes,alias:resource_hash"`
+
+	MediaId   string    `bun:"media_id,pk"`
+	Position  int       `bun:"position,pk"`
+	Algorithm string    `bun:"algorithm,notnull"`
+	Value     string    `bun:"value,notnull"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 }
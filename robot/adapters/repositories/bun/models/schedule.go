@@ -0,0 +1,24 @@
+package models
+
+import (
+	"automator-go/robot/entities/models"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type Schedule struct {
+	bun.BaseModel `bun:"table:schedules,alias:schedule"`
+
+	ID          string      `bun:"id,pk"`
+	Cron        string      `bun:"cron,nullzero"`
+	RunInterval string      `bun:"run_interval,nullzero"`
+	Timezone    string      `bun:"timezone,nullzero"`
+	Jitter      string      `bun:"jitter,nullzero"`
+	MissedRuns  string      `bun:"missed_runs,notnull"`
+	Disabled    bool        `bun:"disabled,notnull"`
+	Task        models.Task `bun:"task,type:jsonb,notnull"`
+	NextRunAt   time.Time   `bun:"next_run_at,nullzero"`
+	LastRunAt   *time.Time  `bun:"last_run_at,nullzero"`
+	CreatedAt   time.Time   `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt   time.Time   `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
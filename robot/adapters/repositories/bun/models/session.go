@@ -0,0 +1,17 @@
+package models
+
+import (
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type Session struct {
+	bun.BaseModel `bun:"table:sessions,alias:session"`
+
+	Name      string    `bun:"name,pk"`
+	Data      []byte    `bun:"data,type:bytea,notnull"`
+	SavedAt   time.Time `bun:"saved_at,notnull"`
+	ExpiresAt time.Time `bun:"expires_at,notnull"`
+	CreatedAt time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"automator-go/robot/entities/models"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type Strategy struct {
+	bun.BaseModel `bun:"table:strategies,alias:strategy"`
+
+	ID          string              `bun:"id,pk"`
+	Name        string              `bun:"name,notnull"`
+	Description string              `bun:"description,nullzero"`
+	Site        string              `bun:"site,nullzero"`
+	Url         string              `bun:"url,nullzero"`
+	PreActions  []models.TaskAction `bun:"pre_actions,type:jsonb,nullzero"`
+	PostActions []models.TaskAction `bun:"post_actions,type:jsonb,nullzero"`
+	CreatedAt   time.Time           `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt   time.Time           `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"automator-go/robot/entities/models"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type TaskRun struct {
+	bun.BaseModel `bun:"table:task_runs,alias:task_run"`
+
+	ID         string             `bun:"id,pk"`
+	TaskId     string             `bun:"task_id,notnull"`
+	Status     string             `bun:"status,notnull"`
+	Error      string             `bun:"error,nullzero"`
+	HarUrl     string             `bun:"har_url,nullzero"`
+	VideoUrl   string             `bun:"video_url,nullzero"`
+	Events     []models.RunEvent  `bun:"events,type:jsonb,nullzero"`
+	Failure    *models.RunFailure `bun:"failure,type:jsonb,nullzero"`
+	Regressed  bool               `bun:"regressed,notnull"`
+	StartedAt  time.Time          `bun:"started_at,notnull"`
+	FinishedAt time.Time          `bun:"finished_at,notnull"`
+	CreatedAt  time.Time          `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+}
@@ -0,0 +1,97 @@
+package bun
+
+import (
+	bunModels "automator-go/robot/adapters/repositories/bun/models"
+	"automator-go/robot/entities/models"
+	"context"
+	"fmt"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type Schedule struct {
+	db *bun.DB
+}
+
+func NewBunSchedule(db *bun.DB) *Schedule {
+	return &Schedule{db: db}
+}
+
+func (b *Schedule) GetDueSchedules(now time.Time, ctx context.Context) ([]*models.Schedule, error) {
+	var schedules []bunModels.Schedule
+	err := b.db.NewSelect().
+		Model(&schedules).
+		Where("NOT disabled").
+		Where("next_run_at IS NULL OR next_run_at <= ?", now).
+		Order("next_run_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting due schedules: %w", err)
+	}
+
+	result := make([]*models.Schedule, 0, len(schedules))
+	for i := range schedules {
+		result = append(result, MapBunScheduleToModel(&schedules[i]))
+	}
+
+	return result, nil
+}
+
+func (b *Schedule) SaveRuns(schedule *models.Schedule, previous time.Time, ctx context.Context) (bool, error) {
+	result, err := b.db.NewUpdate().
+		Model((*bunModels.Schedule)(nil)).
+		Set("next_run_at = ?", bun.NullTime{Time: schedule.NextRunAt}).
+		Set("last_run_at = ?", schedule.LastRunAt).
+		Where("id = ?", schedule.Id).
+		Where("next_run_at IS NOT DISTINCT FROM ?", bun.NullTime{Time: previous}).
+		Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error saving schedule runs: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error saving schedule runs: %w", err)
+	}
+
+	return updated == 1, nil
+}
+
+// SaveSchedule saves the definition of the schedule. The next run time of a
+// schedule already saved is kept, unless its times changed.
+func (b *Schedule) SaveSchedule(schedule *models.Schedule, ctx context.Context) error {
+	bunSchedule := bunModels.Schedule{
+		ID:          schedule.Id,
+		Cron:        schedule.Cron,
+		RunInterval: schedule.Interval,
+		Timezone:    schedule.Timezone,
+		Jitter:      schedule.Jitter,
+		MissedRuns:  string(schedule.MissedRunsPolicy()),
+		Disabled:    schedule.Disabled,
+		Task:        schedule.Task,
+		NextRunAt:   schedule.NextRunAt,
+		UpdatedAt:   time.Now(),
+	}
+
+	_, err := b.db.NewInsert().
+		Model(&bunSchedule).
+		On("CONFLICT (id) DO UPDATE").
+		Set("next_run_at = CASE WHEN schedule.cron IS DISTINCT FROM EXCLUDED.cron" +
+			" OR schedule.run_interval IS DISTINCT FROM EXCLUDED.run_interval" +
+			" OR schedule.timezone IS DISTINCT FROM EXCLUDED.timezone" +
+			" THEN EXCLUDED.next_run_at ELSE schedule.next_run_at END").
+		Set("cron = EXCLUDED.cron").
+		Set("run_interval = EXCLUDED.run_interval").
+		Set("timezone = EXCLUDED.timezone").
+		Set("jitter = EXCLUDED.jitter").
+		Set("missed_runs = EXCLUDED.missed_runs").
+		Set("disabled = EXCLUDED.disabled").
+		Set("task = EXCLUDED.task").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("error saving schedule: %w", err)
+	}
+
+	return nil
+}
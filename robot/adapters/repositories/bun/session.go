@@ -0,0 +1,130 @@
+package bun
+
+import (
+	bunModels "automator-go/robot/adapters/repositories/bun/models"
+	"automator-go/robot/entities/models"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/uptrace/bun"
+	"io"
+	"time"
+)
+
+// sessionData is the encrypted part of the session.
+type sessionData struct {
+	Cookies []models.SessionCookie           `json:"cookies"`
+	Storage map[string]models.SessionStorage `json:"storage"`
+}
+
+// SessionStore saves the sessions encrypted with AES-GCM, they hold the
+// credentials of the sites the tasks log in.
+type SessionStore struct {
+	db   *bun.DB
+	aead cipher.AEAD
+}
+
+// NewBunSessionStore needs a 32 bytes key to use AES-256.
+func NewBunSessionStore(db *bun.DB, key []byte) (*SessionStore, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("session encryption key must be 32 bytes long, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating session cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating session cipher: %w", err)
+	}
+
+	return &SessionStore{db: db, aead: aead}, nil
+}
+
+func (s *SessionStore) encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	return s.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (s *SessionStore) decrypt(ciphertext []byte) ([]byte, error) {
+	nonceSize := s.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, errors.New("encrypted session is too short")
+	}
+
+	return s.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+}
+
+func (s *SessionStore) Save(session *models.Session, ctx context.Context) error {
+	data, err := json.Marshal(sessionData{Cookies: session.Cookies, Storage: session.Storage})
+	if err != nil {
+		return fmt.Errorf("error marshalling session: %w", err)
+	}
+
+	encrypted, err := s.encrypt(data)
+	if err != nil {
+		return fmt.Errorf("error encrypting session: %w", err)
+	}
+
+	bunSession := bunModels.Session{
+		Name:      session.Name,
+		Data:      encrypted,
+		SavedAt:   session.SavedAt,
+		ExpiresAt: session.ExpiresAt,
+		UpdatedAt: time.Now(),
+	}
+
+	_, err = s.db.NewInsert().
+		Model(&bunSession).
+		On("CONFLICT (name) DO UPDATE").
+		Set("data = EXCLUDED.data").
+		Set("saved_at = EXCLUDED.saved_at").
+		Set("expires_at = EXCLUDED.expires_at").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("error saving session: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SessionStore) Get(name string, ctx context.Context) (*models.Session, error) {
+	bunSession := &bunModels.Session{}
+	err := s.db.NewSelect().Model(bunSession).Where("name = ?", name).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting session: %w", err)
+	}
+
+	decrypted, err := s.decrypt(bunSession.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting session: %w", err)
+	}
+
+	var data sessionData
+	if err = json.Unmarshal(decrypted, &data); err != nil {
+		return nil, fmt.Errorf("error unmarshalling session: %w", err)
+	}
+
+	return &models.Session{
+		Name:      bunSession.Name,
+		Cookies:   data.Cookies,
+		Storage:   data.Storage,
+		SavedAt:   bunSession.SavedAt,
+		ExpiresAt: bunSession.ExpiresAt,
+	}, nil
+}
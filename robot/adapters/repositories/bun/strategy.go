@@ -0,0 +1,63 @@
+package bun
+
+import (
+	bunModels "automator-go/robot/adapters/repositories/bun/models"
+	"automator-go/robot/entities/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/uptrace/bun"
+	"time"
+)
+
+type Strategy struct {
+	db *bun.DB
+}
+
+func NewBunStrategy(db *bun.DB) *Strategy {
+	return &Strategy{db: db}
+}
+
+func (b *Strategy) GetStrategy(id string, ctx context.Context) (*models.Strategy, error) {
+	strategy := &bunModels.Strategy{}
+	err := b.db.NewSelect().Model(strategy).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting strategy: %w", err)
+	}
+
+	return MapBunStrategyToModel(strategy), nil
+}
+
+func (b *Strategy) SaveStrategy(strategy *models.Strategy, ctx context.Context) error {
+	bunStrategy := bunModels.Strategy{
+		ID:          strategy.Id,
+		Name:        strategy.Name,
+		Description: strategy.Description,
+		Site:        strategy.Site,
+		Url:         strategy.Url,
+		PreActions:  strategy.PreActions,
+		PostActions: strategy.PostActions,
+		UpdatedAt:   time.Now(),
+	}
+
+	_, err := b.db.NewInsert().
+		Model(&bunStrategy).
+		On("CONFLICT (id) DO UPDATE").
+		Set("name = EXCLUDED.name").
+		Set("description = EXCLUDED.description").
+		Set("site = EXCLUDED.site").
+		Set("url = EXCLUDED.url").
+		Set("pre_actions = EXCLUDED.pre_actions").
+		Set("post_actions = EXCLUDED.post_actions").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("error saving strategy: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package bun
+
+import (
+	bunModels "automator-go/robot/adapters/repositories/bun/models"
+	"automator-go/robot/entities/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/uptrace/bun"
+)
+
+type TaskRun struct {
+	db *bun.DB
+}
+
+func NewBunTaskRun(db *bun.DB) *TaskRun {
+	return &TaskRun{db: db}
+}
+
+func (b *TaskRun) GetTaskRun(id string, ctx context.Context) (*models.TaskRun, error) {
+	run := &bunModels.TaskRun{}
+	err := b.db.NewSelect().Model(run).Where("id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting task run: %w", err)
+	}
+
+	return MapBunTaskRunToModel(run), nil
+}
+
+func (b *TaskRun) SaveTaskRun(run *models.TaskRun, ctx context.Context) error {
+	bunRun := bunModels.TaskRun{
+		ID:         run.Id,
+		TaskId:     run.TaskId,
+		Status:     string(run.Status),
+		Error:      run.Error,
+		HarUrl:     run.HarUrl,
+		VideoUrl:   run.VideoUrl,
+		Events:     run.Events,
+		Failure:    run.Failure,
+		Regressed:  run.Regressed,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+	}
+
+	_, err := b.db.NewInsert().Model(&bunRun).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("error saving task run: %w", err)
+	}
+
+	return nil
+}
@@ -12,22 +12,119 @@ func MapBunMediaToModel(media *bunModels.Media) *models.Media {
 		deletedAt = &media.DeletedAt.Time
 	}
 
+	hashes := make([]models.MediaHash, 0, len(media.Hashes))
+	for _, hash := range media.Hashes {
+		hashes = append(hashes, models.MediaHash{
+			Algorithm: models.HashAlgorithm(hash.Algorithm),
+			Value:     hash.Value,
+		})
+	}
+	resourceHashes := make([]models.MediaHash, 0, len(media.ResourceHashes))
+	for _, hash := range media.ResourceHashes {
+		resourceHashes = append(resourceHashes, models.MediaHash{
+			Algorithm: models.HashAlgorithm(hash.Algorithm),
+			Value:     hash.Value,
+		})
+	}
+
 	return &models.Media{
-		Id:            media.ID,
-		Attributes:    media.Attributes,
-		Height:        media.Height,
-		Width:         media.Width,
-		X:             media.X,
-		Y:             media.Y,
-		Url:           media.Url,
-		PHash:         media.PHash,
-		Filename:      media.Filename,
-		MediaUrl:      media.MediaUrl,
-		ScreenshotUrl: media.ScreenshotUrl,
-		ResourceUrl:   media.ResourceUrl,
-		TaskId:        media.TaskId,
-		CreatedAt:     media.CreatedAt,
-		UpdatedAt:     media.UpdatedAt,
-		DeletedAt:     deletedAt,
+		Id:             media.ID,
+		Attributes:     media.Attributes,
+		Height:         media.Height,
+		Width:          media.Width,
+		X:              media.X,
+		Y:              media.Y,
+		Url:            media.Url,
+		PHash:          media.PHash,
+		ResourceHashes: resourceHashes,
+		Hashes:         hashes,
+		Filename:       media.Filename,
+		MediaUrl:       media.MediaUrl,
+		ScreenshotUrl:  media.ScreenshotUrl,
+		ResourceUrl:    media.ResourceUrl,
+		TaskId:         media.TaskId,
+		ActionId:       media.ActionId,
+		RunId:          media.RunId,
+		VisualDiff:     media.VisualDiff,
+		CreatedAt:      media.CreatedAt,
+		UpdatedAt:      media.UpdatedAt,
+		DeletedAt:      deletedAt,
+	}
+}
+
+func MapBunStrategyToModel(strategy *bunModels.Strategy) *models.Strategy {
+	return &models.Strategy{
+		Id:          strategy.ID,
+		Name:        strategy.Name,
+		Description: strategy.Description,
+		Site:        strategy.Site,
+		Url:         strategy.Url,
+		PreActions:  strategy.PreActions,
+		PostActions: strategy.PostActions,
+	}
+}
+
+func MapBunTaskRunToModel(run *bunModels.TaskRun) *models.TaskRun {
+	return &models.TaskRun{
+		Id:         run.ID,
+		TaskId:     run.TaskId,
+		Status:     models.TaskRunStatus(run.Status),
+		Error:      run.Error,
+		HarUrl:     run.HarUrl,
+		VideoUrl:   run.VideoUrl,
+		Events:     run.Events,
+		Failure:    run.Failure,
+		Regressed:  run.Regressed,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+	}
+}
+
+func MapBunBaselineToModel(baseline *bunModels.Baseline) *models.Baseline {
+	var approvedAt *time.Time
+	if !baseline.ApprovedAt.IsZero() {
+		approvedAt = &baseline.ApprovedAt.Time
+	}
+
+	return &models.Baseline{
+		TaskId:     baseline.TaskId,
+		ActionId:   baseline.ActionId,
+		MediaId:    baseline.MediaId,
+		PHash:      baseline.PHash,
+		Sha256:     baseline.Sha256,
+		MediaUrl:   baseline.MediaUrl,
+		ApprovedAt: approvedAt,
+	}
+}
+
+func MapBunContentChangeToModel(change *bunModels.ContentChange) *models.ContentChange {
+	return &models.ContentChange{
+		Id:              change.ID,
+		TaskId:          change.TaskId,
+		ActionId:        change.ActionId,
+		RunId:           change.RunId,
+		PreviousRunId:   change.PreviousRunId,
+		Kind:            models.ContentKind(change.Kind),
+		Previous:        change.Previous,
+		Current:         change.Current,
+		MediaId:         change.MediaId,
+		PreviousMediaId: change.PreviousMediaId,
+		Distance:        change.Distance,
+		DetectedAt:      change.DetectedAt,
+	}
+}
+
+func MapBunScheduleToModel(schedule *bunModels.Schedule) *models.Schedule {
+	return &models.Schedule{
+		Id:         schedule.ID,
+		Cron:       schedule.Cron,
+		Interval:   schedule.RunInterval,
+		Timezone:   schedule.Timezone,
+		Jitter:     schedule.Jitter,
+		MissedRuns: models.MissedRunPolicy(schedule.MissedRuns),
+		Disabled:   schedule.Disabled,
+		Task:       schedule.Task,
+		NextRunAt:  schedule.NextRunAt,
+		LastRunAt:  schedule.LastRunAt,
 	}
 }
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    name varchar(255) PRIMARY KEY,
+    data bytea NOT NULL,
+    saved_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
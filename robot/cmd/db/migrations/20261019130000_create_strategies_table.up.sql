@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS strategies (
+    id varchar(64) PRIMARY KEY,
+    name varchar(255) NOT NULL,
+    description text,
+    site varchar(255),
+    url varchar(255),
+    pre_actions jsonb,
+    post_actions jsonb,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS strategies_site_idx ON strategies (site);
@@ -0,0 +1,3 @@
+ALTER TABLE media DROP COLUMN IF EXISTS run_id;
+
+DROP TABLE IF EXISTS task_runs;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS task_runs (
+    id varchar(32) PRIMARY KEY,
+    task_id varchar(32) NOT NULL,
+    status varchar(32) NOT NULL,
+    error text,
+    har_url varchar(255),
+    started_at timestamp with time zone NOT NULL,
+    finished_at timestamp with time zone NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS task_runs_task_id_idx ON task_runs (task_id);
+
+ALTER TABLE media ADD COLUMN IF NOT EXISTS run_id varchar(32);
@@ -0,0 +1 @@
+ALTER TABLE task_runs ADD COLUMN IF NOT EXISTS failure jsonb;
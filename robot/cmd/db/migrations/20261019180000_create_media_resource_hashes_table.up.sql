@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS media_resource_hashes (
+    media_id varchar(32) NOT NULL REFERENCES media (id) ON DELETE CASCADE,
+    position integer NOT NULL,
+    algorithm varchar(16) NOT NULL,
+    value varchar(64) NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (media_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS media_resource_hashes_algorithm_value_idx ON media_resource_hashes (algorithm, value);
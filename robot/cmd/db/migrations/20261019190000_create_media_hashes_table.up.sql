@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS media_hashes (
+    media_id varchar(32) NOT NULL REFERENCES media (id) ON DELETE CASCADE,
+    algorithm varchar(16) NOT NULL,
+    value varchar(64) NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (media_id, algorithm)
+);
+
+CREATE INDEX IF NOT EXISTS media_hashes_algorithm_value_idx ON media_hashes (algorithm, value);
+
+INSERT INTO media_hashes (media_id, algorithm, value, created_at)
+SELECT id, 'phash', split_part(phash, ':', 2), created_at
+FROM media
+WHERE phash LIKE 'p:%'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+ALTER TABLE task_runs DROP COLUMN IF EXISTS regressed;
+
+ALTER TABLE media DROP COLUMN IF EXISTS visual_diff;
+ALTER TABLE media DROP COLUMN IF EXISTS action_id;
+
+DROP TABLE IF EXISTS baselines;
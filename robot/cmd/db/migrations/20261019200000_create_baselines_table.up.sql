@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS baselines (
+    task_id varchar(32) NOT NULL,
+    action_id varchar(255) NOT NULL,
+    media_id varchar(32) NOT NULL REFERENCES media (id) ON DELETE CASCADE,
+    phash varchar(16) NOT NULL,
+    sha256 varchar(64),
+    media_url varchar(255) NOT NULL,
+    approved_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (task_id, action_id)
+);
+
+ALTER TABLE media ADD COLUMN IF NOT EXISTS action_id varchar(255);
+ALTER TABLE media ADD COLUMN IF NOT EXISTS visual_diff jsonb;
+
+ALTER TABLE task_runs ADD COLUMN IF NOT EXISTS regressed boolean NOT NULL DEFAULT false;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS content_snapshots (
+    task_id varchar(32) NOT NULL,
+    action_id varchar(255) NOT NULL,
+    run_id varchar(32) NOT NULL,
+    kind varchar(16) NOT NULL,
+    value text NOT NULL,
+    media_id varchar(32),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (task_id, action_id)
+);
+
+CREATE TABLE IF NOT EXISTS content_changes (
+    id varchar(32) PRIMARY KEY,
+    task_id varchar(32) NOT NULL,
+    action_id varchar(255) NOT NULL,
+    run_id varchar(32) NOT NULL,
+    previous_run_id varchar(32) NOT NULL,
+    kind varchar(16) NOT NULL,
+    previous text NOT NULL,
+    current text NOT NULL,
+    media_id varchar(32),
+    previous_media_id varchar(32),
+    distance integer NOT NULL DEFAULT 0,
+    detected_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS content_changes_task_id_detected_at_idx ON content_changes (task_id, detected_at);
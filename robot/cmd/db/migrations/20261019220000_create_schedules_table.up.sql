@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS schedules (
+    id varchar(64) PRIMARY KEY,
+    cron varchar(255),
+    run_interval varchar(32),
+    timezone varchar(64),
+    jitter varchar(32),
+    missed_runs varchar(16) NOT NULL,
+    disabled boolean NOT NULL DEFAULT false,
+    task jsonb NOT NULL,
+    next_run_at timestamp with time zone,
+    last_run_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS schedules_next_run_at_idx ON schedules (next_run_at) WHERE NOT disabled;
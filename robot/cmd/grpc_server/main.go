@@ -3,6 +3,7 @@ package main
 import (
 	grpcDef "automator-go/grpc"
 	grpcController "automator-go/robot/adapters/controllers/grpc"
+	"automator-go/robot/adapters/gateways/storage"
 	bunRepo "automator-go/robot/adapters/repositories/bun"
 	utils2 "automator-go/utils"
 	"context"
@@ -51,6 +52,11 @@ func main() {
 	db := utils2.OpenDb()
 
 	repo := bunRepo.NewBunCaptureMedia(db)
+	taskRunRepo := bunRepo.NewBunTaskRun(db)
+	baselineRepo := bunRepo.NewBunBaseline(db)
+	changeRepo := bunRepo.NewBunContentChange(db)
+	logWithCtx := logger.Ctx(ctx)
+	fileStorage := storage.NewFileStorage("png", &logWithCtx)
 
 	flag.Parse()
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
@@ -64,7 +70,7 @@ func main() {
 		grpc.ChainStreamInterceptor(authInterceptor.Stream()),
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 	)
-	grpcDef.RegisterMediaServiceServer(s, grpcController.NewGrpcServer(repo, logger))
+	grpcDef.RegisterMediaServiceServer(s, grpcController.NewGrpcServer(repo, taskRunRepo, baselineRepo, changeRepo, fileStorage, logger))
 
 	go func() {
 		logger.Ctx(ctx).Info("Starting server...", zap.Int("port", *port))
@@ -0,0 +1,247 @@
+package main
+
+import (
+	bunRepo "automator-go/robot/adapters/repositories/bun"
+	"automator-go/robot/entities/models"
+	utils2 "automator-go/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/joho/godotenv"
+	"github.com/urfave/cli/v2"
+	"log"
+	"os"
+	"time"
+)
+
+func main() {
+	app := &cli.App{
+		Name:  "robot",
+		Usage: "robot tools",
+
+		Commands: []*cli.Command{
+			newLintCommand(),
+			newActionsCommand(),
+			newStrategiesCommand(),
+			newSchedulesCommand(),
+		},
+	}
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readTasks accepts both a list of tasks (as the file consumer reads them) and
+// a single task (as it is published on the queue).
+func readTasks(path string) ([]models.Task, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading tasks file: %w", err)
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(file), []byte("{")) {
+		var task models.Task
+		if err = json.Unmarshal(file, &task); err != nil {
+			return nil, fmt.Errorf("error unmarshalling task: %w", err)
+		}
+
+		return []models.Task{task}, nil
+	}
+
+	var tasks []models.Task
+	if err = json.Unmarshal(file, &tasks); err != nil {
+		return nil, fmt.Errorf("error unmarshalling tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+func newLintCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "lint",
+		Usage:     "validate task definitions without running them",
+		ArgsUsage: "<tasks.json>",
+		Action: func(c *cli.Context) error {
+			path := c.Args().First()
+			if path == "" {
+				return errors.New("tasks file is required")
+			}
+
+			tasks, err := readTasks(path)
+			if err != nil {
+				return err
+			}
+
+			invalid := 0
+			for _, task := range tasks {
+				err := task.Validate()
+				if err == nil {
+					fmt.Printf("task %s: ok\n", task.Id)
+					continue
+				}
+
+				invalid++
+				fmt.Printf("task %s: invalid\n", task.Id)
+				printErrors(err)
+			}
+
+			if invalid > 0 {
+				return cli.Exit(fmt.Sprintf("%d of %d tasks are invalid", invalid, len(tasks)), 1)
+			}
+
+			return nil
+		},
+	}
+}
+
+func printErrors(err error) {
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		for _, joinedErr := range joined.Unwrap() {
+			fmt.Printf("  - %s\n", joinedErr)
+		}
+	} else {
+		fmt.Printf("  - %s\n", err)
+	}
+}
+
+func newStrategiesCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "strategies",
+		Usage: "manage the strategies shared by the tasks",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "import",
+				Usage:     "validate and save the strategies of a file in the database",
+				ArgsUsage: "<strategies.json>",
+				Action: func(c *cli.Context) error {
+					path := c.Args().First()
+					if path == "" {
+						return errors.New("strategies file is required")
+					}
+
+					file, err := os.ReadFile(path)
+					if err != nil {
+						return fmt.Errorf("error reading strategies file: %w", err)
+					}
+
+					var strategies []*models.Strategy
+					if err = json.Unmarshal(file, &strategies); err != nil {
+						return fmt.Errorf("error unmarshalling strategies: %w", err)
+					}
+
+					invalid := 0
+					for _, strategy := range strategies {
+						if err = strategy.Validate(); err != nil {
+							invalid++
+							fmt.Printf("strategy %s: invalid\n", strategy.Id)
+							printErrors(err)
+						}
+					}
+					if invalid > 0 {
+						return cli.Exit(fmt.Sprintf("%d of %d strategies are invalid", invalid, len(strategies)), 1)
+					}
+
+					if err = godotenv.Load(); err != nil {
+						return errors.New("error loading .env file")
+					}
+					db := utils2.OpenDb()
+					defer db.Close()
+
+					strategyRepo := bunRepo.NewBunStrategy(db)
+					for _, strategy := range strategies {
+						if err = strategyRepo.SaveStrategy(strategy, context.Background()); err != nil {
+							return err
+						}
+						fmt.Printf("strategy %s: saved\n", strategy.Id)
+					}
+
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func newSchedulesCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "schedules",
+		Usage: "manage the schedules the scheduler publishes the tasks on",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "import",
+				Usage:     "validate and save the schedules of a file in the database",
+				ArgsUsage: "<schedules.json>",
+				Action: func(c *cli.Context) error {
+					path := c.Args().First()
+					if path == "" {
+						return errors.New("schedules file is required")
+					}
+
+					file, err := os.ReadFile(path)
+					if err != nil {
+						return fmt.Errorf("error reading schedules file: %w", err)
+					}
+
+					var schedules []*models.Schedule
+					if err = json.Unmarshal(file, &schedules); err != nil {
+						return fmt.Errorf("error unmarshalling schedules: %w", err)
+					}
+
+					invalid := 0
+					for _, schedule := range schedules {
+						if err = schedule.Validate(); err != nil {
+							invalid++
+							fmt.Printf("schedule %s: invalid\n", schedule.Id)
+							printErrors(err)
+						}
+					}
+					if invalid > 0 {
+						return cli.Exit(fmt.Sprintf("%d of %d schedules are invalid", invalid, len(schedules)), 1)
+					}
+
+					if err = godotenv.Load(); err != nil {
+						return errors.New("error loading .env file")
+					}
+					db := utils2.OpenDb()
+					defer db.Close()
+
+					scheduleRepo := bunRepo.NewBunSchedule(db)
+					for _, schedule := range schedules {
+						// Only used when the schedule is new or its times changed.
+						if schedule.NextRunAt, err = schedule.Next(time.Now()); err != nil {
+							return err
+						}
+						if err = scheduleRepo.SaveSchedule(schedule, context.Background()); err != nil {
+							return err
+						}
+						fmt.Printf("schedule %s: saved\n", schedule.Id)
+					}
+
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func newActionsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "actions",
+		Usage: "list the actions tasks can use and the value each one expects",
+		Action: func(c *cli.Context) error {
+			targets := [...]string{
+				models.PageTarget:            "page",
+				models.ElementTarget:         "element",
+				models.OptionalElementTarget: "page or element",
+			}
+			for _, spec := range models.Actions() {
+				fmt.Printf("%-18s %-16s %s\n", spec.Name, targets[spec.Target], spec.Value)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	schedulerController "automator-go/robot/adapters/controllers/scheduler"
+	utils2 "automator-go/utils"
+	"context"
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+	"log"
+	"os"
+	"os/signal"
+)
+
+const serviceName = "robot-scheduler"
+
+func main() {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	stopSignal := make(chan os.Signal, 1)
+	signal.Notify(stopSignal, os.Interrupt)
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	debugEnv := os.Getenv("APP_DEBUG")
+	debug := debugEnv == "true"
+	version := os.Getenv("APP_VERSION")
+
+	utils2.StartTrace(serviceName, version, debug)
+	defer utils2.ShutdownTrace(ctx)
+
+	ctx, span := utils2.StartSpan(ctx, serviceName, "root")
+
+	logWithCtx := utils2.StartLoggerWithCtx(ctx, debug)
+
+	db := utils2.OpenDb()
+	defer db.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		controller := schedulerController.NewSchedulerController(db, &logWithCtx, ctx)
+		if err := controller.Start(); err != nil {
+			logWithCtx.Fatal("error scheduling tasks", zap.Error(err))
+		}
+	}()
+
+	<-stopSignal
+	logWithCtx.Info("Shutting down")
+	stop()
+	<-done
+	span.End()
+}
@@ -5,7 +5,6 @@ import (
 	taskControllers "automator-go/robot/adapters/controllers/tasks"
 	utils2 "automator-go/utils"
 	"context"
-	"github.com/go-rod/rod"
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 	"log"
@@ -47,16 +46,34 @@ func main() {
 	db := utils2.OpenDb()
 
 	go func() {
-		browser := rod.New().Context(ctx)
-		err = browser.Connect()
+		farm, err := taskControllers.NewBrowserFarm(ctx, pagePoolNumber, &logWithCtx)
 		if err != nil {
-			logWithCtx.Fatal("error connecting to browser", zap.Error(err))
+			logWithCtx.Fatal("error connecting to browsers", zap.Error(err))
+		}
+		strategyRegistry, err := taskControllers.NewStrategyRegistry(db)
+		if err != nil {
+			logWithCtx.Fatal("error loading strategies", zap.Error(err))
+		}
+		blocklist, err := taskControllers.LoadNetworkBlocklist()
+		if err != nil {
+			logWithCtx.Fatal("error loading network blocklist", zap.Error(err))
 		}
-		logWithCtx.Debug("Connected to browser")
-
-		pagePool := rod.NewPagePool(pagePoolNumber)
 
-		taskController := taskControllers.NewTaskController(browser, pagePool, db, ctx, &logWithCtx)
+		changePublisher, shutdownPublisher, err := taskControllers.NewChangePublisher(&logWithCtx)
+		if err != nil {
+			logWithCtx.Fatal("error starting change publisher", zap.Error(err))
+		}
+		defer shutdownPublisher()
+
+		taskController := taskControllers.NewTaskController(
+			farm,
+			strategyRegistry,
+			blocklist,
+			changePublisher,
+			db,
+			ctx,
+			&logWithCtx,
+		)
 		consumerController := controllerConsumer.NewRabbitConsumerController(taskController, &logWithCtx, ctx)
 
 		errs := consumerController.ConsumeTasks()
@@ -64,12 +81,7 @@ func main() {
 			logWithCtx.Fatal("error processing tasks", zap.Errors("errors", errs))
 		}
 
-		pagePool.Cleanup(func(page *rod.Page) {
-			err := page.Close()
-			if err != nil {
-				logWithCtx.Error("error closing page", zap.Error(err))
-			}
-		})
+		farm.Close()
 	}()
 
 	<-stopSignal
@@ -1,10 +1,20 @@
 package models
 
 import (
+	"automator-go/robot/entities/validation"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+// TimeLayout is the format expected by WriteTime values.
+const TimeLayout = "2006-01-02T15:04:05Z04:00"
+
 type Action uint8
 
 const (
@@ -18,48 +28,161 @@ const (
 	WriteTime
 	ClearInput
 	DownloadResource
+	WaitForElement
+	WaitForNavigation
+	PressKey
+	Hover
+	DoubleClick
+	RightClick
+	DragAndDrop
+	UploadFile
+	ScrollTo
+	ScrollUp
+	ScrollToBottom
+	ScrollLeft
+	ScrollRight
+	EvaluateScript
+	SaveSession
+	LoadSession
+	ExtractText
+)
+
+// ActionTarget is what the action runs on.
+type ActionTarget uint8
+
+const (
+	// PageTarget actions run on the whole page.
+	PageTarget ActionTarget = iota
+	// ElementTarget actions run on an element selected from the page.
+	ElementTarget
+	// OptionalElementTarget actions run on the element given on
+	// TaskAction.Selector or on the whole page otherwise.
+	OptionalElementTarget
 )
 
+// ActionSpec declares an action: its name in the task definitions, what it
+// runs on, the value it expects and how that value is checked.
+type ActionSpec struct {
+	Name   string
+	Target ActionTarget
+	// InputValue actions need a value besides their element, so the element
+	// goes on TaskAction.Selector.
+	InputValue bool
+	// Static actions only need the HTML of the page, so the http driver can
+	// run them too.
+	Static bool
+	// Value describes the expected value, e.g. "positive integer".
+	Value string
+	// Validate checks the value, nil when any value is accepted.
+	Validate func(action *TaskAction) []error
+}
+
+var builtinActionSpecs = [...]ActionSpec{
+	Navigate:          {Name: "Navigate", Target: ElementTarget, Static: true, Value: "selector of the link"},
+	Click:             {Name: "Click", Target: ElementTarget, Value: "selector"},
+	ScrollDown:        {Name: "ScrollDown", Target: OptionalElementTarget, Value: "steps", Validate: validateSteps},
+	Capture:           {Name: "Capture", Target: ElementTarget, Value: "selector"},
+	WaitSeconds:       {Name: "WaitSeconds", Target: PageTarget, Static: true, Value: "seconds", Validate: validateSteps},
+	WriteInput:        {Name: "WriteInput", Target: ElementTarget, InputValue: true, Value: "text"},
+	SelectOptions:     {Name: "SelectOptions", Target: ElementTarget, InputValue: true, Value: "comma separated options", Validate: validateOptions},
+	WriteTime:         {Name: "WriteTime", Target: ElementTarget, InputValue: true, Value: "time with layout " + TimeLayout, Validate: validateTime},
+	ClearInput:        {Name: "ClearInput", Target: ElementTarget, Value: "selector"},
+	DownloadResource:  {Name: "DownloadResource", Target: ElementTarget, Static: true, Value: "selector"},
+	WaitForElement:    {Name: "WaitForElement", Target: ElementTarget, Static: true, Value: "selector"},
+	WaitForNavigation: {Name: "WaitForNavigation", Target: PageTarget, Value: "optional url fragment"},
+	PressKey:          {Name: "PressKey", Target: OptionalElementTarget, Value: "key combination", Validate: validateKeys},
+	Hover:             {Name: "Hover", Target: ElementTarget, Value: "selector"},
+	DoubleClick:       {Name: "DoubleClick", Target: ElementTarget, Value: "selector"},
+	RightClick:        {Name: "RightClick", Target: ElementTarget, Value: "selector"},
+	DragAndDrop:       {Name: "DragAndDrop", Target: ElementTarget, InputValue: true, Value: "selector of the drop target", Validate: validateDropTarget},
+	UploadFile:        {Name: "UploadFile", Target: ElementTarget, InputValue: true, Value: "comma separated file paths", Validate: validateFilePaths},
+	ScrollTo:          {Name: "ScrollTo", Target: ElementTarget, Value: "selector"},
+	ScrollUp:          {Name: "ScrollUp", Target: OptionalElementTarget, Value: "steps", Validate: validateSteps},
+	ScrollToBottom:    {Name: "ScrollToBottom", Target: OptionalElementTarget, Value: "optional maximum of scrolls", Validate: validateMaxScrolls},
+	ScrollLeft:        {Name: "ScrollLeft", Target: OptionalElementTarget, Value: "steps", Validate: validateSteps},
+	ScrollRight:       {Name: "ScrollRight", Target: OptionalElementTarget, Value: "steps", Validate: validateSteps},
+	EvaluateScript:    {Name: "EvaluateScript", Target: OptionalElementTarget, Value: "script", Validate: validateScript},
+	SaveSession:       {Name: "SaveSession", Target: PageTarget, Value: "optional session name", Validate: validateSessionName},
+	LoadSession:       {Name: "LoadSession", Target: PageTarget, Value: "optional session name", Validate: validateSessionName},
+	ExtractText:       {Name: "ExtractText", Target: ElementTarget, Static: true, Value: "selector"},
+}
+
+var (
+	actionsMu     sync.RWMutex
+	actionSpecs   = builtinActionSpecs[:]
+	actionsByName = func() map[string]Action {
+		names := make(map[string]Action, len(builtinActionSpecs))
+		for i, spec := range builtinActionSpecs {
+			names[spec.Name] = Action(i)
+		}
+		return names
+	}()
+)
+
+// RegisterAction adds an action to the ones tasks can use, so programs
+// embedding the robot can extend it. It must be called before reading the
+// tasks, e.g. from an init function, and the action needs a handler in the
+// automator too.
+func RegisterAction(spec ActionSpec) (Action, error) {
+	if strings.TrimSpace(spec.Name) == "" {
+		return 0, errors.New("action name is required")
+	}
+
+	actionsMu.Lock()
+	defer actionsMu.Unlock()
+
+	if _, ok := actionsByName[spec.Name]; ok {
+		return 0, fmt.Errorf("action %s is already registered", spec.Name)
+	}
+	if len(actionSpecs) > math.MaxUint8 {
+		return 0, fmt.Errorf("too many actions to register %s", spec.Name)
+	}
+
+	action := Action(len(actionSpecs))
+	actionSpecs = append(actionSpecs[:len(actionSpecs):len(actionSpecs)], spec)
+	actionsByName[spec.Name] = action
+
+	return action, nil
+}
+
+// Actions returns the specs of all the registered actions.
+func Actions() []ActionSpec {
+	actionsMu.RLock()
+	defer actionsMu.RUnlock()
+
+	return append([]ActionSpec(nil), actionSpecs...)
+}
+
+func (a *Action) Spec() (ActionSpec, bool) {
+	actionsMu.RLock()
+	defer actionsMu.RUnlock()
+
+	if int(*a) >= len(actionSpecs) {
+		return ActionSpec{}, false
+	}
+
+	return actionSpecs[*a], true
+}
+
 func (a *Action) String() string {
-	return [...]string{
-		"Navigate",
-		"Click",
-		"ScrollDown",
-		"Capture",
-		"WaitSeconds",
-		"WriteInput",
-		"SelectOptions",
-		"WriteTime",
-		"ClearInput",
-		"DownloadResource",
-	}[*a]
+	spec, ok := a.Spec()
+	if !ok {
+		return fmt.Sprintf("Action(%d)", *a)
+	}
+
+	return spec.Name
 }
 
 func (a *Action) FromString(s string) (Action, error) {
-	switch s {
-	case "Navigate":
-		return Navigate, nil
-	case "Click":
-		return Click, nil
-	case "ScrollDown":
-		return ScrollDown, nil
-	case "Capture":
-		return Capture, nil
-	case "WaitSeconds":
-		return WaitSeconds, nil
-	case "WriteInput":
-		return WriteInput, nil
-	case "ClearInput":
-		return ClearInput, nil
-	case "SelectOptions":
-		return SelectOptions, nil
-	case "WriteTime":
-		return WriteTime, nil
-	case "DownloadResource":
-		return DownloadResource, nil
-	default:
+	actionsMu.RLock()
+	defer actionsMu.RUnlock()
+
+	action, ok := actionsByName[s]
+	if !ok {
 		return Navigate, fmt.Errorf("invalid action %s", s)
 	}
+
+	return action, nil
 }
 
 func (a *Action) MarshalJSON() ([]byte, error) {
@@ -81,9 +204,214 @@ func (a *Action) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// TargetsElement reports whether the action runs on an element selected from the page.
+func (a *Action) TargetsElement() bool {
+	spec, _ := a.Spec()
+	return spec.Target == ElementTarget
+}
+
+// HasOptionalSelector reports whether the action may run on an element given
+// on TaskAction.Selector or on the whole page otherwise.
+func (a *Action) HasOptionalSelector() bool {
+	spec, _ := a.Spec()
+	return spec.Target == OptionalElementTarget
+}
+
+// HasInputValue reports whether the action needs a value besides its selector,
+// in which case the selector goes on TaskAction.Selector.
+func (a *Action) HasInputValue() bool {
+	spec, _ := a.Spec()
+	return spec.InputValue
+}
+
+// TaskAction is a step of a task or a strategy. Secret names the secret
+// written by a WriteInput instead of its Value, so credentials like passwords
+// are not stored with the strategies, it is resolved with the strategies.
 type TaskAction struct {
-	Id    string `json:"id"`
-	Label string `json:"label"`
-	Type  Action `json:"type"`
-	Value string `json:"value"`
+	Id       string         `json:"id"`
+	Label    string         `json:"label"`
+	Type     Action         `json:"type"`
+	Selector string         `json:"selector,omitempty"`
+	Value    string         `json:"value"`
+	Secret   string         `json:"secret,omitempty"`
+	Timeout  string         `json:"timeout,omitempty"`
+	WaitFor  *WaitCondition `json:"wait_for,omitempty"`
+}
+
+// WithSecret returns the action writing the value of its secret, resolved by
+// resolve. Actions without secret are returned as they are.
+func (ta TaskAction) WithSecret(resolve func(name string) (string, error)) (TaskAction, error) {
+	if ta.Secret == "" {
+		return ta, nil
+	}
+
+	value, err := resolve(ta.Secret)
+	if err != nil {
+		return ta, fmt.Errorf("error resolving secret %s: %w", ta.Secret, err)
+	}
+	ta.Value = value
+	ta.Secret = ""
+
+	return ta, nil
+}
+
+// Target returns the selector of the element the action runs on. Legacy
+// tasks keep it on Value, so it is used when Selector is empty.
+func (ta *TaskAction) Target() string {
+	if ta.Selector != "" {
+		return ta.Selector
+	}
+
+	return ta.Value
+}
+
+func (ta *TaskAction) Validate() error {
+	return errors.Join(ta.validate()...)
+}
+
+func (ta *TaskAction) validate() []error {
+	var errs []error
+
+	if strings.TrimSpace(ta.Id) == "" {
+		errs = append(errs, errors.New("id is required"))
+	}
+
+	if ta.Type.TargetsElement() {
+		// Without a selector, the target of input actions would be the text
+		// they input.
+		if ta.Type.HasInputValue() && strings.TrimSpace(ta.Selector) == "" {
+			errs = append(errs, errors.New("selector is required"))
+		} else if err := validation.ValidateSelector(ta.Target()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if ta.Type.HasOptionalSelector() && ta.Selector != "" {
+		if err := validation.ValidateSelector(ta.Selector); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if spec, ok := ta.Type.Spec(); !ok {
+		errs = append(errs, fmt.Errorf("unknown action %s", ta.Type.String()))
+	} else if spec.Validate != nil {
+		errs = append(errs, spec.Validate(ta)...)
+	}
+
+	if ta.Secret != "" {
+		if ta.Type != WriteInput {
+			errs = append(errs, errors.New("secret is only supported by WriteInput"))
+		}
+		if !secretNamePattern.MatchString(ta.Secret) {
+			errs = append(errs, fmt.Errorf("invalid secret name %q", ta.Secret))
+		}
+		if ta.Value != "" {
+			errs = append(errs, errors.New("secret and value can't be both set"))
+		}
+	}
+
+	if strings.TrimSpace(ta.Timeout) != "" {
+		timeout, err := time.ParseDuration(ta.Timeout)
+		if err != nil || timeout <= 0 {
+			errs = append(errs, fmt.Errorf("timeout must be a positive duration, got %q", ta.Timeout))
+		}
+	}
+
+	if ta.WaitFor != nil {
+		switch ta.WaitFor.Strategy {
+		case WaitVisible:
+			if !ta.Type.TargetsElement() {
+				errs = append(errs, fmt.Errorf("wait strategy %s requires an element action", ta.WaitFor.Strategy.String()))
+			}
+		case WaitPredicate:
+			if strings.TrimSpace(ta.WaitFor.Predicate) == "" {
+				errs = append(errs, errors.New("predicate wait strategy requires a predicate"))
+			}
+		}
+	}
+
+	return errs
+}
+
+func validateSteps(ta *TaskAction) []error {
+	value, err := strconv.ParseInt(ta.Value, 10, 64)
+	if err != nil || value < 1 {
+		return []error{fmt.Errorf("value must be a positive integer, got %q", ta.Value)}
+	}
+
+	return nil
+}
+
+// validateMaxScrolls accepts an empty value, the maximum is optional.
+func validateMaxScrolls(ta *TaskAction) []error {
+	if ta.Value == "" {
+		return nil
+	}
+
+	return validateSteps(ta)
+}
+
+func validateOptions(ta *TaskAction) []error {
+	for _, option := range strings.Split(ta.Value, ",") {
+		if strings.TrimSpace(option) == "" {
+			return []error{fmt.Errorf("options must not be empty, got %q", ta.Value)}
+		}
+	}
+
+	return nil
+}
+
+func validateTime(ta *TaskAction) []error {
+	if _, err := time.Parse(TimeLayout, ta.Value); err != nil {
+		return []error{fmt.Errorf("value must be a time with layout %s, got %q", TimeLayout, ta.Value)}
+	}
+
+	return nil
+}
+
+func validateKeys(ta *TaskAction) []error {
+	if err := validation.ValidateKeyCombination(ta.Value); err != nil {
+		return []error{err}
+	}
+
+	return nil
+}
+
+func validateScript(ta *TaskAction) []error {
+	if strings.TrimSpace(ta.Value) == "" {
+		return []error{errors.New("script is required")}
+	}
+
+	return nil
+}
+
+// validateSessionName accepts an empty value, the session of the task is
+// used then.
+func validateSessionName(ta *TaskAction) []error {
+	if ta.Value == "" {
+		return nil
+	}
+	if err := ValidateSessionName(ta.Value); err != nil {
+		return []error{err}
+	}
+
+	return nil
+}
+
+func validateDropTarget(ta *TaskAction) []error {
+	if err := validation.ValidateSelector(ta.Value); err != nil {
+		return []error{fmt.Errorf("drop target: %w", err)}
+	}
+
+	return nil
+}
+
+func validateFilePaths(ta *TaskAction) []error {
+	for _, path := range strings.Split(ta.Value, ",") {
+		if strings.TrimSpace(path) == "" {
+			return []error{fmt.Errorf("file paths must not be empty, got %q", ta.Value)}
+		}
+	}
+
+	return nil
 }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"maps"
 	"testing"
 )
 
@@ -50,6 +51,74 @@ func TestAction_String(t *testing.T) {
 			name: "DownloadResource",
 			a:    DownloadResource,
 		},
+		{
+			name: "WaitForElement",
+			a:    WaitForElement,
+		},
+		{
+			name: "WaitForNavigation",
+			a:    WaitForNavigation,
+		},
+		{
+			name: "PressKey",
+			a:    PressKey,
+		},
+		{
+			name: "Hover",
+			a:    Hover,
+		},
+		{
+			name: "DoubleClick",
+			a:    DoubleClick,
+		},
+		{
+			name: "RightClick",
+			a:    RightClick,
+		},
+		{
+			name: "DragAndDrop",
+			a:    DragAndDrop,
+		},
+		{
+			name: "UploadFile",
+			a:    UploadFile,
+		},
+		{
+			name: "ScrollTo",
+			a:    ScrollTo,
+		},
+		{
+			name: "ScrollUp",
+			a:    ScrollUp,
+		},
+		{
+			name: "ScrollToBottom",
+			a:    ScrollToBottom,
+		},
+		{
+			name: "ScrollLeft",
+			a:    ScrollLeft,
+		},
+		{
+			name: "ScrollRight",
+			a:    ScrollRight,
+		},
+		{
+			name: "EvaluateScript",
+			a:    EvaluateScript,
+		},
+		{
+			name: "SaveSession",
+			a:    SaveSession,
+		},
+		{
+			name: "LoadSession",
+			a:    LoadSession,
+		},
+		{
+			name: "ExtractText",
+			a:    ExtractText,
+		},
 	}
 
 	for _, tt := range tests {
@@ -120,6 +189,91 @@ func TestAction_MarshalJSON(t *testing.T) {
 			a:       DownloadResource,
 			wantErr: false,
 		},
+		{
+			name:    "WaitForElement",
+			a:       WaitForElement,
+			wantErr: false,
+		},
+		{
+			name:    "WaitForNavigation",
+			a:       WaitForNavigation,
+			wantErr: false,
+		},
+		{
+			name:    "PressKey",
+			a:       PressKey,
+			wantErr: false,
+		},
+		{
+			name:    "Hover",
+			a:       Hover,
+			wantErr: false,
+		},
+		{
+			name:    "DoubleClick",
+			a:       DoubleClick,
+			wantErr: false,
+		},
+		{
+			name:    "RightClick",
+			a:       RightClick,
+			wantErr: false,
+		},
+		{
+			name:    "DragAndDrop",
+			a:       DragAndDrop,
+			wantErr: false,
+		},
+		{
+			name:    "UploadFile",
+			a:       UploadFile,
+			wantErr: false,
+		},
+		{
+			name:    "ScrollTo",
+			a:       ScrollTo,
+			wantErr: false,
+		},
+		{
+			name:    "ScrollUp",
+			a:       ScrollUp,
+			wantErr: false,
+		},
+		{
+			name:    "ScrollToBottom",
+			a:       ScrollToBottom,
+			wantErr: false,
+		},
+		{
+			name:    "ScrollLeft",
+			a:       ScrollLeft,
+			wantErr: false,
+		},
+		{
+			name:    "ScrollRight",
+			a:       ScrollRight,
+			wantErr: false,
+		},
+		{
+			name:    "EvaluateScript",
+			a:       EvaluateScript,
+			wantErr: false,
+		},
+		{
+			name:    "SaveSession",
+			a:       SaveSession,
+			wantErr: false,
+		},
+		{
+			name:    "LoadSession",
+			a:       LoadSession,
+			wantErr: false,
+		},
+		{
+			name:    "ExtractText",
+			a:       ExtractText,
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -192,6 +346,91 @@ func TestAction_UnmarshalJSON(t *testing.T) {
 			value:   []byte("\"DownloadResource\""),
 			wantErr: false,
 		},
+		{
+			name:    "WaitForElement",
+			value:   []byte("\"WaitForElement\""),
+			wantErr: false,
+		},
+		{
+			name:    "WaitForNavigation",
+			value:   []byte("\"WaitForNavigation\""),
+			wantErr: false,
+		},
+		{
+			name:    "PressKey",
+			value:   []byte("\"PressKey\""),
+			wantErr: false,
+		},
+		{
+			name:    "Hover",
+			value:   []byte("\"Hover\""),
+			wantErr: false,
+		},
+		{
+			name:    "DoubleClick",
+			value:   []byte("\"DoubleClick\""),
+			wantErr: false,
+		},
+		{
+			name:    "RightClick",
+			value:   []byte("\"RightClick\""),
+			wantErr: false,
+		},
+		{
+			name:    "DragAndDrop",
+			value:   []byte("\"DragAndDrop\""),
+			wantErr: false,
+		},
+		{
+			name:    "UploadFile",
+			value:   []byte("\"UploadFile\""),
+			wantErr: false,
+		},
+		{
+			name:    "ScrollTo",
+			value:   []byte("\"ScrollTo\""),
+			wantErr: false,
+		},
+		{
+			name:    "ScrollUp",
+			value:   []byte("\"ScrollUp\""),
+			wantErr: false,
+		},
+		{
+			name:    "ScrollToBottom",
+			value:   []byte("\"ScrollToBottom\""),
+			wantErr: false,
+		},
+		{
+			name:    "ScrollLeft",
+			value:   []byte("\"ScrollLeft\""),
+			wantErr: false,
+		},
+		{
+			name:    "ScrollRight",
+			value:   []byte("\"ScrollRight\""),
+			wantErr: false,
+		},
+		{
+			name:    "EvaluateScript",
+			value:   []byte("\"EvaluateScript\""),
+			wantErr: false,
+		},
+		{
+			name:    "SaveSession",
+			value:   []byte("\"SaveSession\""),
+			wantErr: false,
+		},
+		{
+			name:    "LoadSession",
+			value:   []byte("\"LoadSession\""),
+			wantErr: false,
+		},
+		{
+			name:    "ExtractText",
+			value:   []byte("\"ExtractText\""),
+			wantErr: false,
+		},
 		{
 			name:    "Invalid",
 			value:   []byte("\"Invalid\""),
@@ -218,3 +457,105 @@ func TestAction_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+// restoreActions removes the actions the test registers once it finishes, so
+// they don't leak into the other tests.
+func restoreActions(t *testing.T) {
+	actionsMu.RLock()
+	specs, byName := actionSpecs, maps.Clone(actionsByName)
+	actionsMu.RUnlock()
+
+	t.Cleanup(func() {
+		actionsMu.Lock()
+		defer actionsMu.Unlock()
+
+		actionSpecs, actionsByName = specs, byName
+	})
+}
+
+func TestRegisterAction(t *testing.T) {
+	restoreActions(t)
+
+	tests := []struct {
+		name    string
+		spec    ActionSpec
+		wantErr bool
+	}{
+		{
+			name:    "New action",
+			spec:    ActionSpec{Name: "SolveCaptcha", Target: ElementTarget, Value: "selector"},
+			wantErr: false,
+		},
+		{
+			name:    "Built-in action",
+			spec:    ActionSpec{Name: "Click", Target: ElementTarget},
+			wantErr: true,
+		},
+		{
+			name:    "Without name",
+			spec:    ActionSpec{Target: PageTarget},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, err := RegisterAction(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RegisterAction() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if action.String() != tt.spec.Name {
+				t.Errorf("Action.String() = %v, want %v", action.String(), tt.spec.Name)
+			}
+			got, err := action.FromString(tt.spec.Name)
+			if err != nil || got != action {
+				t.Errorf("Action.FromString() = %v, %v, want %v", got, err, action)
+			}
+			if action.TargetsElement() != (tt.spec.Target == ElementTarget) {
+				t.Errorf("Action.TargetsElement() = %v", action.TargetsElement())
+			}
+
+			taskAction := TaskAction{Id: "1", Type: action, Value: "#captcha"}
+			if errs := taskAction.validate(); len(errs) > 0 {
+				t.Errorf("TaskAction.validate() errors = %v", errs)
+			}
+		})
+	}
+}
+
+func TestTaskAction_validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     TaskAction
+		wantErrors int
+	}{
+		{
+			name:       "Input with selector",
+			action:     TaskAction{Id: "1", Type: WriteInput, Selector: "#search", Value: "Tony [Bennett"},
+			wantErrors: 0,
+		},
+		{
+			name:       "Input without selector",
+			action:     TaskAction{Id: "1", Type: WriteInput, Value: "Tony [Bennett"},
+			wantErrors: 1,
+		},
+		{
+			name:       "Invalid selector",
+			action:     TaskAction{Id: "1", Type: Click, Value: "div["},
+			wantErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if errs := tt.action.validate(); len(errs) != tt.wantErrors {
+				t.Errorf("TaskAction.validate() errors = %v, want %d", errs, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Baseline is the expected appearance of the element captured by an action of
+// a task, the later captures of the action are compared with it. The first
+// capture of the action is its baseline until another one is approved.
+type Baseline struct {
+	TaskId     string     `json:"task_id"`
+	ActionId   string     `json:"action_id"`
+	MediaId    string     `json:"media_id"`
+	PHash      string     `json:"phash"`
+	Sha256     string     `json:"sha256"`
+	MediaUrl   string     `json:"media_url"`
+	ApprovedAt *time.Time `json:"approved_at,omitempty"`
+}
+
+// NewBaseline makes the media the baseline of the action that captured it.
+func NewBaseline(media *Media) (*Baseline, error) {
+	if media.TaskId == "" || media.ActionId == "" {
+		return nil, errors.New("media was not captured by a task action")
+	}
+
+	phash := FindHash(media.Hashes, PerceptionHash)
+	if phash == "" {
+		return nil, errors.New("media has no perception hash")
+	}
+
+	return &Baseline{
+		TaskId:   media.TaskId,
+		ActionId: media.ActionId,
+		MediaId:  media.Id,
+		PHash:    phash,
+		Sha256:   FindHash(media.Hashes, Sha256Hash),
+		MediaUrl: media.MediaUrl,
+	}, nil
+}
+
+// VisualDiff compares a capture with the baseline of its action. Distance is
+// the hamming distance between their perception hashes and DiffRatio the
+// share of pixels that changed, highlighted by the image at DiffUrl. The
+// capture regressed when the distance is over the configured threshold.
+// Error tells why the capture could not be compared, the diff is empty then.
+type VisualDiff struct {
+	BaselineMediaId string  `json:"baseline_media_id"`
+	Distance        int     `json:"distance"`
+	DiffRatio       float64 `json:"diff_ratio"`
+	DiffUrl         string  `json:"diff_url,omitempty"`
+	Regressed       bool    `json:"regressed"`
+	Error           string  `json:"error,omitempty"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "testing"
+
+func TestNewBaseline(t *testing.T) {
+	hashes := []MediaHash{
+		{Algorithm: PerceptionHash, Value: "8f28f6d738680f07"},
+		{Algorithm: Sha256Hash, Value: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+	}
+
+	tests := []struct {
+		name    string
+		media   Media
+		want    Baseline
+		wantErr bool
+	}{
+		{
+			name:  "Captured by an action",
+			media: Media{Id: "m1", TaskId: "t1", ActionId: "5", MediaUrl: "./media/media_e3b0.png", Hashes: hashes},
+			want: Baseline{
+				TaskId:   "t1",
+				ActionId: "5",
+				MediaId:  "m1",
+				PHash:    "8f28f6d738680f07",
+				Sha256:   "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+				MediaUrl: "./media/media_e3b0.png",
+			},
+		},
+		{
+			name:    "Without action",
+			media:   Media{Id: "m1", TaskId: "t1", Hashes: hashes},
+			wantErr: true,
+		},
+		{
+			name:    "Without perception hash",
+			media:   Media{Id: "m1", TaskId: "t1", ActionId: "5", Hashes: hashes[1:]},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewBaseline(&tt.media)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewBaseline() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && *got != tt.want {
+				t.Errorf("NewBaseline() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	LightColorScheme = "light"
+	DarkColorScheme  = "dark"
+)
+
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// DevicePresets are the devices the browsers emulate, by their name.
+var DevicePresets = []string{
+	"iPhone 4",
+	"iPhone 5/SE",
+	"iPhone 6/7/8",
+	"iPhone 6/7/8 Plus",
+	"iPhone X",
+	"Nexus 5",
+	"Nexus 5X",
+	"Nexus 6P",
+	"Pixel 2",
+	"Pixel 2 XL",
+	"Galaxy S III",
+	"Galaxy S5",
+	"Galaxy Note 3",
+	"Galaxy Fold",
+	"Moto G4",
+	"Surface Duo",
+	"iPad Mini",
+	"iPad",
+	"iPad Pro",
+	"Nexus 7",
+	"Nexus 10",
+	"Kindle Fire HDX",
+	"Laptop with touch",
+	"Laptop with HiDPI screen",
+	"Laptop with MDPI screen",
+}
+
+type Viewport struct {
+	Width             int     `json:"width"`
+	Height            int     `json:"height"`
+	DeviceScaleFactor float64 `json:"device_scale_factor,omitempty"`
+	Mobile            bool    `json:"mobile,omitempty"`
+}
+
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  float64 `json:"accuracy,omitempty"`
+}
+
+// BrowserConfig emulates the browser context the task is run in. Device is one
+// of the DevicePresets (e.g. "iPhone X"); Viewport and UserAgent override the
+// ones of the preset when both are set.
+type BrowserConfig struct {
+	Device      string       `json:"device,omitempty"`
+	Landscape   bool         `json:"landscape,omitempty"`
+	Viewport    *Viewport    `json:"viewport,omitempty"`
+	UserAgent   string       `json:"user_agent,omitempty"`
+	Locale      string       `json:"locale,omitempty"`
+	Timezone    string       `json:"timezone,omitempty"`
+	Geolocation *Geolocation `json:"geolocation,omitempty"`
+	ColorScheme string       `json:"color_scheme,omitempty"`
+}
+
+// AcceptLanguage builds the Accept-Language header for the locale, falling
+// back to its base language, e.g. es-AR,es;q=0.9.
+func (b *BrowserConfig) AcceptLanguage() string {
+	if b.Locale == "" {
+		return ""
+	}
+
+	base, _, found := strings.Cut(b.Locale, "-")
+	if !found {
+		return b.Locale
+	}
+
+	return fmt.Sprintf("%s,%s;q=0.9", b.Locale, base)
+}
+
+func (b *BrowserConfig) Validate() error {
+	var errs []error
+
+	if b.Landscape && b.Device == "" {
+		errs = append(errs, errors.New("landscape requires a device"))
+	}
+
+	if b.Device != "" && !slices.Contains(DevicePresets, b.Device) {
+		errs = append(errs, fmt.Errorf("unknown device %q", b.Device))
+	}
+
+	if b.Viewport != nil {
+		if b.Viewport.Width <= 0 || b.Viewport.Height <= 0 {
+			errs = append(errs, fmt.Errorf("invalid viewport %dx%d", b.Viewport.Width, b.Viewport.Height))
+		}
+		if b.Viewport.DeviceScaleFactor < 0 {
+			errs = append(errs, fmt.Errorf("invalid device scale factor %v", b.Viewport.DeviceScaleFactor))
+		}
+	}
+
+	if b.Locale != "" && !localePattern.MatchString(b.Locale) {
+		errs = append(errs, fmt.Errorf("invalid locale %q", b.Locale))
+	}
+
+	if b.Timezone != "" {
+		if _, err := time.LoadLocation(b.Timezone); err != nil {
+			errs = append(errs, fmt.Errorf("invalid timezone %q: %w", b.Timezone, err))
+		}
+	}
+
+	if b.Geolocation != nil {
+		if b.Geolocation.Latitude < -90 || b.Geolocation.Latitude > 90 {
+			errs = append(errs, fmt.Errorf("invalid latitude %v", b.Geolocation.Latitude))
+		}
+		if b.Geolocation.Longitude < -180 || b.Geolocation.Longitude > 180 {
+			errs = append(errs, fmt.Errorf("invalid longitude %v", b.Geolocation.Longitude))
+		}
+		if b.Geolocation.Accuracy < 0 {
+			errs = append(errs, fmt.Errorf("invalid accuracy %v", b.Geolocation.Accuracy))
+		}
+	}
+
+	if b.ColorScheme != "" && b.ColorScheme != LightColorScheme && b.ColorScheme != DarkColorScheme {
+		errs = append(errs, fmt.Errorf("invalid color scheme %q", b.ColorScheme))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,99 @@
+package models
+
+import "testing"
+
+func TestBrowserConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  BrowserConfig
+		wantErr bool
+	}{
+		{
+			name: "Valid config",
+			config: BrowserConfig{
+				Device:      "iPhone X",
+				Landscape:   true,
+				UserAgent:   "Mozilla/5.0",
+				Locale:      "es-AR",
+				Timezone:    "America/Argentina/Buenos_Aires",
+				Geolocation: &Geolocation{Latitude: -34.6037, Longitude: -58.3816, Accuracy: 10},
+				ColorScheme: DarkColorScheme,
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Valid viewport",
+			config:  BrowserConfig{Viewport: &Viewport{Width: 1280, Height: 720, DeviceScaleFactor: 2}},
+			wantErr: false,
+		},
+		{
+			name:    "Empty config",
+			config:  BrowserConfig{},
+			wantErr: false,
+		},
+		{
+			name:    "Landscape without device",
+			config:  BrowserConfig{Landscape: true},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown device",
+			config:  BrowserConfig{Device: "Nokia 3310"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid viewport",
+			config:  BrowserConfig{Viewport: &Viewport{Width: 0, Height: 720}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid locale",
+			config:  BrowserConfig{Locale: "spanish_argentina"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid timezone",
+			config:  BrowserConfig{Timezone: "Mars/Olympus_Mons"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid latitude",
+			config:  BrowserConfig{Geolocation: &Geolocation{Latitude: 91, Longitude: 0}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid color scheme",
+			config:  BrowserConfig{ColorScheme: "sepia"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("BrowserConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBrowserConfig_AcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		want   string
+	}{
+		{name: "Without locale", locale: "", want: ""},
+		{name: "Language", locale: "es", want: "es"},
+		{name: "Language and region", locale: "es-AR", want: "es-AR,es;q=0.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := BrowserConfig{Locale: tt.locale}
+			if got := config.AcceptLanguage(); got != tt.want {
+				t.Errorf("BrowserConfig.AcceptLanguage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+type ContentKind string
+
+const (
+	CaptureContent ContentKind = "capture"
+	TextContent    ContentKind = "text"
+)
+
+// ContentSnapshot is what an action of a task got in a run: the perception
+// hash of the element it captured or the text it extracted. MediaId is the
+// media of the capture.
+type ContentSnapshot struct {
+	TaskId   string      `json:"task_id"`
+	ActionId string      `json:"action_id"`
+	RunId    string      `json:"run_id"`
+	Kind     ContentKind `json:"kind"`
+	Value    string      `json:"value"`
+	MediaId  string      `json:"media_id,omitempty"`
+}
+
+// ContentChange is a content of a task action that differs from the one of
+// the previous run. Distance is the hamming distance between the perception
+// hashes of the captures, texts have none.
+type ContentChange struct {
+	Id              string      `json:"id"`
+	TaskId          string      `json:"task_id"`
+	ActionId        string      `json:"action_id"`
+	RunId           string      `json:"run_id"`
+	PreviousRunId   string      `json:"previous_run_id"`
+	Kind            ContentKind `json:"kind"`
+	Previous        string      `json:"previous"`
+	Current         string      `json:"current"`
+	MediaId         string      `json:"media_id,omitempty"`
+	PreviousMediaId string      `json:"previous_media_id,omitempty"`
+	Distance        int         `json:"distance,omitempty"`
+	DetectedAt      time.Time   `json:"detected_at"`
+}
+
+// ChangedFrom compares the snapshot with the previous one of the action,
+// returning nil when the content is the same. Captures changed when their
+// perception hashes differ, so the noise of the screenshots is ignored.
+func (s *ContentSnapshot) ChangedFrom(previous *ContentSnapshot) (*ContentChange, error) {
+	change := &ContentChange{
+		TaskId:          s.TaskId,
+		ActionId:        s.ActionId,
+		RunId:           s.RunId,
+		PreviousRunId:   previous.RunId,
+		Kind:            s.Kind,
+		Previous:        previous.Value,
+		Current:         s.Value,
+		MediaId:         s.MediaId,
+		PreviousMediaId: previous.MediaId,
+	}
+
+	if s.Kind != previous.Kind {
+		return change, nil
+	}
+	if s.Kind == CaptureContent {
+		distance, err := HashDistance(previous.Value, s.Value)
+		if err != nil {
+			return nil, err
+		}
+		if distance == 0 {
+			return nil, nil
+		}
+		change.Distance = distance
+
+		return change, nil
+	}
+	if s.Value == previous.Value {
+		return nil, nil
+	}
+
+	return change, nil
+}
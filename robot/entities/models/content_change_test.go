@@ -0,0 +1,65 @@
+package models
+
+import "testing"
+
+func TestContentSnapshot_ChangedFrom(t *testing.T) {
+	tests := []struct {
+		name         string
+		snapshot     ContentSnapshot
+		previous     ContentSnapshot
+		wantChange   bool
+		wantDistance int
+		wantErr      bool
+	}{
+		{
+			name:     "Same capture",
+			snapshot: ContentSnapshot{Kind: CaptureContent, Value: "8f28f6d738680f07"},
+			previous: ContentSnapshot{Kind: CaptureContent, Value: "8f28f6d738680f07"},
+		},
+		{
+			name:         "Changed capture",
+			snapshot:     ContentSnapshot{Kind: CaptureContent, Value: "8f28f6d738680f00"},
+			previous:     ContentSnapshot{Kind: CaptureContent, Value: "8f28f6d738680f07"},
+			wantChange:   true,
+			wantDistance: 3,
+		},
+		{
+			name:     "Same text",
+			snapshot: ContentSnapshot{Kind: TextContent, Value: "Tony Bennett"},
+			previous: ContentSnapshot{Kind: TextContent, Value: "Tony Bennett"},
+		},
+		{
+			name:       "Changed text",
+			snapshot:   ContentSnapshot{Kind: TextContent, Value: "Frank Sinatra"},
+			previous:   ContentSnapshot{Kind: TextContent, Value: "Tony Bennett"},
+			wantChange: true,
+		},
+		{
+			name:       "Changed kind",
+			snapshot:   ContentSnapshot{Kind: TextContent, Value: "8f28f6d738680f07"},
+			previous:   ContentSnapshot{Kind: CaptureContent, Value: "8f28f6d738680f07"},
+			wantChange: true,
+		},
+		{
+			name:     "Invalid hash",
+			snapshot: ContentSnapshot{Kind: CaptureContent, Value: "p:8f28f6d738680f07"},
+			previous: ContentSnapshot{Kind: CaptureContent, Value: "8f28f6d738680f07"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.snapshot.ChangedFrom(&tt.previous)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ChangedFrom() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got != nil) != tt.wantChange {
+				t.Fatalf("ChangedFrom() = %+v, want change %v", got, tt.wantChange)
+			}
+			if got != nil && (got.Distance != tt.wantDistance || got.Previous != tt.previous.Value || got.Current != tt.snapshot.Value) {
+				t.Errorf("ChangedFrom() = %+v, want distance %d from %q to %q", got, tt.wantDistance, tt.previous.Value, tt.snapshot.Value)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"fmt"
+	"github.com/robfig/cron/v3"
+	"strings"
+)
+
+// cronStarBit is the bit robfig/cron sets on the unrestricted fields of a
+// spec, it does not set it on stepped ones like */2.
+const cronStarBit = 1 << 63
+
+// ParseCron parses a standard five fields cron expression (minute, hour, day
+// of month, month and day of week) or a macro like @daily. Days of month and
+// of week match either of them when both are restricted, as vixie cron does:
+// a field starting with * is not restricted, even with a step. The next time
+// of expressions that never match (e.g. February 30) is zero.
+func ParseCron(expr string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+
+	fields := strings.Fields(expr)
+	if spec, ok := schedule.(*cron.SpecSchedule); ok && len(fields) == 5 {
+		if strings.HasPrefix(fields[2], "*") {
+			spec.Dom |= cronStarBit
+		}
+		if strings.HasPrefix(fields[4], "*") {
+			spec.Dow |= cronStarBit
+		}
+	}
+
+	return schedule, nil
+}
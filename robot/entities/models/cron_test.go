@@ -0,0 +1,121 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "Every minute", expr: "* * * * *"},
+		{name: "Lists, ranges and steps", expr: "0,30 8-18/2 1-15 */3 1-5"},
+		{name: "Names", expr: "0 8 * jan-jun mon,wed,fri"},
+		{name: "Sunday as 7 is out of range", expr: "0 0 * * 7", wantErr: true},
+		{name: "Macro", expr: "@daily"},
+		{name: "Missing fields", expr: "0 8 * *", wantErr: true},
+		{name: "Out of range", expr: "60 * * * *", wantErr: true},
+		{name: "Reversed range", expr: "* 18-8 * * *", wantErr: true},
+		{name: "Invalid step", expr: "*/0 * * * *", wantErr: true},
+		{name: "Invalid name", expr: "0 0 * * someday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCron(tt.expr); (err != nil) != tt.wantErr {
+				t.Errorf("ParseCron() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseCron_Next(t *testing.T) {
+	after := time.Date(2026, time.October, 19, 10, 15, 30, 0, time.UTC)
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skip("timezone database not available")
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "Every minute",
+			expr:  "* * * * *",
+			after: after,
+			want:  time.Date(2026, time.October, 19, 10, 16, 0, 0, time.UTC),
+		},
+		{
+			name:  "Every quarter",
+			expr:  "*/15 * * * *",
+			after: after,
+			want:  time.Date(2026, time.October, 19, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "Next day",
+			expr:  "0 8 * * *",
+			after: after,
+			want:  time.Date(2026, time.October, 20, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Weekdays skip the weekend",
+			expr:  "0 8 * * mon-fri",
+			after: time.Date(2026, time.October, 23, 9, 0, 0, 0, time.UTC),
+			want:  time.Date(2026, time.October, 26, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Day of month or day of week",
+			expr:  "0 0 1 * sun",
+			after: after,
+			want:  time.Date(2026, time.October, 25, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Next year",
+			expr:  "@yearly",
+			after: after,
+			want:  time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Stepped day of month and day of week",
+			expr:  "0 0 */2 * mon",
+			after: after,
+			want:  time.Date(2026, time.November, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Leap day",
+			expr:  "0 0 29 feb *",
+			after: after,
+			want:  time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "Never",
+			expr:  "0 0 30 feb *",
+			after: after,
+			want:  time.Time{},
+		},
+		{
+			name:  "Skipped by daylight saving",
+			expr:  "30 2 * * *",
+			after: time.Date(2026, time.March, 28, 12, 0, 0, 0, madrid),
+			want:  time.Date(2026, time.March, 30, 2, 30, 0, 0, madrid),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron() error = %v", err)
+			}
+			if got := cron.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("cron.Schedule.Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
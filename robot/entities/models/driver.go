@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Drivers run the tasks. The browser driver renders the pages, the http driver
+// only fetches their HTML, saving browser capacity for the tasks that just
+// download resources or extract from static pages.
+const (
+	BrowserDriver = "browser"
+	HttpDriver    = "http"
+)
+
+// validateStatic checks that the http driver can run the task: only static
+// actions and none of the settings that need a browser.
+func (t *Task) validateStatic() []error {
+	var errs []error
+
+	if t.Browser != nil {
+		errs = append(errs, fmt.Errorf("browser config is not supported by the %s driver", HttpDriver))
+	}
+	if t.Profile != "" {
+		errs = append(errs, fmt.Errorf("profile is not supported by the %s driver", HttpDriver))
+	}
+	if t.Session != nil {
+		errs = append(errs, fmt.Errorf("session is not supported by the %s driver", HttpDriver))
+	}
+	if len(t.Network) > 0 {
+		errs = append(errs, fmt.Errorf("network rules are not supported by the %s driver", HttpDriver))
+	}
+	if t.RecordHar {
+		errs = append(errs, fmt.Errorf("har recording is not supported by the %s driver", HttpDriver))
+	}
+	if t.Video != nil {
+		errs = append(errs, fmt.Errorf("video recording is not supported by the %s driver", HttpDriver))
+	}
+
+	errs = append(errs, validateStaticActions("action", t.Actions)...)
+
+	return errs
+}
+
+// ValidateStrategies checks that the driver of the task can run the actions
+// of its resolved strategies, so tasks fail validation instead of halfway
+// through their run.
+func (t *Task) ValidateStrategies(strategies []*Strategy) error {
+	if t.DriverName() != HttpDriver {
+		return nil
+	}
+
+	var errs []error
+	for _, strategy := range strategies {
+		errs = append(errs, validateStaticActions("strategy "+strategy.Id+" pre action", strategy.PreActions)...)
+		errs = append(errs, validateStaticActions("strategy "+strategy.Id+" post action", strategy.PostActions)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateStaticActions(label string, actions []TaskAction) []error {
+	var errs []error
+
+	for i, action := range actions {
+		if spec, ok := action.Type.Spec(); ok && !spec.Static {
+			errs = append(errs, fmt.Errorf("%s %d (%s): not supported by the %s driver", label, i, action.Type.String(), HttpDriver))
+		}
+		if action.WaitFor != nil {
+			errs = append(errs, fmt.Errorf("%s %d (%s): wait conditions are not supported by the %s driver", label, i, action.Type.String(), HttpDriver))
+		}
+	}
+
+	return errs
+}
@@ -3,20 +3,31 @@ package models
 import "time"
 
 type Media struct {
-	Id            string                 `json:"id"`
-	Attributes    map[string]interface{} `json:"attributes"`
-	Height        float64                `json:"height"`
-	Width         float64                `json:"width"`
-	X             float64                `json:"x"`
-	Y             float64                `json:"y"`
-	Url           string                 `json:"url"`
-	PHash         string                 `json:"phash"`
-	Filename      string                 `json:"filename"`
-	MediaUrl      string                 `json:"media_url"`
-	ScreenshotUrl string                 `json:"screenshot_url"`
-	ResourceUrl   string                 `json:"resource_url"`
-	TaskId        string                 `json:"task_id"`
-	CreatedAt     time.Time              `json:"created_at"`
-	UpdatedAt     time.Time              `json:"updated_at"`
-	DeletedAt     *time.Time             `json:"deleted_at"`
+	Id         string                 `json:"id"`
+	Attributes map[string]interface{} `json:"attributes"`
+	Height     float64                `json:"height"`
+	Width      float64                `json:"width"`
+	X          float64                `json:"x"`
+	Y          float64                `json:"y"`
+	Url        string                 `json:"url"`
+	PHash      string                 `json:"phash"`
+	// ResourceHashes are the hashes of the downloaded resource, one per frame
+	// of animated images and per keyframe of videos, in their order.
+	ResourceHashes []MediaHash `json:"resource_hashes,omitempty"`
+	// Hashes are the fingerprints of the media by every algorithm, PHash keeps
+	// the perception hash in the format the medias were first searched by.
+	Hashes        []MediaHash `json:"hashes,omitempty"`
+	Filename      string      `json:"filename"`
+	MediaUrl      string      `json:"media_url"`
+	ScreenshotUrl string      `json:"screenshot_url"`
+	ResourceUrl   string      `json:"resource_url"`
+	TaskId        string      `json:"task_id"`
+	ActionId      string      `json:"action_id,omitempty"`
+	RunId         string      `json:"run_id"`
+	// VisualDiff compares the media with the baseline of its action, it is
+	// nil for the first capture of the action.
+	VisualDiff *VisualDiff `json:"visual_diff,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+	DeletedAt  *time.Time  `json:"deleted_at"`
 }
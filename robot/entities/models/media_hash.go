@@ -0,0 +1,108 @@
+package models
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+type HashAlgorithm string
+
+const (
+	PerceptionHash HashAlgorithm = "phash"
+	DifferenceHash HashAlgorithm = "dhash"
+	AverageHash    HashAlgorithm = "ahash"
+	WaveletHash    HashAlgorithm = "whash"
+	Sha256Hash     HashAlgorithm = "sha256"
+)
+
+// HashAlgorithms are the algorithms every media is hashed with.
+var HashAlgorithms = []HashAlgorithm{PerceptionHash, DifferenceHash, AverageHash, WaveletHash, Sha256Hash}
+
+// Perceptual tells the algorithms whose hashes are compared by their hamming
+// distance, the hashes of similar images differ in a few bits. Sha256 hashes
+// only match the exact same bytes.
+func (a HashAlgorithm) Perceptual() bool {
+	return a != Sha256Hash
+}
+
+// hashSize is the size in bytes of the hashes of the algorithm.
+func (a HashAlgorithm) hashSize() int {
+	if a == Sha256Hash {
+		return 32
+	}
+
+	return 8
+}
+
+func ParseHashAlgorithm(name string) (HashAlgorithm, error) {
+	for _, algorithm := range HashAlgorithms {
+		if string(algorithm) == strings.ToLower(name) {
+			return algorithm, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown hash algorithm %q", name)
+}
+
+// MediaHash is a fingerprint of the captured media. The value is the hash in
+// hexadecimal, without the kind prefix of the phash of the media.
+type MediaHash struct {
+	Algorithm HashAlgorithm `json:"algorithm"`
+	Value     string        `json:"value"`
+}
+
+// ParseMediaHash validates a hash of the algorithm given in hexadecimal.
+func ParseMediaHash(algorithm string, value string) (MediaHash, error) {
+	hashAlgorithm, err := ParseHashAlgorithm(algorithm)
+	if err != nil {
+		return MediaHash{}, err
+	}
+
+	value = strings.ToLower(strings.TrimSpace(value))
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		return MediaHash{}, fmt.Errorf("error decoding %s hash: %w", hashAlgorithm, err)
+	}
+	if len(decoded) != hashAlgorithm.hashSize() {
+		return MediaHash{}, fmt.Errorf("%s hashes have %d bytes, got %d", hashAlgorithm, hashAlgorithm.hashSize(), len(decoded))
+	}
+
+	return MediaHash{Algorithm: hashAlgorithm, Value: value}, nil
+}
+
+// FindHash returns the value of the hash of the algorithm, empty when the
+// media was not hashed with it.
+func FindHash(hashes []MediaHash, algorithm HashAlgorithm) string {
+	for _, hash := range hashes {
+		if hash.Algorithm == algorithm {
+			return hash.Value
+		}
+	}
+
+	return ""
+}
+
+// HashDistance is the hamming distance between two hashes of the same
+// algorithm given in hexadecimal.
+func HashDistance(a string, b string) (int, error) {
+	first, err := hex.DecodeString(a)
+	if err != nil {
+		return 0, fmt.Errorf("error decoding hash: %w", err)
+	}
+	second, err := hex.DecodeString(b)
+	if err != nil {
+		return 0, fmt.Errorf("error decoding hash: %w", err)
+	}
+	if len(first) != len(second) {
+		return 0, fmt.Errorf("hashes of %d and %d bytes can't be compared", len(first), len(second))
+	}
+
+	distance := 0
+	for i := range first {
+		distance += bits.OnesCount8(first[i] ^ second[i])
+	}
+
+	return distance, nil
+}
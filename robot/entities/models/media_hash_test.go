@@ -0,0 +1,68 @@
+package models
+
+import "testing"
+
+func TestParseMediaHash(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		value     string
+		want      MediaHash
+		wantErr   bool
+	}{
+		{
+			name:      "Perceptual hash",
+			algorithm: "dhash",
+			value:     "8F28F6D738680F07",
+			want:      MediaHash{Algorithm: DifferenceHash, Value: "8f28f6d738680f07"},
+		},
+		{
+			name:      "Sha256 hash",
+			algorithm: "SHA256",
+			value:     "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			want:      MediaHash{Algorithm: Sha256Hash, Value: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		},
+		{name: "Unknown algorithm", algorithm: "md5", value: "8f28f6d738680f07", wantErr: true},
+		{name: "Not hexadecimal", algorithm: "phash", value: "p:8f28f6d738680f07", wantErr: true},
+		{name: "Wrong size", algorithm: "sha256", value: "8f28f6d738680f07", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMediaHash(tt.algorithm, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMediaHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMediaHash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashDistance(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       string
+		b       string
+		want    int
+		wantErr bool
+	}{
+		{name: "Same hash", a: "8f28f6d738680f07", b: "8f28f6d738680f07", want: 0},
+		{name: "Different bits", a: "8f28f6d738680f07", b: "8f28f6d738680f00", want: 3},
+		{name: "Different sizes", a: "8f28f6d738680f07", b: "8f28", wantErr: true},
+		{name: "Not hexadecimal", a: "p:8f28f6d738680f07", b: "8f28f6d738680f07", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HashDistance(tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HashDistance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("HashDistance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
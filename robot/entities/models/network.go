@@ -0,0 +1,106 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// Actions of the network rules.
+const (
+	BlockRequest = "block"
+	// AllowRequest lets the request through untouched, so tasks can exempt
+	// urls from the rules that follow, like the default blocklist.
+	AllowRequest    = "allow"
+	OverrideHeaders = "headers"
+	MockResponse    = "mock"
+)
+
+// resourceTypes are the types of resource a browser requests, as the DevTools
+// protocol names them.
+var resourceTypes = map[string]bool{
+	"Document": true, "Stylesheet": true, "Image": true, "Media": true, "Font": true, "Script": true,
+	"TextTrack": true, "XHR": true, "Fetch": true, "Prefetch": true, "EventSource": true, "WebSocket": true,
+	"Manifest": true, "SignedExchange": true, "Ping": true, "CSPViolationReport": true, "Preflight": true,
+	"Other": true,
+}
+
+// NetworkMock is the response given to the requests matching a mock rule,
+// with the body inline or read from a fixture file. Fixture is a path relative
+// to the fixtures directory of the robot.
+type NetworkMock struct {
+	Status  int               `json:"status,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Fixture string            `json:"fixture,omitempty"`
+}
+
+// NetworkRule intercepts the requests of a task whose url matches Url, a
+// pattern where * matches any characters and ? a single one, and, when set,
+// whose resource type is ResourceType. The first rule matching a request is
+// the one applied.
+type NetworkRule struct {
+	Url          string            `json:"url"`
+	ResourceType string            `json:"resource_type,omitempty"`
+	Action       string            `json:"action"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Mock         *NetworkMock      `json:"mock,omitempty"`
+}
+
+// validateFixture checks that the fixture is a path relative to the fixtures
+// directory, without going up from it.
+func validateFixture(fixture string) error {
+	if fixture == "" {
+		return nil
+	}
+	if filepath.IsAbs(fixture) || strings.HasPrefix(fixture, "/") || strings.HasPrefix(fixture, "\\") {
+		return fmt.Errorf("mock fixture %q must be relative to the fixtures dir", fixture)
+	}
+	for _, part := range strings.FieldsFunc(fixture, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if part == ".." {
+			return fmt.Errorf("mock fixture %q can't go up from the fixtures dir", fixture)
+		}
+	}
+
+	return nil
+}
+
+func (nr *NetworkRule) Validate() error {
+	var errs []error
+
+	if strings.TrimSpace(nr.Url) == "" {
+		errs = append(errs, errors.New("url pattern is required"))
+	}
+
+	if nr.ResourceType != "" && !resourceTypes[nr.ResourceType] {
+		errs = append(errs, fmt.Errorf("invalid resource type %q", nr.ResourceType))
+	}
+
+	switch nr.Action {
+	case BlockRequest, AllowRequest:
+	case OverrideHeaders:
+		if len(nr.Headers) == 0 {
+			errs = append(errs, errors.New("headers are required"))
+		}
+	case MockResponse:
+		if nr.Mock == nil {
+			errs = append(errs, errors.New("mock is required"))
+			break
+		}
+		if nr.Mock.Status != 0 && http.StatusText(nr.Mock.Status) == "" {
+			errs = append(errs, fmt.Errorf("invalid mock status %d", nr.Mock.Status))
+		}
+		if nr.Mock.Body != "" && nr.Mock.Fixture != "" {
+			errs = append(errs, errors.New("mock must have either a body or a fixture"))
+		}
+		if err := validateFixture(nr.Mock.Fixture); err != nil {
+			errs = append(errs, err)
+		}
+	default:
+		errs = append(errs, fmt.Errorf("invalid network action %q", nr.Action))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,89 @@
+package models
+
+import "testing"
+
+func TestNetworkRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    NetworkRule
+		wantErr bool
+	}{
+		{
+			name:    "Block by resource type",
+			rule:    NetworkRule{Url: "*", ResourceType: "Font", Action: BlockRequest},
+			wantErr: false,
+		},
+		{
+			name:    "Allow url",
+			rule:    NetworkRule{Url: "*://*.wikimedia.org/*", Action: AllowRequest},
+			wantErr: false,
+		},
+		{
+			name:    "Override headers",
+			rule:    NetworkRule{Url: "*://en.wikipedia.org/*", Action: OverrideHeaders, Headers: map[string]string{"Accept-Language": "es"}},
+			wantErr: false,
+		},
+		{
+			name: "Mock response",
+			rule: NetworkRule{
+				Url:    "*/api/rest_v1/*",
+				Action: MockResponse,
+				Mock:   &NetworkMock{Status: 200, Headers: map[string]string{"Content-Type": "application/json"}, Fixture: "summary.json"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Without url",
+			rule:    NetworkRule{Action: BlockRequest},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid resource type",
+			rule:    NetworkRule{Url: "*", ResourceType: "font", Action: BlockRequest},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid action",
+			rule:    NetworkRule{Url: "*", Action: "drop"},
+			wantErr: true,
+		},
+		{
+			name:    "Headers without headers",
+			rule:    NetworkRule{Url: "*", Action: OverrideHeaders},
+			wantErr: true,
+		},
+		{
+			name:    "Mock without mock",
+			rule:    NetworkRule{Url: "*", Action: MockResponse},
+			wantErr: true,
+		},
+		{
+			name:    "Mock with invalid status",
+			rule:    NetworkRule{Url: "*", Action: MockResponse, Mock: &NetworkMock{Status: 999}},
+			wantErr: true,
+		},
+		{
+			name:    "Mock with body and fixture",
+			rule:    NetworkRule{Url: "*", Action: MockResponse, Mock: &NetworkMock{Body: "{}", Fixture: "summary.json"}},
+			wantErr: true,
+		},
+		{
+			name:    "Mock with absolute fixture",
+			rule:    NetworkRule{Url: "*", Action: MockResponse, Mock: &NetworkMock{Fixture: "/root/module/robot/.env"}},
+			wantErr: true,
+		},
+		{
+			name:    "Mock with fixture going up",
+			rule:    NetworkRule{Url: "*", Action: MockResponse, Mock: &NetworkMock{Fixture: "api/../../.env"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("NetworkRule.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
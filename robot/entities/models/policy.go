@@ -0,0 +1,42 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+type TaskSource string
+
+const (
+	FileSource  TaskSource = "file"
+	QueueSource TaskSource = "queue"
+)
+
+// ScriptPolicy is the allowlist of task sources trusted to run custom
+// scripts in the browser, with EvaluateScript or wait predicates.
+type ScriptPolicy struct {
+	AllowedSources map[TaskSource]bool
+}
+
+// NewScriptPolicy builds the policy from a comma separated list of sources.
+func NewScriptPolicy(allowedSources string) ScriptPolicy {
+	policy := ScriptPolicy{AllowedSources: make(map[TaskSource]bool)}
+	for _, source := range strings.Split(allowedSources, ",") {
+		source = strings.TrimSpace(source)
+		if source != "" {
+			policy.AllowedSources[TaskSource(source)] = true
+		}
+	}
+
+	return policy
+}
+
+// Check receives the resolved strategies of the task, their actions run
+// along with the task ones.
+func (p ScriptPolicy) Check(task *Task, strategies []*Strategy, source TaskSource) error {
+	if task.RunsScripts(strategies) && !p.AllowedSources[source] {
+		return fmt.Errorf("scripts are not allowed for tasks from source %s", source)
+	}
+
+	return nil
+}
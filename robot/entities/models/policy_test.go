@@ -0,0 +1,115 @@
+package models
+
+import "testing"
+
+func TestScriptPolicy_Check(t *testing.T) {
+	taskWithScript := &Task{
+		Id:      "1",
+		Actions: []TaskAction{{Id: "1", Type: EvaluateScript, Value: "return document.title"}},
+	}
+	taskWithoutScript := &Task{
+		Id:      "2",
+		Actions: []TaskAction{{Id: "1", Type: Capture, Value: "#firstHeading"}},
+	}
+	taskWithLoginScript := &Task{
+		Id:      "3",
+		Actions: []TaskAction{{Id: "1", Type: Capture, Value: "#firstHeading"}},
+		Session: &TaskSession{
+			Name: "login",
+			LoginTask: &Task{
+				Id:      "login",
+				Actions: []TaskAction{{Id: "1", Type: EvaluateScript, Value: "return document.title"}},
+			},
+		},
+	}
+	taskWithPredicate := &Task{
+		Id: "4",
+		Actions: []TaskAction{{
+			Id:      "1",
+			Type:    Capture,
+			Value:   "#firstHeading",
+			WaitFor: &WaitCondition{Strategy: WaitPredicate, Predicate: "() => window.ready"},
+		}},
+	}
+	strategyWithScript := &Strategy{
+		Id:          "login",
+		PreActions:  []TaskAction{{Id: "1", Type: EvaluateScript, Value: "return document.title"}},
+		PostActions: []TaskAction{{Id: "2", Type: Click, Value: "#logout"}},
+	}
+
+	tests := []struct {
+		name           string
+		allowedSources string
+		task           *Task
+		strategies     []*Strategy
+		source         TaskSource
+		wantErr        bool
+	}{
+		{
+			name:           "Script from allowed source",
+			allowedSources: "file, queue",
+			task:           taskWithScript,
+			source:         QueueSource,
+			wantErr:        false,
+		},
+		{
+			name:           "Script from not allowed source",
+			allowedSources: "file",
+			task:           taskWithScript,
+			source:         QueueSource,
+			wantErr:        true,
+		},
+		{
+			name:           "Scripts disabled",
+			allowedSources: "",
+			task:           taskWithScript,
+			source:         FileSource,
+			wantErr:        true,
+		},
+		{
+			name:           "Task without script",
+			allowedSources: "",
+			task:           taskWithoutScript,
+			source:         QueueSource,
+			wantErr:        false,
+		},
+		{
+			name:           "Script in the login task",
+			allowedSources: "file",
+			task:           taskWithLoginScript,
+			source:         QueueSource,
+			wantErr:        true,
+		},
+		{
+			name:           "Script in a strategy",
+			allowedSources: "file",
+			task:           taskWithoutScript,
+			strategies:     []*Strategy{strategyWithScript},
+			source:         QueueSource,
+			wantErr:        true,
+		},
+		{
+			name:           "Wait predicate",
+			allowedSources: "file",
+			task:           taskWithPredicate,
+			source:         QueueSource,
+			wantErr:        true,
+		},
+		{
+			name:           "Wait predicate from allowed source",
+			allowedSources: "queue",
+			task:           taskWithPredicate,
+			source:         QueueSource,
+			wantErr:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewScriptPolicy(tt.allowedSources)
+			if err := policy.Check(tt.task, tt.strategies, tt.source); (err != nil) != tt.wantErr {
+				t.Errorf("ScriptPolicy.Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
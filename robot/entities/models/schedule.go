@@ -0,0 +1,209 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+)
+
+// MissedRunPolicy tells what to do with the runs of a schedule that were due
+// while no scheduler was running.
+type MissedRunPolicy string
+
+const (
+	// MissedRunsSkip drops the missed runs, the task waits for its next run.
+	MissedRunsSkip MissedRunPolicy = "skip"
+	// MissedRunsRunOnce runs the task once for all the missed runs.
+	MissedRunsRunOnce MissedRunPolicy = "run_once"
+	// MissedRunsRunAll runs the task for each missed run, up to maxMissedRuns.
+	MissedRunsRunAll MissedRunPolicy = "run_all"
+)
+
+const (
+	// MinScheduleInterval is the shortest interval between the runs of a
+	// schedule, the precision of the cron expressions.
+	MinScheduleInterval = time.Minute
+	// maxMissedRuns caps the runs caught up by MissedRunsRunAll, the latest
+	// ones are kept.
+	maxMissedRuns = 100
+)
+
+// Schedule runs a task on the times of a cron expression or every interval.
+// Each run is delayed by up to Jitter, so the tasks scheduled at the same time
+// don't hit the sites together. NextRunAt is the next time without jitter.
+type Schedule struct {
+	Id         string          `json:"id"`
+	Cron       string          `json:"cron,omitempty"`
+	Interval   string          `json:"interval,omitempty"`
+	Timezone   string          `json:"timezone,omitempty"`
+	Jitter     string          `json:"jitter,omitempty"`
+	MissedRuns MissedRunPolicy `json:"missed_runs,omitempty"`
+	Disabled   bool            `json:"disabled,omitempty"`
+	Task       Task            `json:"task"`
+	NextRunAt  time.Time       `json:"next_run_at,omitempty"`
+	LastRunAt  *time.Time      `json:"last_run_at,omitempty"`
+}
+
+// MissedRunsPolicy is the policy of the schedule, MissedRunsRunOnce unless set.
+func (s *Schedule) MissedRunsPolicy() MissedRunPolicy {
+	if s.MissedRuns == "" {
+		return MissedRunsRunOnce
+	}
+
+	return s.MissedRuns
+}
+
+func (s *Schedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", s.Timezone, err)
+	}
+
+	return loc, nil
+}
+
+func (s *Schedule) JitterDuration() time.Duration {
+	jitter, err := time.ParseDuration(s.Jitter)
+	if err != nil || jitter <= 0 {
+		return 0
+	}
+
+	return jitter
+}
+
+// JitterOffset is the delay of the run of the given time. It only depends on
+// the schedule and the time, so every scheduler delays a run the same.
+func (s *Schedule) JitterOffset(runAt time.Time) time.Duration {
+	jitter := s.JitterDuration()
+	if jitter == 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%s:%d", s.Id, runAt.Unix())
+
+	return time.Duration(h.Sum64() % uint64(jitter))
+}
+
+// Next returns the first run time of the schedule after the given time, zero
+// when the cron expression matches no date.
+func (s *Schedule) Next(after time.Time) (time.Time, error) {
+	if s.Cron == "" {
+		interval, err := time.ParseDuration(s.Interval)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid interval %q: %w", s.Interval, err)
+		}
+
+		return after.Add(interval), nil
+	}
+
+	cron, err := ParseCron(s.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc, err := s.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return cron.Next(after.In(loc)), nil
+}
+
+// DueRuns returns the run times of the schedule due at now, following its
+// missed run policy, and the next run time to wait for. A run is due once its
+// jitter elapsed, and missed when it was due for longer than tolerance.
+// Schedules without next run time wait for their first one.
+func (s *Schedule) DueRuns(now time.Time, tolerance time.Duration) ([]time.Time, time.Time, error) {
+	if s.NextRunAt.IsZero() {
+		next, err := s.Next(now)
+		return nil, next, err
+	}
+
+	var due, missed []time.Time
+	next := s.NextRunAt
+	for !next.IsZero() && !next.Add(s.JitterOffset(next)).After(now) {
+		if now.Sub(next.Add(s.JitterOffset(next))) > tolerance {
+			missed = append(missed, next)
+			if len(missed) > maxMissedRuns {
+				missed = missed[1:]
+			}
+		} else {
+			due = append(due, next)
+		}
+
+		var err error
+		if next, err = s.Next(next); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+
+	switch s.MissedRunsPolicy() {
+	case MissedRunsRunOnce:
+		if len(due) == 0 && len(missed) > 0 {
+			due = missed[len(missed)-1:]
+		}
+	case MissedRunsRunAll:
+		due = append(missed, due...)
+	}
+
+	return due, next, nil
+}
+
+func (s *Schedule) Validate() error {
+	var errs []error
+
+	if !namePattern.MatchString(s.Id) {
+		errs = append(errs, fmt.Errorf("invalid schedule id %q", s.Id))
+	}
+
+	switch {
+	case s.Cron == "" && s.Interval == "":
+		errs = append(errs, errors.New("schedule must have a cron expression or an interval"))
+	case s.Cron != "" && s.Interval != "":
+		errs = append(errs, errors.New("schedule can't have both a cron expression and an interval"))
+	case s.Cron != "":
+		if _, err := ParseCron(s.Cron); err != nil {
+			errs = append(errs, err)
+		} else if next, err := s.Next(time.Now()); err == nil && next.IsZero() {
+			errs = append(errs, fmt.Errorf("cron expression %q never runs", s.Cron))
+		}
+	default:
+		interval, err := time.ParseDuration(s.Interval)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid interval %q: %w", s.Interval, err))
+		} else if interval < MinScheduleInterval {
+			errs = append(errs, fmt.Errorf("interval must be at least %s, got %s", MinScheduleInterval, interval))
+		}
+	}
+
+	if _, err := s.location(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if strings.TrimSpace(s.Jitter) != "" {
+		jitter, err := time.ParseDuration(s.Jitter)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid jitter %q: %w", s.Jitter, err))
+		} else if jitter < 0 {
+			errs = append(errs, fmt.Errorf("jitter must be positive, got %s", jitter))
+		}
+	}
+
+	switch s.MissedRunsPolicy() {
+	case MissedRunsSkip, MissedRunsRunOnce, MissedRunsRunAll:
+	default:
+		errs = append(errs, fmt.Errorf("invalid missed runs policy %q", s.MissedRuns))
+	}
+
+	if err := s.Task.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("task: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
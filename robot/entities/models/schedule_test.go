@@ -0,0 +1,207 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule_JitterOffset(t *testing.T) {
+	runAt := time.Date(2026, time.October, 19, 8, 0, 0, 0, time.UTC)
+	schedule := Schedule{Id: "news", Cron: "0 8 * * *", Jitter: "5m"}
+
+	offset := schedule.JitterOffset(runAt)
+	if offset < 0 || offset >= 5*time.Minute {
+		t.Errorf("Schedule.JitterOffset() = %v, want between 0 and 5m", offset)
+	}
+	if again := schedule.JitterOffset(runAt); again != offset {
+		t.Errorf("Schedule.JitterOffset() = %v, then %v, want the same offset", offset, again)
+	}
+
+	schedule.Jitter = ""
+	if got := schedule.JitterOffset(runAt); got != 0 {
+		t.Errorf("Schedule.JitterOffset() without jitter = %v, want 0", got)
+	}
+}
+
+func TestSchedule_DueRuns(t *testing.T) {
+	at := func(hour int, minute int) time.Time {
+		return time.Date(2026, time.October, 19, hour, minute, 0, 0, time.UTC)
+	}
+	tolerance := time.Minute
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		now      time.Time
+		wantDue  []time.Time
+		wantNext time.Time
+	}{
+		{
+			name:     "First run",
+			schedule: Schedule{Id: "news", Cron: "0 * * * *"},
+			now:      at(8, 30),
+			wantNext: at(9, 0),
+		},
+		{
+			name:     "Not due",
+			schedule: Schedule{Id: "news", Cron: "0 * * * *", NextRunAt: at(9, 0)},
+			now:      at(8, 30),
+			wantNext: at(9, 0),
+		},
+		{
+			name:     "Due",
+			schedule: Schedule{Id: "news", Cron: "0 * * * *", NextRunAt: at(9, 0)},
+			now:      at(9, 0),
+			wantDue:  []time.Time{at(9, 0)},
+			wantNext: at(10, 0),
+		},
+		{
+			name:     "Interval",
+			schedule: Schedule{Id: "news", Interval: "20m", NextRunAt: at(9, 0)},
+			now:      at(9, 0),
+			wantDue:  []time.Time{at(9, 0)},
+			wantNext: at(9, 20),
+		},
+		{
+			name:     "Missed runs skipped",
+			schedule: Schedule{Id: "news", Cron: "0 * * * *", MissedRuns: MissedRunsSkip, NextRunAt: at(6, 0)},
+			now:      at(8, 30),
+			wantNext: at(9, 0),
+		},
+		{
+			name:     "Missed runs run once",
+			schedule: Schedule{Id: "news", Cron: "0 * * * *", NextRunAt: at(6, 0)},
+			now:      at(8, 30),
+			wantDue:  []time.Time{at(8, 0)},
+			wantNext: at(9, 0),
+		},
+		{
+			name:     "Missed runs run all",
+			schedule: Schedule{Id: "news", Cron: "0 * * * *", MissedRuns: MissedRunsRunAll, NextRunAt: at(6, 0)},
+			now:      at(8, 30),
+			wantDue:  []time.Time{at(6, 0), at(7, 0), at(8, 0)},
+			wantNext: at(9, 0),
+		},
+		{
+			name:     "Missed runs with one on time",
+			schedule: Schedule{Id: "news", Cron: "0 * * * *", MissedRuns: MissedRunsSkip, NextRunAt: at(6, 0)},
+			now:      at(9, 0),
+			wantDue:  []time.Time{at(9, 0)},
+			wantNext: at(10, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, next, err := tt.schedule.DueRuns(tt.now, tolerance)
+			if err != nil {
+				t.Fatalf("Schedule.DueRuns() error = %v", err)
+			}
+			if len(due) != len(tt.wantDue) {
+				t.Fatalf("Schedule.DueRuns() due = %v, want %v", due, tt.wantDue)
+			}
+			for i := range due {
+				if !due[i].Equal(tt.wantDue[i]) {
+					t.Errorf("Schedule.DueRuns() due = %v, want %v", due, tt.wantDue)
+				}
+			}
+			if !next.Equal(tt.wantNext) {
+				t.Errorf("Schedule.DueRuns() next = %v, want %v", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestSchedule_DueRunsWithJitter(t *testing.T) {
+	runAt := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+	schedule := Schedule{Id: "news", Cron: "0 * * * *", Jitter: "10m", NextRunAt: runAt}
+	offset := schedule.JitterOffset(runAt)
+
+	if due, _, _ := schedule.DueRuns(runAt.Add(offset-time.Second), time.Minute); len(due) != 0 {
+		t.Errorf("Schedule.DueRuns() before the jitter = %v, want none", due)
+	}
+	if due, _, _ := schedule.DueRuns(runAt.Add(offset), time.Minute); len(due) != 1 || !due[0].Equal(runAt) {
+		t.Errorf("Schedule.DueRuns() after the jitter = %v, want %v", due, runAt)
+	}
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	task := Task{
+		Id:      "news",
+		Url:     "https://en.wikipedia.org/wiki/Main_Page",
+		Actions: []TaskAction{{Id: "1", Type: Capture, Value: "#mp-welcome"}},
+	}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		wantErr  bool
+	}{
+		{
+			name:     "Cron",
+			schedule: Schedule{Id: "news", Cron: "0 8 * * mon-fri", Timezone: "UTC", Jitter: "5m", Task: task},
+		},
+		{
+			name:     "Interval",
+			schedule: Schedule{Id: "news", Interval: "30m", MissedRuns: MissedRunsSkip, Task: task},
+		},
+		{
+			name:     "Invalid id",
+			schedule: Schedule{Id: "daily news", Interval: "30m", Task: task},
+			wantErr:  true,
+		},
+		{
+			name:     "Without cron nor interval",
+			schedule: Schedule{Id: "news", Task: task},
+			wantErr:  true,
+		},
+		{
+			name:     "Both cron and interval",
+			schedule: Schedule{Id: "news", Cron: "@hourly", Interval: "30m", Task: task},
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid cron",
+			schedule: Schedule{Id: "news", Cron: "0 25 * * *", Task: task},
+			wantErr:  true,
+		},
+		{
+			name:     "Cron never running",
+			schedule: Schedule{Id: "news", Cron: "0 0 31 feb *", Task: task},
+			wantErr:  true,
+		},
+		{
+			name:     "Too short interval",
+			schedule: Schedule{Id: "news", Interval: "10s", Task: task},
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid timezone",
+			schedule: Schedule{Id: "news", Cron: "@daily", Timezone: "Mars/Olympus", Task: task},
+			wantErr:  true,
+		},
+		{
+			name:     "Negative jitter",
+			schedule: Schedule{Id: "news", Interval: "30m", Jitter: "-1m", Task: task},
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid missed runs policy",
+			schedule: Schedule{Id: "news", Interval: "30m", MissedRuns: "later", Task: task},
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid task",
+			schedule: Schedule{Id: "news", Interval: "30m", Task: Task{Id: "news"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.schedule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Schedule.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
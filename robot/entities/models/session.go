@@ -0,0 +1,95 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultSessionTTL is how long a saved session is considered fresh when the
+// task does not set its own ttl.
+const DefaultSessionTTL = 24 * time.Hour
+
+func ValidateSessionName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid session name %q", name)
+	}
+
+	return nil
+}
+
+type SessionCookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires,omitempty"`
+	HttpOnly bool    `json:"http_only,omitempty"`
+	Secure   bool    `json:"secure,omitempty"`
+	SameSite string  `json:"same_site,omitempty"`
+}
+
+// SessionStorage holds the localStorage and sessionStorage items of an origin.
+type SessionStorage struct {
+	Local   map[string]string `json:"local,omitempty"`
+	Session map[string]string `json:"session,omitempty"`
+}
+
+// Session is the browser state saved by a task to be restored by the next
+// runs, so they don't need to log in again.
+type Session struct {
+	Name      string                    `json:"name"`
+	Cookies   []SessionCookie           `json:"cookies"`
+	Storage   map[string]SessionStorage `json:"storage"`
+	SavedAt   time.Time                 `json:"saved_at"`
+	ExpiresAt time.Time                 `json:"expires_at"`
+}
+
+func (s *Session) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// TaskSession references the session a task runs with. It is restored before
+// navigating to the task url and, when it is missing or stale, LoginTask is
+// run first and its resulting state saved as the session.
+type TaskSession struct {
+	Name      string `json:"name"`
+	Ttl       string `json:"ttl,omitempty"`
+	LoginTask *Task  `json:"login_task,omitempty"`
+}
+
+func (ts *TaskSession) TtlDuration() time.Duration {
+	ttl, err := time.ParseDuration(ts.Ttl)
+	if err != nil || ttl <= 0 {
+		return DefaultSessionTTL
+	}
+
+	return ttl
+}
+
+func (ts *TaskSession) Validate() error {
+	var errs []error
+
+	if err := ValidateSessionName(ts.Name); err != nil {
+		errs = append(errs, err)
+	}
+
+	if strings.TrimSpace(ts.Ttl) != "" {
+		ttl, err := time.ParseDuration(ts.Ttl)
+		if err != nil || ttl <= 0 {
+			errs = append(errs, fmt.Errorf("ttl must be a positive duration, got %q", ts.Ttl))
+		}
+	}
+
+	if ts.LoginTask != nil {
+		if ts.LoginTask.Session != nil {
+			errs = append(errs, errors.New("login task must not have a session"))
+		}
+		if err := ts.LoginTask.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("login task: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSession_IsExpired(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      bool
+	}{
+		{name: "Fresh", expiresAt: now.Add(time.Hour), want: false},
+		{name: "Expiring now", expiresAt: now, want: true},
+		{name: "Expired", expiresAt: now.Add(-time.Hour), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := Session{Name: "login", ExpiresAt: tt.expiresAt}
+			if got := session.IsExpired(now); got != tt.want {
+				t.Errorf("Session.IsExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskSession_TtlDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  string
+		want time.Duration
+	}{
+		{name: "Without ttl", ttl: "", want: DefaultSessionTTL},
+		{name: "With ttl", ttl: "2h", want: 2 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := TaskSession{Name: "login", Ttl: tt.ttl}
+			if got := session.TtlDuration(); got != tt.want {
+				t.Errorf("TaskSession.TtlDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskSession_Validate(t *testing.T) {
+	loginTask := &Task{
+		Id:  "login",
+		Url: "https://en.wikipedia.org/wiki/Special:UserLogin",
+		Actions: []TaskAction{
+			{Id: "1", Type: WriteInput, Selector: "#wpName1", Value: "user"},
+			{Id: "2", Type: Click, Value: "#wpLoginAttempt"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		session TaskSession
+		wantErr bool
+	}{
+		{
+			name:    "Valid session",
+			session: TaskSession{Name: "wikipedia", Ttl: "12h", LoginTask: loginTask},
+			wantErr: false,
+		},
+		{
+			name:    "Invalid name",
+			session: TaskSession{Name: "wikipedia login"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid ttl",
+			session: TaskSession{Name: "wikipedia", Ttl: "-1h"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid login task",
+			session: TaskSession{Name: "wikipedia", LoginTask: &Task{Id: "login", Url: "wikipedia"}},
+			wantErr: true,
+		},
+		{
+			name: "Login task with session",
+			session: TaskSession{
+				Name: "wikipedia",
+				LoginTask: &Task{
+					Id:      "login",
+					Url:     "https://en.wikipedia.org/wiki/Special:UserLogin",
+					Session: &TaskSession{Name: "other"},
+					Actions: []TaskAction{{Id: "1", Type: Click, Value: "#wpLoginAttempt"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.session.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("TaskSession.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
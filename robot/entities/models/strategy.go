@@ -0,0 +1,107 @@
+package models
+
+import (
+	"automator-go/robot/entities/validation"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// secretNamePattern is the pattern of the secret names, the one of the
+// environment variables.
+var secretNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// Strategy wraps the tasks that reference it with actions run before (e.g.
+// log in) and after (e.g. log out) the task actions. PreActions run on Url,
+// or on the task url when it is empty, and then the task url is opened again.
+// PostActions run on the page the task actions left.
+type Strategy struct {
+	Id          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Site        string       `json:"site,omitempty"`
+	Url         string       `json:"url,omitempty"`
+	PreActions  []TaskAction `json:"pre_actions,omitempty"`
+	PostActions []TaskAction `json:"post_actions,omitempty"`
+}
+
+// AppliesTo reports whether the strategy may run for the url: strategies
+// without site apply to every url, otherwise the url host must be the site
+// or one of its subdomains.
+func (s *Strategy) AppliesTo(rawUrl string) bool {
+	if s.Site == "" {
+		return true
+	}
+
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(parsedUrl.Hostname())
+	site := strings.ToLower(s.Site)
+
+	return host == site || strings.HasSuffix(host, "."+site)
+}
+
+// WithSecrets returns a copy of the strategy whose actions write the values
+// of their secrets, resolved by resolve.
+func (s *Strategy) WithSecrets(resolve func(name string) (string, error)) (*Strategy, error) {
+	resolved := *s
+	var err error
+	if resolved.PreActions, err = actionsWithSecrets(s.PreActions, resolve); err != nil {
+		return nil, err
+	}
+	if resolved.PostActions, err = actionsWithSecrets(s.PostActions, resolve); err != nil {
+		return nil, err
+	}
+
+	return &resolved, nil
+}
+
+func actionsWithSecrets(actions []TaskAction, resolve func(name string) (string, error)) ([]TaskAction, error) {
+	if actions == nil {
+		return nil, nil
+	}
+
+	resolved := make([]TaskAction, len(actions))
+	for i, action := range actions {
+		var err error
+		if resolved[i], err = action.WithSecret(resolve); err != nil {
+			return nil, fmt.Errorf("action %s: %w", action.Id, err)
+		}
+	}
+
+	return resolved, nil
+}
+
+func (s *Strategy) Validate() error {
+	var errs []error
+
+	if !namePattern.MatchString(s.Id) {
+		errs = append(errs, fmt.Errorf("invalid strategy id %q", s.Id))
+	}
+
+	if strings.Contains(s.Site, "/") || strings.Contains(s.Site, ":") {
+		errs = append(errs, fmt.Errorf("site must be a host, got %q", s.Site))
+	}
+
+	if s.Url != "" {
+		if err := validation.ValidateUrl(s.Url); err != nil {
+			errs = append(errs, err)
+		} else if !s.AppliesTo(s.Url) {
+			errs = append(errs, fmt.Errorf("url %q is not on site %s", s.Url, s.Site))
+		}
+	}
+
+	if len(s.PreActions) == 0 && len(s.PostActions) == 0 {
+		errs = append(errs, errors.New("strategy must have at least one pre or post action"))
+	}
+
+	errs = append(errs, validateActions("pre action", s.PreActions, false)...)
+	errs = append(errs, validateActions("post action", s.PostActions, false)...)
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,126 @@
+package models
+
+import "testing"
+
+func TestStrategy_AppliesTo(t *testing.T) {
+	tests := []struct {
+		name string
+		site string
+		url  string
+		want bool
+	}{
+		{name: "Without site", site: "", url: "https://en.wikipedia.org", want: true},
+		{name: "Same host", site: "wikipedia.org", url: "https://wikipedia.org/wiki", want: true},
+		{name: "Subdomain", site: "wikipedia.org", url: "https://en.wikipedia.org/wiki", want: true},
+		{name: "Other site", site: "wikipedia.org", url: "https://notwikipedia.org", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := Strategy{Id: "login", Site: tt.site}
+			if got := strategy.AppliesTo(tt.url); got != tt.want {
+				t.Errorf("Strategy.AppliesTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrategy_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy Strategy
+		wantErr  bool
+	}{
+		{
+			name: "Valid strategy",
+			strategy: Strategy{
+				Id:   "wikipedia-login",
+				Site: "wikipedia.org",
+				Url:  "https://en.wikipedia.org/wiki/Special:UserLogin",
+				PreActions: []TaskAction{
+					{Id: "1", Type: WriteInput, Selector: "#wpName1", Value: "user"},
+					{Id: "2", Type: Click, Value: "#wpLoginAttempt"},
+				},
+				PostActions: []TaskAction{{Id: "1", Type: Click, Value: "#pt-logout a"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Invalid id",
+			strategy: Strategy{Id: "wikipedia login", PostActions: []TaskAction{{Id: "1", Type: Click, Value: "#logout"}}},
+			wantErr:  true,
+		},
+		{
+			name:     "Without actions",
+			strategy: Strategy{Id: "wikipedia-login"},
+			wantErr:  true,
+		},
+		{
+			name: "Url on another site",
+			strategy: Strategy{
+				Id:         "wikipedia-login",
+				Site:       "wikipedia.org",
+				Url:        "https://example.com/login",
+				PreActions: []TaskAction{{Id: "1", Type: Click, Value: "#login"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid site",
+			strategy: Strategy{
+				Id:         "wikipedia-login",
+				Site:       "https://wikipedia.org",
+				PreActions: []TaskAction{{Id: "1", Type: Click, Value: "#login"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid pre action",
+			strategy: Strategy{
+				Id:         "wikipedia-login",
+				PreActions: []TaskAction{{Id: "1", Type: WriteInput, Value: "user"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Secret input",
+			strategy: Strategy{
+				Id:         "wikipedia-login",
+				PreActions: []TaskAction{{Id: "1", Type: WriteInput, Selector: "#wpPassword1", Secret: "WIKIPEDIA_PASSWORD"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Secret with value",
+			strategy: Strategy{
+				Id:         "wikipedia-login",
+				PreActions: []TaskAction{{Id: "1", Type: WriteInput, Selector: "#wpPassword1", Value: "password", Secret: "WIKIPEDIA_PASSWORD"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Secret on a click",
+			strategy: Strategy{
+				Id:         "wikipedia-login",
+				PreActions: []TaskAction{{Id: "1", Type: Click, Value: "#login", Secret: "WIKIPEDIA_PASSWORD"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Session action without name",
+			strategy: Strategy{
+				Id:         "wikipedia-login",
+				PreActions: []TaskAction{{Id: "1", Type: SaveSession}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.strategy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Strategy.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
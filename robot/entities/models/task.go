@@ -1,11 +1,193 @@
 package models
 
+import (
+	"automator-go/robot/entities/validation"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// namePattern restricts the names of profiles and sessions.
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
 type Task struct {
-	Id          string       `json:"id"`
-	Title       string       `json:"name"`
-	Description string       `json:"description"`
-	Url         string       `json:"url"`
-	Country     string       `json:"country"`
-	WithProxy   bool         `json:"with_proxy"`
-	Actions     []TaskAction `json:"actions"`
+	Id          string         `json:"id"`
+	Title       string         `json:"name"`
+	Description string         `json:"description"`
+	Url         string         `json:"url"`
+	Country     string         `json:"country"`
+	WithProxy   bool           `json:"with_proxy"`
+	Driver      string         `json:"driver,omitempty"`
+	Browser     *BrowserConfig `json:"browser,omitempty"`
+	Profile     string         `json:"profile,omitempty"`
+	Session     *TaskSession   `json:"session,omitempty"`
+	Strategies  []string       `json:"strategies,omitempty"`
+	Network     []NetworkRule  `json:"network,omitempty"`
+	RecordHar   bool           `json:"record_har,omitempty"`
+	// HarFullCapture keeps the credential headers and the request bodies in
+	// the har, they are redacted otherwise.
+	HarFullCapture bool         `json:"har_full_capture,omitempty"`
+	Video          *VideoConfig `json:"video,omitempty"`
+	Actions        []TaskAction `json:"actions"`
+}
+
+// allActions returns every action the task may run: its own ones, the ones
+// of its session login task and the pre and post actions of its strategies.
+func (t *Task) allActions(strategies []*Strategy) []TaskAction {
+	actions := append([]TaskAction{}, t.Actions...)
+	if t.Session != nil && t.Session.LoginTask != nil {
+		actions = append(actions, t.Session.LoginTask.allActions(nil)...)
+	}
+	for _, strategy := range strategies {
+		actions = append(actions, strategy.PreActions...)
+		actions = append(actions, strategy.PostActions...)
+	}
+
+	return actions
+}
+
+// HasAction reports whether the task, its login task or its resolved
+// strategies have an action of the type.
+func (t *Task) HasAction(actionType Action, strategies []*Strategy) bool {
+	for _, action := range t.allActions(strategies) {
+		if action.Type == actionType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RunsScripts reports whether the task runs custom scripts in the browser,
+// with EvaluateScript or with the JS predicate of a wait.
+func (t *Task) RunsScripts(strategies []*Strategy) bool {
+	for _, action := range t.allActions(strategies) {
+		if action.Type == EvaluateScript {
+			return true
+		}
+		if action.WaitFor != nil && action.WaitFor.Strategy == WaitPredicate {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DriverName is the driver that runs the task, the browser unless set.
+func (t *Task) DriverName() string {
+	if t.Driver == "" {
+		return BrowserDriver
+	}
+
+	return t.Driver
+}
+
+// Validate checks the whole task definition without touching the browser, so
+// malformed tasks are rejected before they are run. All the problems found are
+// joined in the returned error.
+func (t *Task) Validate() error {
+	var errs []error
+
+	if strings.TrimSpace(t.Id) == "" {
+		errs = append(errs, errors.New("task id is required"))
+	}
+
+	if err := validation.ValidateUrl(t.Url); err != nil {
+		errs = append(errs, err)
+	}
+
+	if t.Profile != "" && !namePattern.MatchString(t.Profile) {
+		errs = append(errs, fmt.Errorf("invalid profile %q", t.Profile))
+	}
+
+	if t.Browser != nil {
+		if err := t.Browser.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("browser: %w", err))
+		}
+	}
+
+	if t.Session != nil {
+		if err := t.Session.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("session: %w", err))
+		}
+	}
+
+	for i, rule := range t.Network {
+		if err := rule.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("network rule %d: %w", i, err))
+		}
+	}
+
+	if t.Video != nil {
+		if err := t.Video.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("video: %w", err))
+		}
+	}
+
+	if len(t.Actions) == 0 {
+		errs = append(errs, errors.New("task must have at least one action"))
+	}
+
+	switch t.DriverName() {
+	case BrowserDriver:
+	case HttpDriver:
+		errs = append(errs, t.validateStatic()...)
+	default:
+		errs = append(errs, fmt.Errorf("invalid driver %q", t.Driver))
+	}
+
+	if len(t.Strategies) > 0 {
+		strategyIds := make(map[string]bool, len(t.Strategies))
+		for _, strategyId := range t.Strategies {
+			if !namePattern.MatchString(strategyId) {
+				errs = append(errs, fmt.Errorf("invalid strategy id %q", strategyId))
+			}
+			if strategyIds[strategyId] {
+				errs = append(errs, fmt.Errorf("duplicated strategy %q", strategyId))
+			}
+			strategyIds[strategyId] = true
+		}
+	}
+
+	errs = append(errs, validateActions("action", t.Actions, t.Session != nil)...)
+
+	// Secrets are resolved with the strategies, the tasks come from producers
+	// that may not read them.
+	for i, action := range t.Actions {
+		if action.Secret != "" {
+			errs = append(errs, fmt.Errorf("action %d (%s): secrets are only supported by strategies", i, action.Type.String()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateActions checks every action of the list and the rules that depend
+// on their order. Session actions without a name need a task session.
+func validateActions(label string, actions []TaskAction, hasSession bool) []error {
+	var errs []error
+
+	actionIds := make(map[string]bool, len(actions))
+	for i, action := range actions {
+		if action.Id != "" && actionIds[action.Id] {
+			errs = append(errs, fmt.Errorf("%s %d: duplicated id %q", label, i, action.Id))
+		}
+		actionIds[action.Id] = true
+
+		for _, err := range action.validate() {
+			errs = append(errs, fmt.Errorf("%s %d (%s): %w", label, i, action.Type.String(), err))
+		}
+
+		if (action.Type == SaveSession || action.Type == LoadSession) && action.Value == "" && !hasSession {
+			errs = append(errs, fmt.Errorf("%s %d (%s): requires a session name or a task session", label, i, action.Type.String()))
+		}
+
+		// The navigation to the page is already awaited before the first action runs.
+		if i == 0 && action.Type == WaitForNavigation {
+			errs = append(errs, fmt.Errorf("%s %d (%s): must follow an action that navigates", label, i, action.Type.String()))
+		}
+	}
+
+	return errs
 }
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+type TaskRunStatus string
+
+const (
+	TaskRunSucceeded TaskRunStatus = "succeeded"
+	TaskRunFailed    TaskRunStatus = "failed"
+)
+
+// TaskRun is a single execution of a task. The medias captured by the run
+// reference it, and so do the debugging files recorded while it ran. The
+// video shows how the task ran, to debug it or to confirm it ran. Regressed
+// flags the runs with a capture too far from the baseline of its action.
+type TaskRun struct {
+	Id         string        `json:"id"`
+	TaskId     string        `json:"task_id"`
+	Status     TaskRunStatus `json:"status"`
+	Error      string        `json:"error,omitempty"`
+	HarUrl     string        `json:"har_url,omitempty"`
+	VideoUrl   string        `json:"video_url,omitempty"`
+	Events     []RunEvent    `json:"events,omitempty"`
+	Failure    *RunFailure   `json:"failure,omitempty"`
+	Regressed  bool          `json:"regressed,omitempty"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+}
+
+// RunFailure references the diagnostics of the action that made the run
+// fail: the url of the page and where its screenshot and DOM were saved.
+type RunFailure struct {
+	ActionId      string `json:"action_id"`
+	Url           string `json:"url,omitempty"`
+	ScreenshotUrl string `json:"screenshot_url,omitempty"`
+	DomUrl        string `json:"dom_url,omitempty"`
+}
+
+type RunEventKind string
+
+const (
+	ConsoleEvent       RunEventKind = "console"
+	ExceptionEvent     RunEventKind = "exception"
+	RequestFailedEvent RunEventKind = "request_failed"
+)
+
+// RunEvent is something the page reported while the task ran: a console
+// message, an uncaught exception or a failed request. Level is the console
+// message type, or the status code of a failed request.
+type RunEvent struct {
+	Kind    RunEventKind `json:"kind"`
+	Level   string       `json:"level,omitempty"`
+	Message string       `json:"message"`
+	Url     string       `json:"url,omitempty"`
+	Time    time.Time    `json:"time"`
+}
@@ -0,0 +1,356 @@
+package models
+
+import "testing"
+
+func TestTask_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		task    Task
+		wantErr bool
+	}{
+		{
+			name: "Valid task",
+			task: Task{
+				Id:         "1",
+				Url:        "https://en.wikipedia.org/wiki/Special:Random",
+				Profile:    "wikipedia-login",
+				Strategies: []string{"wikipedia-login"},
+				Actions: []TaskAction{
+					{Id: "1", Type: ScrollDown, Value: "2"},
+					{Id: "2", Type: WriteInput, Selector: "input[name='search']", Value: "Tony Bennett"},
+					{Id: "3", Type: Click, Value: "#searchButton", Timeout: "30s"},
+					{Id: "4", Type: WaitForNavigation, Value: "Tony_Bennett"},
+					{Id: "5", Type: Capture, Value: "#firstHeading", WaitFor: &WaitCondition{Strategy: WaitVisible}},
+					{Id: "6", Type: PressKey, Selector: "input[name='search']", Value: "Control+a"},
+					{Id: "7", Type: Hover, Value: "text=Tony Bennett"},
+					{Id: "8", Type: DragAndDrop, Selector: "#item", Value: "#list"},
+					{Id: "9", Type: UploadFile, Selector: "input[type='file']", Value: "./media/a.png,./media/b.png"},
+					{Id: "10", Type: ScrollTo, Value: "#footer"},
+					{Id: "11", Type: ScrollUp, Value: "3"},
+					{Id: "12", Type: ScrollRight, Selector: ".carousel", Value: "2"},
+					{Id: "13", Type: ScrollToBottom, Value: "20"},
+					{Id: "14", Type: ScrollToBottom},
+					{Id: "15", Type: SaveSession, Value: "wikipedia"},
+					{Id: "16", Type: LoadSession, Value: "wikipedia"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid url",
+			task: Task{
+				Id:      "1",
+				Url:     "wikipedia",
+				Actions: []TaskAction{{Id: "1", Type: Capture, Value: "#firstHeading"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Without actions",
+			task:    Task{Id: "1", Url: "https://en.wikipedia.org"},
+			wantErr: true,
+		},
+		{
+			name: "Duplicated action id",
+			task: Task{
+				Id:  "1",
+				Url: "https://en.wikipedia.org",
+				Actions: []TaskAction{
+					{Id: "1", Type: Capture, Value: "#firstHeading"},
+					{Id: "1", Type: Capture, Value: "#firstHeading"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid scroll steps",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: ScrollDown, Value: "two"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid seconds",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: WaitSeconds, Value: "0"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid time",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: WriteTime, Selector: "#date", Value: "yesterday"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Input without selector",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: WriteInput, Value: "Tony Bennett"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid selector",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: Click, Value: "//a[@id='link'"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid timeout",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: Click, Value: "#link", Timeout: "soon"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Predicate without script",
+			task: Task{
+				Id:  "1",
+				Url: "https://en.wikipedia.org",
+				Actions: []TaskAction{
+					{Id: "1", Type: Click, Value: "#link", WaitFor: &WaitCondition{Strategy: WaitPredicate}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid max scrolls",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: ScrollToBottom, Value: "-1"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid scroll container",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: ScrollLeft, Selector: "div[", Value: "1"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid key",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: PressKey, Value: "Enter+Control"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid drop target",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: DragAndDrop, Selector: "#item", Value: ""}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Session action without session",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: SaveSession}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Session action with task session",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Session: &TaskSession{Name: "wikipedia"},
+				Actions: []TaskAction{{Id: "1", Type: SaveSession}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid session name",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: LoadSession, Value: "../sessions"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Duplicated strategy",
+			task: Task{
+				Id:         "1",
+				Url:        "https://en.wikipedia.org",
+				Strategies: []string{"wikipedia-login", "wikipedia-login"},
+				Actions:    []TaskAction{{Id: "1", Type: Capture, Value: "#firstHeading"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid profile",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Profile: "../wikipedia",
+				Actions: []TaskAction{{Id: "1", Type: Capture, Value: "#firstHeading"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid browser config",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Browser: &BrowserConfig{Viewport: &Viewport{Width: -1, Height: 720}},
+				Actions: []TaskAction{{Id: "1", Type: Capture, Value: "#firstHeading"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Http driver",
+			task: Task{
+				Id:     "1",
+				Url:    "https://en.wikipedia.org/wiki/Tony_Bennett",
+				Driver: HttpDriver,
+				Actions: []TaskAction{
+					{Id: "1", Type: ExtractText, Value: "#firstHeading"},
+					{Id: "2", Type: Navigate, Value: "text=Frank Sinatra"},
+					{Id: "3", Type: DownloadResource, Value: ".infobox img"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Http driver with browser action",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Driver:  HttpDriver,
+				Actions: []TaskAction{{Id: "1", Type: Capture, Value: "#firstHeading"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Http driver with profile",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Driver:  HttpDriver,
+				Profile: "wikipedia",
+				Actions: []TaskAction{{Id: "1", Type: ExtractText, Value: "#firstHeading"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Http driver recording har",
+			task: Task{
+				Id:        "1",
+				Url:       "https://en.wikipedia.org",
+				Driver:    HttpDriver,
+				RecordHar: true,
+				Actions:   []TaskAction{{Id: "1", Type: ExtractText, Value: "#firstHeading"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Http driver recording video",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Driver:  HttpDriver,
+				Video:   &VideoConfig{},
+				Actions: []TaskAction{{Id: "1", Type: ExtractText, Value: "#firstHeading"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid driver",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Driver:  "curl",
+				Actions: []TaskAction{{Id: "1", Type: ExtractText, Value: "#firstHeading"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Wait for navigation as first action",
+			task: Task{
+				Id:      "1",
+				Url:     "https://en.wikipedia.org",
+				Actions: []TaskAction{{Id: "1", Type: WaitForNavigation}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.task.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Task.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTask_ValidateStrategies(t *testing.T) {
+	staticStrategy := &Strategy{
+		Id:         "consent",
+		PreActions: []TaskAction{{Id: "1", Type: Navigate, Value: "text=Accept"}},
+	}
+	browserStrategy := &Strategy{
+		Id:          "login",
+		PreActions:  []TaskAction{{Id: "1", Type: WriteInput, Value: "user", Selector: "#username"}},
+		PostActions: []TaskAction{{Id: "2", Type: Click, Value: "#logout"}},
+	}
+
+	tests := []struct {
+		name       string
+		driver     string
+		strategies []*Strategy
+		wantErr    bool
+	}{
+		{
+			name:       "Browser driver with browser strategy",
+			driver:     BrowserDriver,
+			strategies: []*Strategy{browserStrategy},
+			wantErr:    false,
+		},
+		{
+			name:       "Http driver with static strategy",
+			driver:     HttpDriver,
+			strategies: []*Strategy{staticStrategy},
+			wantErr:    false,
+		},
+		{
+			name:       "Http driver with browser strategy",
+			driver:     HttpDriver,
+			strategies: []*Strategy{staticStrategy, browserStrategy},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{Id: "1", Driver: tt.driver}
+			if err := task.ValidateStrategies(tt.strategies); (err != nil) != tt.wantErr {
+				t.Errorf("Task.ValidateStrategies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
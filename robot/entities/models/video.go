@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Video formats the screencast of a task is encoded in. Gif plays anywhere,
+// mjpeg keeps the frames as the browser sent them, concatenated.
+const (
+	GifVideo   = "gif"
+	MjpegVideo = "mjpeg"
+)
+
+// maxVideoSize limits the width and height of the recorded frames.
+const maxVideoSize = 1920
+
+// VideoConfig records the screencast of the task. The frames are scaled down
+// to fit MaxWidth and MaxHeight, when set.
+type VideoConfig struct {
+	Format    string `json:"format,omitempty"`
+	MaxWidth  int    `json:"max_width,omitempty"`
+	MaxHeight int    `json:"max_height,omitempty"`
+}
+
+// FormatName is the format of the video, gif unless set.
+func (v *VideoConfig) FormatName() string {
+	if v.Format == "" {
+		return GifVideo
+	}
+
+	return v.Format
+}
+
+func (v *VideoConfig) Validate() error {
+	var errs []error
+
+	switch v.FormatName() {
+	case GifVideo, MjpegVideo:
+	default:
+		errs = append(errs, fmt.Errorf("invalid video format %q", v.Format))
+	}
+
+	if v.MaxWidth < 0 || v.MaxWidth > maxVideoSize {
+		errs = append(errs, fmt.Errorf("max width must be between 0 and %d, got %d", maxVideoSize, v.MaxWidth))
+	}
+	if v.MaxHeight < 0 || v.MaxHeight > maxVideoSize {
+		errs = append(errs, fmt.Errorf("max height must be between 0 and %d, got %d", maxVideoSize, v.MaxHeight))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,45 @@
+package models
+
+import "testing"
+
+func TestVideoConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  VideoConfig
+		wantErr bool
+	}{
+		{
+			name:    "Default config",
+			config:  VideoConfig{},
+			wantErr: false,
+		},
+		{
+			name:    "Scaled mjpeg",
+			config:  VideoConfig{Format: MjpegVideo, MaxWidth: 800, MaxHeight: 600},
+			wantErr: false,
+		},
+		{
+			name:    "Invalid format",
+			config:  VideoConfig{Format: "mp4"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative width",
+			config:  VideoConfig{MaxWidth: -1},
+			wantErr: true,
+		},
+		{
+			name:    "Height too big",
+			config:  VideoConfig{MaxHeight: 4096},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("VideoConfig.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type WaitStrategy uint8
+
+const (
+	WaitAttached WaitStrategy = iota
+	WaitVisible
+	WaitStable
+	WaitNetworkIdle
+	WaitPredicate
+)
+
+func (w *WaitStrategy) String() string {
+	return [...]string{
+		"Attached",
+		"Visible",
+		"Stable",
+		"NetworkIdle",
+		"Predicate",
+	}[*w]
+}
+
+func (w *WaitStrategy) FromString(s string) (WaitStrategy, error) {
+	switch s {
+	case "Attached":
+		return WaitAttached, nil
+	case "Visible":
+		return WaitVisible, nil
+	case "Stable":
+		return WaitStable, nil
+	case "NetworkIdle":
+		return WaitNetworkIdle, nil
+	case "Predicate":
+		return WaitPredicate, nil
+	default:
+		return WaitAttached, fmt.Errorf("invalid wait strategy %s", s)
+	}
+}
+
+func (w *WaitStrategy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.String())
+}
+
+func (w *WaitStrategy) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	strategy, err := w.FromString(s)
+	if err != nil {
+		return err
+	}
+
+	*w = strategy
+	return nil
+}
+
+// WaitCondition describes what an action must wait for before it runs.
+// Predicate is a JS function returning a boolean, only used by WaitPredicate;
+// when the action targets an element it is bound as `this`.
+type WaitCondition struct {
+	Strategy  WaitStrategy `json:"strategy"`
+	Predicate string       `json:"predicate,omitempty"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWaitStrategy_String(t *testing.T) {
+	tests := []struct {
+		name string
+		w    WaitStrategy
+	}{
+		{
+			name: "Attached",
+			w:    WaitAttached,
+		},
+		{
+			name: "Visible",
+			w:    WaitVisible,
+		},
+		{
+			name: "Stable",
+			w:    WaitStable,
+		},
+		{
+			name: "NetworkIdle",
+			w:    WaitNetworkIdle,
+		},
+		{
+			name: "Predicate",
+			w:    WaitPredicate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.w.String(); got != tt.name {
+				t.Errorf("WaitStrategy.String() = %v, want %v", got, tt.name)
+			}
+			if got, _ := tt.w.FromString(tt.name); got != tt.w {
+				t.Errorf("WaitStrategy.FromString() = %v, want %v", got, tt.w)
+			}
+		})
+	}
+}
+
+func TestWaitCondition_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     []byte
+		want      WaitStrategy
+		predicate string
+		wantErr   bool
+	}{
+		{
+			name:    "Visible",
+			value:   []byte(`{"strategy": "Visible"}`),
+			want:    WaitVisible,
+			wantErr: false,
+		},
+		{
+			name:      "Predicate",
+			value:     []byte(`{"strategy": "Predicate", "predicate": "() => window.ready"}`),
+			want:      WaitPredicate,
+			predicate: "() => window.ready",
+			wantErr:   false,
+		},
+		{
+			name:    "Invalid",
+			value:   []byte(`{"strategy": "Invalid"}`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w WaitCondition
+			err := json.Unmarshal(tt.value, &w)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WaitCondition.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (w.Strategy != tt.want || w.Predicate != tt.predicate) {
+				t.Errorf("WaitCondition.UnmarshalJSON() = %v, want %v", w, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,362 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// nthPattern matches the an+b microsyntax used by the :nth-* pseudo-classes.
+var nthPattern = regexp.MustCompile(`^(?i)(odd|even|[+-]?\d*n(\s*[+-]\s*\d+)?|[+-]?\d+)$`)
+
+// selectorListPseudoClasses receive a selector list as argument.
+var selectorListPseudoClasses = map[string]bool{
+	"not":   true,
+	"is":    true,
+	"where": true,
+	"has":   true,
+	"host":  true,
+}
+
+var nthPseudoClasses = map[string]bool{
+	"nth-child":        true,
+	"nth-last-child":   true,
+	"nth-of-type":      true,
+	"nth-last-of-type": true,
+}
+
+// cssParser checks the syntax of CSS selectors (Selectors Level 4). Pseudo-class
+// names are not checked against a known list, the browser is the one that
+// decides which ones it supports.
+type cssParser struct {
+	s string
+	i int
+}
+
+func ValidateCss(s string) error {
+	p := &cssParser{s: s}
+	if err := p.parseSelectorList(false); err != nil {
+		return fmt.Errorf("invalid css selector %q: %w", s, err)
+	}
+
+	if !p.eof() {
+		return fmt.Errorf("invalid css selector %q: unexpected %q at %d", s, p.peek(), p.i)
+	}
+
+	return nil
+}
+
+func (p *cssParser) eof() bool {
+	return p.i >= len(p.s)
+}
+
+func (p *cssParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+
+	return p.s[p.i]
+}
+
+func (p *cssParser) skipWhitespace() bool {
+	start := p.i
+	for !p.eof() && strings.IndexByte(" \t\n\r\f", p.peek()) >= 0 {
+		p.i++
+	}
+
+	return p.i > start
+}
+
+// parseSelectorList parses comma separated complex selectors. Relative lists
+// (used by :has) may start with a combinator.
+func (p *cssParser) parseSelectorList(relative bool) error {
+	for {
+		p.skipWhitespace()
+		if err := p.parseComplexSelector(relative); err != nil {
+			return err
+		}
+		p.skipWhitespace()
+
+		if p.peek() != ',' {
+			return nil
+		}
+		p.i++
+	}
+}
+
+func (p *cssParser) parseComplexSelector(relative bool) error {
+	if relative && strings.IndexByte(">+~", p.peek()) >= 0 {
+		p.i++
+		p.skipWhitespace()
+	}
+
+	if err := p.parseCompoundSelector(); err != nil {
+		return err
+	}
+
+	for {
+		hadWhitespace := p.skipWhitespace()
+		if p.eof() || p.peek() == ',' || p.peek() == ')' {
+			return nil
+		}
+
+		if strings.IndexByte(">+~", p.peek()) >= 0 {
+			p.i++
+			p.skipWhitespace()
+		} else if !hadWhitespace {
+			return fmt.Errorf("unexpected %q at %d", p.peek(), p.i)
+		}
+
+		if err := p.parseCompoundSelector(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *cssParser) parseCompoundSelector() error {
+	start := p.i
+
+	if p.peek() == '*' {
+		p.i++
+	} else if p.isIdentStart() {
+		if _, err := p.parseIdentifier(); err != nil {
+			return err
+		}
+	}
+
+	for !p.eof() {
+		var err error
+		switch p.peek() {
+		case '#':
+			p.i++
+			_, err = p.parseName()
+		case '.':
+			p.i++
+			_, err = p.parseIdentifier()
+		case '[':
+			err = p.parseAttribute()
+		case ':':
+			err = p.parsePseudo()
+		default:
+			if p.i == start {
+				return fmt.Errorf("expected selector at %d", p.i)
+			}
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if p.i == start {
+		return fmt.Errorf("expected selector at %d", p.i)
+	}
+
+	return nil
+}
+
+func (p *cssParser) parseAttribute() error {
+	p.i++
+	p.skipWhitespace()
+	if _, err := p.parseIdentifier(); err != nil {
+		return err
+	}
+	p.skipWhitespace()
+
+	if p.peek() == ']' {
+		p.i++
+		return nil
+	}
+
+	if strings.IndexByte("~|^$*", p.peek()) >= 0 {
+		p.i++
+	}
+	if p.peek() != '=' {
+		return fmt.Errorf("expected attribute matcher at %d", p.i)
+	}
+	p.i++
+	p.skipWhitespace()
+
+	var err error
+	if p.peek() == '"' || p.peek() == '\'' {
+		err = p.parseString()
+	} else {
+		_, err = p.parseIdentifier()
+	}
+	if err != nil {
+		return err
+	}
+	p.skipWhitespace()
+
+	// Case sensitivity modifiers.
+	if strings.IndexByte("iIsS", p.peek()) >= 0 {
+		p.i++
+		p.skipWhitespace()
+	}
+
+	if p.peek() != ']' {
+		return fmt.Errorf("expected ] at %d", p.i)
+	}
+	p.i++
+
+	return nil
+}
+
+func (p *cssParser) parsePseudo() error {
+	p.i++
+	element := false
+	if p.peek() == ':' {
+		element = true
+		p.i++
+	}
+
+	name, err := p.parseIdentifier()
+	if err != nil {
+		return err
+	}
+	name = strings.ToLower(name)
+
+	if p.peek() != '(' {
+		return nil
+	}
+	p.i++
+	p.skipWhitespace()
+
+	switch {
+	case !element && selectorListPseudoClasses[name]:
+		err = p.parseSelectorList(name == "has")
+	case !element && nthPseudoClasses[name]:
+		err = p.parseNth()
+	default:
+		err = p.skipArguments()
+	}
+	if err != nil {
+		return err
+	}
+
+	p.skipWhitespace()
+	if p.peek() != ')' {
+		return fmt.Errorf("expected ) at %d", p.i)
+	}
+	p.i++
+
+	return nil
+}
+
+func (p *cssParser) parseNth() error {
+	start := p.i
+	for !p.eof() && p.peek() != ')' && !strings.HasPrefix(p.s[p.i:], " of ") {
+		p.i++
+	}
+
+	argument := strings.TrimSpace(p.s[start:p.i])
+	if !nthPattern.MatchString(argument) {
+		return fmt.Errorf("invalid nth argument %q", argument)
+	}
+
+	if strings.HasPrefix(p.s[p.i:], " of ") {
+		p.i += len(" of ")
+		return p.parseSelectorList(false)
+	}
+
+	return nil
+}
+
+// skipArguments consumes the arguments of pseudo-classes with their own
+// syntax (:lang, :dir, ...) checking that they are balanced.
+func (p *cssParser) skipArguments() error {
+	depth := 0
+	for !p.eof() {
+		switch p.peek() {
+		case '"', '\'':
+			if err := p.parseString(); err != nil {
+				return err
+			}
+			continue
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return nil
+			}
+			depth--
+		}
+		p.i++
+	}
+
+	return fmt.Errorf("unclosed (")
+}
+
+func (p *cssParser) parseString() error {
+	quote := p.peek()
+	p.i++
+	for !p.eof() {
+		switch p.peek() {
+		case '\\':
+			p.i += 2
+			continue
+		case '\n':
+			return fmt.Errorf("unexpected newline in string at %d", p.i)
+		case quote:
+			p.i++
+			return nil
+		}
+		p.i++
+	}
+
+	return fmt.Errorf("unclosed string")
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= utf8.RuneSelf
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || c == '-' || (c >= '0' && c <= '9')
+}
+
+func (p *cssParser) isIdentStart() bool {
+	rest := p.s[p.i:]
+	if len(rest) > 0 && rest[0] == '-' {
+		rest = rest[1:]
+	}
+	if len(rest) == 0 {
+		return false
+	}
+
+	return isNameStart(rest[0]) || rest[0] == '-' || rest[0] == '\\'
+}
+
+func (p *cssParser) parseIdentifier() (string, error) {
+	if !p.isIdentStart() {
+		return "", fmt.Errorf("expected identifier at %d", p.i)
+	}
+
+	return p.parseName()
+}
+
+func (p *cssParser) parseName() (string, error) {
+	start := p.i
+	for !p.eof() {
+		c := p.peek()
+		if c == '\\' {
+			if p.i+1 >= len(p.s) || p.s[p.i+1] == '\n' {
+				return "", fmt.Errorf("invalid escape at %d", p.i)
+			}
+			p.i += 2
+			continue
+		}
+		if !isNameChar(c) {
+			break
+		}
+		p.i++
+	}
+
+	if p.i == start {
+		return "", fmt.Errorf("expected name at %d", p.i)
+	}
+
+	return p.s[start:p.i], nil
+}
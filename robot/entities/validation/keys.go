@@ -0,0 +1,81 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeySeparator joins the keys of a combination, like Control+Shift+a.
+const KeySeparator = "+"
+
+var ModifierKeys = map[string]bool{
+	"Control": true,
+	"Shift":   true,
+	"Alt":     true,
+	"Meta":    true,
+}
+
+var NamedKeys = map[string]bool{
+	"Enter":      true,
+	"Tab":        true,
+	"Escape":     true,
+	"Backspace":  true,
+	"Delete":     true,
+	"Insert":     true,
+	"Space":      true,
+	"ArrowUp":    true,
+	"ArrowDown":  true,
+	"ArrowLeft":  true,
+	"ArrowRight": true,
+	"Home":       true,
+	"End":        true,
+	"PageUp":     true,
+	"PageDown":   true,
+	"F1":         true,
+	"F2":         true,
+	"F3":         true,
+	"F4":         true,
+	"F5":         true,
+	"F6":         true,
+	"F7":         true,
+	"F8":         true,
+	"F9":         true,
+	"F10":        true,
+	"F11":        true,
+	"F12":        true,
+}
+
+func isCharacterKey(key string) bool {
+	if len(key) != 1 {
+		return false
+	}
+
+	c := key[0]
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// ValidateKeyCombination checks a key or a combination of modifiers ending
+// with a key, e.g. Enter, Shift+Tab or Control+a.
+func ValidateKeyCombination(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return fmt.Errorf("key is required")
+	}
+
+	keys := strings.Split(s, KeySeparator)
+	for i, key := range keys {
+		key = strings.TrimSpace(key)
+		last := i == len(keys)-1
+
+		if ModifierKeys[key] {
+			continue
+		}
+		if !last {
+			return fmt.Errorf("invalid key combination %q: %q is not a modifier", s, key)
+		}
+		if !NamedKeys[key] && !isCharacterKey(key) {
+			return fmt.Errorf("invalid key combination %q: unknown key %q", s, key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package validation
+
+import "testing"
+
+func TestValidateKeyCombination(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantErr bool
+	}{
+		{
+			name:    "Named key",
+			s:       "Enter",
+			wantErr: false,
+		},
+		{
+			name:    "Character key",
+			s:       "a",
+			wantErr: false,
+		},
+		{
+			name:    "Modifiers",
+			s:       "Control+Shift+Tab",
+			wantErr: false,
+		},
+		{
+			name:    "Modifier alone",
+			s:       "Shift",
+			wantErr: false,
+		},
+		{
+			name:    "Empty",
+			s:       "",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown key",
+			s:       "Control+Hyper",
+			wantErr: true,
+		},
+		{
+			name:    "Key before modifier",
+			s:       "a+Control",
+			wantErr: true,
+		},
+		{
+			name:    "Empty key",
+			s:       "Control+",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateKeyCombination(tt.s); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateKeyCombination() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,7 +1,271 @@
 package validation
 
-import "strings"
+import (
+	"fmt"
+	"github.com/antchfx/xpath"
+	"regexp"
+	"strings"
+)
 
+type SelectorEngine uint8
+
+const (
+	CssEngine SelectorEngine = iota
+	XpathEngine
+	TextEngine
+	RoleEngine
+	TestIdEngine
+)
+
+func (e SelectorEngine) String() string {
+	return [...]string{
+		"css",
+		"xpath",
+		"text",
+		"role",
+		"testid",
+	}[e]
+}
+
+var selectorPrefixes = map[string]SelectorEngine{
+	"css=":    CssEngine,
+	"xpath=":  XpathEngine,
+	"text=":   TextEngine,
+	"role=":   RoleEngine,
+	"testid=": TestIdEngine,
+}
+
+var rolePattern = regexp.MustCompile(`^[a-z]+$`)
+
+// implicitRoles maps ARIA roles to the native elements that have them without
+// a role attribute.
+var implicitRoles = map[string]string{
+	"button":     `button, input[type="button"], input[type="submit"], input[type="reset"]`,
+	"link":       `a[href], area[href]`,
+	"heading":    `h1, h2, h3, h4, h5, h6`,
+	"textbox":    `input:not([type]), input[type="text"], input[type="email"], input[type="tel"], input[type="url"], textarea`,
+	"searchbox":  `input[type="search"]`,
+	"checkbox":   `input[type="checkbox"]`,
+	"radio":      `input[type="radio"]`,
+	"combobox":   `select`,
+	"img":        `img:not([alt=""])`,
+	"list":       `ul, ol`,
+	"listitem":   `li`,
+	"navigation": `nav`,
+	"main":       `main`,
+	"form":       `form`,
+	"table":      `table`,
+}
+
+// ChainSeparator steps from the element matched by a selector into the next
+// one: the document of an iframe, the shadow root of a web component or the
+// element subtree otherwise. XPath selectors after the first one should be
+// relative (.//) to stay inside that scope.
+const ChainSeparator = ">>"
+
+type Selector struct {
+	Engine SelectorEngine
+	Value  string
+}
+
+// IsXpath is the heuristic used by legacy selectors without an engine prefix.
 func IsXpath(s string) bool {
 	return strings.Contains(s, "//") || strings.Contains(s, "@")
 }
+
+func ValidateXpath(s string) error {
+	if _, err := xpath.Compile(s); err != nil {
+		return fmt.Errorf("invalid xpath selector %q: %w", s, err)
+	}
+
+	return nil
+}
+
+// ParseSelector reads the engine prefix of the selector (css=, xpath=, text=,
+// role= or testid=) and validates its value. Selectors without prefix are
+// resolved with the IsXpath heuristic, falling back to the engine their
+// syntax is valid for.
+func ParseSelector(raw string) (Selector, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Selector{}, fmt.Errorf("selector is required")
+	}
+
+	for prefix, engine := range selectorPrefixes {
+		if !strings.HasPrefix(raw, prefix) {
+			continue
+		}
+
+		selector := Selector{Engine: engine, Value: strings.TrimSpace(strings.TrimPrefix(raw, prefix))}
+		if err := selector.validate(); err != nil {
+			return Selector{}, err
+		}
+
+		return selector, nil
+	}
+
+	return parseLegacySelector(raw)
+}
+
+func parseLegacySelector(raw string) (Selector, error) {
+	xpathErr := ValidateXpath(raw)
+	if IsXpath(raw) && xpathErr == nil {
+		return Selector{Engine: XpathEngine, Value: raw}, nil
+	}
+
+	cssErr := ValidateCss(raw)
+	if cssErr == nil {
+		return Selector{Engine: CssEngine, Value: raw}, nil
+	}
+
+	if xpathErr == nil {
+		return Selector{Engine: XpathEngine, Value: raw}, nil
+	}
+
+	if IsXpath(raw) {
+		return Selector{}, xpathErr
+	}
+
+	return Selector{}, cssErr
+}
+
+func (s Selector) validate() error {
+	if s.Value == "" {
+		return fmt.Errorf("%s selector value is required", s.Engine.String())
+	}
+
+	switch s.Engine {
+	case CssEngine:
+		return ValidateCss(s.Value)
+	case XpathEngine:
+		return ValidateXpath(s.Value)
+	case RoleEngine:
+		if !rolePattern.MatchString(s.Value) {
+			return fmt.Errorf("invalid role selector %q", s.Value)
+		}
+	}
+
+	return nil
+}
+
+// isPlainSelector tells the selectors whose value is plain text, the ones of
+// the text, role and testid engines.
+func isPlainSelector(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	for prefix, engine := range selectorPrefixes {
+		if strings.HasPrefix(raw, prefix) {
+			return engine == TextEngine || engine == RoleEngine || engine == TestIdEngine
+		}
+	}
+
+	return false
+}
+
+// Query returns the expression used to find the element in the page and
+// whether it is an XPath expression instead of a CSS selector.
+func (s Selector) Query() (string, bool) {
+	switch s.Engine {
+	case XpathEngine:
+		return s.Value, true
+	case TextEngine:
+		// The deepest element containing the text.
+		literal := xpathLiteral(s.Value)
+		return fmt.Sprintf(
+			".//*[contains(normalize-space(.), %s) and not(.//*[contains(normalize-space(.), %s)])]",
+			literal,
+			literal,
+		), true
+	case RoleEngine:
+		query := fmt.Sprintf(`[role="%s"]`, s.Value)
+		if implicit, ok := implicitRoles[s.Value]; ok {
+			query += ", " + implicit
+		}
+		return query, false
+	case TestIdEngine:
+		return fmt.Sprintf(`[data-testid="%s"]`, cssEscapeString(s.Value)), false
+	default:
+		return s.Value, false
+	}
+}
+
+// ParseSelectorChain splits the selector by ChainSeparator, ignoring the ones
+// inside the quotes, brackets or parentheses of css and xpath parts, and
+// parses every part of it. Text, role and testid values are plain text, an
+// apostrophe in them doesn't open a quote.
+func ParseSelectorChain(raw string) ([]Selector, error) {
+	parts := make([]string, 0, 1)
+	depth := 0
+	var quote rune
+	start := 0
+	plain := isPlainSelector(raw)
+	for i, r := range raw {
+		if quote != 0 {
+			if r == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case r == '>':
+			if depth == 0 && strings.HasPrefix(raw[i:], ChainSeparator) && i >= start {
+				parts = append(parts, raw[start:i])
+				start = i + len(ChainSeparator)
+				plain = isPlainSelector(raw[start:])
+			}
+		case plain:
+			continue
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(' || r == '[':
+			depth++
+		case r == ')' || r == ']':
+			depth--
+		}
+	}
+	parts = append(parts, raw[start:])
+
+	selectors := make([]Selector, 0, len(parts))
+	for _, part := range parts {
+		if len(parts) > 1 && strings.TrimSpace(part) == "" {
+			return nil, fmt.Errorf("invalid selector chain %q: empty selector", raw)
+		}
+
+		selector, err := ParseSelector(part)
+		if err != nil {
+			return nil, err
+		}
+
+		selectors = append(selectors, selector)
+	}
+
+	return selectors, nil
+}
+
+// ValidateSelector checks the selector syntax for its engine, including every
+// selector of a chain.
+func ValidateSelector(s string) error {
+	_, err := ParseSelectorChain(s)
+
+	return err
+}
+
+func xpathLiteral(s string) string {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	if !strings.Contains(s, `"`) {
+		return `"` + s + `"`
+	}
+
+	parts := strings.Split(s, "'")
+	for i, part := range parts {
+		parts[i] = "'" + part + "'"
+	}
+
+	return "concat(" + strings.Join(parts, `, "'", `) + ")"
+}
+
+func cssEscapeString(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
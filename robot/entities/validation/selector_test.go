@@ -28,3 +28,258 @@ func TestIsXpath(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSelector(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantErr bool
+	}{
+		{
+			name:    "Xpath",
+			s:       "//div[@id='test']",
+			wantErr: false,
+		},
+		{
+			name:    "Selector",
+			s:       "div#test > a[href^=\"https://\"]:nth-child(2)",
+			wantErr: false,
+		},
+		{
+			name:    "Empty",
+			s:       " ",
+			wantErr: true,
+		},
+		{
+			name:    "Unclosed bracket",
+			s:       "//div[@id='test'",
+			wantErr: true,
+		},
+		{
+			name:    "Unclosed quote",
+			s:       "a[href='test]",
+			wantErr: true,
+		},
+		{
+			name:    "Unexpected parenthesis",
+			s:       "li:nth-child(2))",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSelector(tt.s); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          string
+		wantEngine SelectorEngine
+		wantXpath  bool
+		wantErr    bool
+	}{
+		{
+			name:       "Legacy xpath",
+			s:          "//div[@id='test']",
+			wantEngine: XpathEngine,
+			wantXpath:  true,
+		},
+		{
+			name:       "Legacy css",
+			s:          "div#test",
+			wantEngine: CssEngine,
+		},
+		{
+			name:       "Legacy css with url attribute",
+			s:          `a[href^="https://"]`,
+			wantEngine: CssEngine,
+		},
+		{
+			name:       "Legacy css with email attribute",
+			s:          `[data-email="a@b"]`,
+			wantEngine: CssEngine,
+		},
+		{
+			name:       "Css prefix",
+			s:          "css=ul > li:nth-child(2n+1)",
+			wantEngine: CssEngine,
+		},
+		{
+			name:       "Xpath prefix",
+			s:          "xpath=//ul/li[last()]",
+			wantEngine: XpathEngine,
+			wantXpath:  true,
+		},
+		{
+			name:       "Text prefix",
+			s:          "text=Don't miss it",
+			wantEngine: TextEngine,
+			wantXpath:  true,
+		},
+		{
+			name:       "Role prefix",
+			s:          "role=button",
+			wantEngine: RoleEngine,
+		},
+		{
+			name:       "Test id prefix",
+			s:          "testid=submit",
+			wantEngine: TestIdEngine,
+		},
+		{
+			name:    "Invalid css prefix",
+			s:       "css=//div",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid xpath prefix",
+			s:       "xpath=//div[@id=",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid role",
+			s:       "role=Submit button",
+			wantErr: true,
+		},
+		{
+			name:    "Empty text",
+			s:       "text=",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSelector(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSelector() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Engine != tt.wantEngine {
+				t.Errorf("ParseSelector() engine = %v, want %v", got.Engine.String(), tt.wantEngine.String())
+			}
+			if query, isXpath := got.Query(); isXpath != tt.wantXpath || query == "" {
+				t.Errorf("Selector.Query() = %v, %v, want xpath %v", query, isXpath, tt.wantXpath)
+			}
+		})
+	}
+}
+
+func TestValidateCss(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantErr bool
+	}{
+		{name: "Type", s: "div", wantErr: false},
+		{name: "Universal", s: "*", wantErr: false},
+		{name: "Id and classes", s: "div#main.content.wide", wantErr: false},
+		{name: "Combinators", s: "ul > li + li ~ li a", wantErr: false},
+		{name: "Selector list", s: "h1, h2,h3", wantErr: false},
+		{name: "Attribute matchers", s: `a[href^="https://"][data-x~=y i][lang|='en']`, wantErr: false},
+		{name: "Pseudo classes", s: "li:first-child:not(.hidden):is(.a, .b)", wantErr: false},
+		{name: "Relative has", s: "section:has(> img)", wantErr: false},
+		{name: "Nth of", s: "li:nth-child(-n + 3 of .item)", wantErr: false},
+		{name: "Pseudo element", s: "p::first-line", wantErr: false},
+		{name: "Escaped identifier", s: `#foo\:bar`, wantErr: false},
+		{name: "Empty", s: "", wantErr: true},
+		{name: "Dangling combinator", s: "div >", wantErr: true},
+		{name: "Empty list item", s: "div,", wantErr: true},
+		{name: "Unclosed attribute", s: "a[href", wantErr: true},
+		{name: "Invalid matcher", s: "a[href!=x]", wantErr: true},
+		{name: "Invalid nth", s: "li:nth-child(first)", wantErr: true},
+		{name: "Unclosed string", s: `a[title="x]`, wantErr: true},
+		{name: "Xpath", s: "//div[@id='x']", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCss(tt.s); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCss() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseSelectorChain(t *testing.T) {
+	tests := []struct {
+		name        string
+		s           string
+		wantEngines []SelectorEngine
+		wantErr     bool
+	}{
+		{
+			name:        "Single selector",
+			s:           "div#test",
+			wantEngines: []SelectorEngine{CssEngine},
+		},
+		{
+			name:        "Iframe and shadow root",
+			s:           "iframe#payment >> css=payment-form >> testid=card-number",
+			wantEngines: []SelectorEngine{CssEngine, CssEngine, TestIdEngine},
+		},
+		{
+			name:        "Mixed engines",
+			s:           "xpath=//iframe[@title='video'] >> text=Play",
+			wantEngines: []SelectorEngine{XpathEngine, TextEngine},
+		},
+		{
+			name:        "Separator inside quotes",
+			s:           `a[title="a >> b"]`,
+			wantEngines: []SelectorEngine{CssEngine},
+		},
+		{
+			name:        "Apostrophe in text",
+			s:           "text=Don't >> css=button",
+			wantEngines: []SelectorEngine{TextEngine, CssEngine},
+		},
+		{
+			name:        "Parenthesis in text",
+			s:           "text=:) >> xpath=.//button[@title='a >> b']",
+			wantEngines: []SelectorEngine{TextEngine, XpathEngine},
+		},
+		{
+			name:    "Empty chain part",
+			s:       "iframe >> >> button",
+			wantErr: true,
+		},
+		{
+			name:    "Dangling separator",
+			s:       "iframe >>",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid chain part",
+			s:       "iframe >> css=div[",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSelectorChain(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSelectorChain() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != len(tt.wantEngines) {
+				t.Errorf("ParseSelectorChain() = %v, want %v selectors", got, len(tt.wantEngines))
+				return
+			}
+			for i, selector := range got {
+				if selector.Engine != tt.wantEngines[i] {
+					t.Errorf("ParseSelectorChain()[%d] engine = %v, want %v", i, selector.Engine.String(), tt.wantEngines[i].String())
+				}
+			}
+		})
+	}
+}
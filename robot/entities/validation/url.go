@@ -0,0 +1,23 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+)
+
+func ValidateUrl(s string) error {
+	parsedUrl, err := url.ParseRequestURI(s)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", s, err)
+	}
+
+	if parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https" {
+		return fmt.Errorf("invalid url %q: scheme must be http or https", s)
+	}
+
+	if parsedUrl.Host == "" {
+		return fmt.Errorf("invalid url %q: host is required", s)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package validation
+
+import "testing"
+
+func TestValidateUrl(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantErr bool
+	}{
+		{
+			name:    "Https",
+			s:       "https://en.wikipedia.org/wiki/Special:Random",
+			wantErr: false,
+		},
+		{
+			name:    "Http",
+			s:       "http://localhost:8080",
+			wantErr: false,
+		},
+		{
+			name:    "Empty",
+			s:       "",
+			wantErr: true,
+		},
+		{
+			name:    "Relative",
+			s:       "/wiki/Special:Random",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid scheme",
+			s:       "ftp://example.com",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateUrl(tt.s); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUrl() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
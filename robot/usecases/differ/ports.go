@@ -0,0 +1,13 @@
+package differ
+
+// ImageDiff highlights the pixels that changed between two images. Ratio is
+// the share of changed pixels and Image the png showing them.
+type ImageDiff struct {
+	Image []byte
+	Ratio float64
+}
+
+type ImageDiffer interface {
+	// Diff compares the capture with the baseline image, pixel by pixel.
+	Diff(baseline []byte, capture []byte) (*ImageDiff, error)
+}
@@ -1,5 +1,16 @@
 package hasher
 
+import (
+	"automator-go/robot/entities/models"
+	"io"
+)
+
 type ImageHasher interface {
-	Hash([]byte) (string, error)
+	// Hash fingerprints the captured image with every algorithm of
+	// models.HashAlgorithms.
+	Hash([]byte) ([]models.MediaHash, error)
+	// HashResource fingerprints a downloaded resource: one hash for still
+	// images, one per frame for animated images and one per keyframe for
+	// videos, all perception hashes. Resources of other types have no hashes.
+	HashResource(resource io.Reader) ([]models.MediaHash, error)
 }
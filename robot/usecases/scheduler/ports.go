@@ -0,0 +1,33 @@
+package scheduler
+
+import (
+	"automator-go/robot/entities/models"
+	"context"
+	"time"
+)
+
+type ScheduleRepository interface {
+	// GetDueSchedules returns the enabled schedules whose next run time is
+	// not after now.
+	GetDueSchedules(now time.Time, ctx context.Context) ([]*models.Schedule, error)
+	// SaveRuns saves the next and last run times of the schedule, without
+	// touching its definition, when its next run time is still previous. It
+	// returns false when another scheduler already changed it.
+	SaveRuns(schedule *models.Schedule, previous time.Time, ctx context.Context) (bool, error)
+}
+
+// TaskPublisher sends the task of a schedule to the robots, as if it was
+// published by any other producer of tasks.
+type TaskPublisher interface {
+	PublishTask(schedule *models.Schedule, runAt time.Time, ctx context.Context) error
+}
+
+// LeaderElector elects the one robot instance that publishes the scheduled
+// tasks, so they are not published twice.
+type LeaderElector interface {
+	// TryLead returns whether the instance leads, taking the lead when no
+	// other instance has it.
+	TryLead(ctx context.Context) (bool, error)
+	// Resign lets another instance take the lead.
+	Resign(ctx context.Context) error
+}
@@ -0,0 +1,112 @@
+package scheduler
+
+import (
+	"automator-go/robot/entities/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Scheduler struct {
+	scheduleRepo ScheduleRepository
+	publisher    TaskPublisher
+	leader       LeaderElector
+	tolerance    time.Duration
+}
+
+// NewScheduler takes the runs due for longer than tolerance as missed, the
+// schedulers were not running then.
+func NewScheduler(
+	scheduleRepo ScheduleRepository,
+	publisher TaskPublisher,
+	leader LeaderElector,
+	tolerance time.Duration,
+) *Scheduler {
+	return &Scheduler{
+		scheduleRepo: scheduleRepo,
+		publisher:    publisher,
+		leader:       leader,
+		tolerance:    tolerance,
+	}
+}
+
+// Tick publishes the tasks of the schedules due at now when the instance
+// leads. It returns the number of published tasks.
+func (s *Scheduler) Tick(now time.Time, ctx context.Context) (int, error) {
+	leading, err := s.leader.TryLead(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error electing leader: %w", err)
+	}
+	if !leading {
+		return 0, nil
+	}
+
+	schedules, err := s.scheduleRepo.GetDueSchedules(now, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	var errs []error
+	for _, schedule := range schedules {
+		n, err := s.run(schedule, now, ctx)
+		published += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error running schedule %s: %w", schedule.Id, err))
+		}
+	}
+
+	return published, errors.Join(errs...)
+}
+
+// run publishes the due runs of the schedule. The runs are claimed first,
+// saving the next run time only if no other scheduler advanced it, so they
+// are published once even when the lead changed during the tick. When
+// publishing fails, the failed run becomes the next one again, so it is
+// retried on the next tick.
+func (s *Scheduler) run(schedule *models.Schedule, now time.Time, ctx context.Context) (int, error) {
+	due, next, err := schedule.DueRuns(now, s.tolerance)
+	if err != nil {
+		return 0, err
+	}
+	if len(due) == 0 && next.Equal(schedule.NextRunAt) {
+		return 0, nil
+	}
+
+	previous := schedule.NextRunAt
+	lastRunAt := schedule.LastRunAt
+	schedule.NextRunAt = next
+	if len(due) > 0 {
+		lastDue := due[len(due)-1]
+		schedule.LastRunAt = &lastDue
+	}
+	claimed, err := s.scheduleRepo.SaveRuns(schedule, previous, ctx)
+	if err != nil || !claimed {
+		return 0, err
+	}
+
+	published := 0
+	for _, runAt := range due {
+		if err = s.publisher.PublishTask(schedule, runAt, ctx); err != nil {
+			publishErr := fmt.Errorf("error publishing task: %w", err)
+			schedule.NextRunAt = runAt
+			schedule.LastRunAt = lastRunAt
+			if _, err = s.scheduleRepo.SaveRuns(schedule, next, ctx); err != nil {
+				return published, errors.Join(publishErr, err)
+			}
+
+			return published, publishErr
+		}
+		publishedAt := runAt
+		lastRunAt = &publishedAt
+		published++
+	}
+
+	return published, nil
+}
+
+// Stop resigns the lead, another instance goes on publishing the tasks.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	return s.leader.Resign(ctx)
+}
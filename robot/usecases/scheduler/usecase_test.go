@@ -0,0 +1,202 @@
+package scheduler
+
+import (
+	"automator-go/robot/entities/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type MockScheduleRepository struct {
+	Schedules []*models.Schedule
+	Saved     []models.Schedule
+	// Previous are the next run times the saves were conditional on.
+	Previous []time.Time
+	// Taken tells the runs were already claimed by another scheduler.
+	Taken     bool
+	Error     error
+	SaveError error
+}
+
+func (m *MockScheduleRepository) GetDueSchedules(time.Time, context.Context) ([]*models.Schedule, error) {
+	return m.Schedules, m.Error
+}
+
+func (m *MockScheduleRepository) SaveRuns(schedule *models.Schedule, previous time.Time, _ context.Context) (bool, error) {
+	m.Saved = append(m.Saved, *schedule)
+	m.Previous = append(m.Previous, previous)
+	return !m.Taken, m.SaveError
+}
+
+type MockTaskPublisher struct {
+	Published []time.Time
+	FailAfter int
+	Error     error
+}
+
+func (m *MockTaskPublisher) PublishTask(_ *models.Schedule, runAt time.Time, _ context.Context) error {
+	if m.Error != nil && len(m.Published) >= m.FailAfter {
+		return m.Error
+	}
+	m.Published = append(m.Published, runAt)
+	return nil
+}
+
+type MockLeaderElector struct {
+	Leading  bool
+	Resigned bool
+	Error    error
+}
+
+func (m *MockLeaderElector) TryLead(context.Context) (bool, error) {
+	return m.Leading, m.Error
+}
+
+func (m *MockLeaderElector) Resign(context.Context) error {
+	m.Resigned = true
+	return m.Error
+}
+
+func TestScheduler_Tick(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2026, time.October, 19, hour, 0, 0, 0, time.UTC)
+	}
+	now := at(9)
+
+	tests := []struct {
+		name          string
+		schedule      models.Schedule
+		leader        *MockLeaderElector
+		publisher     *MockTaskPublisher
+		saveError     error
+		taken         bool
+		wantPublished int
+		wantNext      time.Time
+		wantLast      time.Time
+		wantSaved     bool
+		wantErr       bool
+	}{
+		{
+			name:      "Not leading",
+			schedule:  models.Schedule{Id: "news", Cron: "@hourly", NextRunAt: at(9)},
+			leader:    &MockLeaderElector{},
+			publisher: &MockTaskPublisher{},
+		},
+		{
+			name:      "Error electing leader",
+			schedule:  models.Schedule{Id: "news", Cron: "@hourly", NextRunAt: at(9)},
+			leader:    &MockLeaderElector{Error: errors.New("error")},
+			publisher: &MockTaskPublisher{},
+			wantErr:   true,
+		},
+		{
+			name:          "Due schedule",
+			schedule:      models.Schedule{Id: "news", Cron: "@hourly", NextRunAt: at(9)},
+			leader:        &MockLeaderElector{Leading: true},
+			publisher:     &MockTaskPublisher{},
+			wantPublished: 1,
+			wantNext:      at(10),
+			wantLast:      at(9),
+			wantSaved:     true,
+		},
+		{
+			name:          "Missed runs",
+			schedule:      models.Schedule{Id: "news", Cron: "@hourly", MissedRuns: models.MissedRunsRunAll, NextRunAt: at(7)},
+			leader:        &MockLeaderElector{Leading: true},
+			publisher:     &MockTaskPublisher{},
+			wantPublished: 3,
+			wantNext:      at(10),
+			wantLast:      at(9),
+			wantSaved:     true,
+		},
+		{
+			name:      "New schedule",
+			schedule:  models.Schedule{Id: "news", Cron: "@hourly"},
+			leader:    &MockLeaderElector{Leading: true},
+			publisher: &MockTaskPublisher{},
+			wantNext:  at(10),
+			wantSaved: true,
+		},
+		{
+			name:          "Error publishing retries the run",
+			schedule:      models.Schedule{Id: "news", Cron: "@hourly", MissedRuns: models.MissedRunsRunAll, NextRunAt: at(7)},
+			leader:        &MockLeaderElector{Leading: true},
+			publisher:     &MockTaskPublisher{FailAfter: 1, Error: errors.New("error")},
+			wantPublished: 1,
+			wantNext:      at(8),
+			wantLast:      at(7),
+			wantSaved:     true,
+			wantErr:       true,
+		},
+		{
+			name:      "Error saving runs publishes nothing",
+			schedule:  models.Schedule{Id: "news", Cron: "@hourly", NextRunAt: at(9)},
+			leader:    &MockLeaderElector{Leading: true},
+			publisher: &MockTaskPublisher{},
+			saveError: errors.New("error"),
+			wantNext:  at(10),
+			wantLast:  at(9),
+			wantSaved: true,
+			wantErr:   true,
+		},
+		{
+			name:      "Runs claimed by another scheduler",
+			schedule:  models.Schedule{Id: "news", Cron: "@hourly", NextRunAt: at(9)},
+			leader:    &MockLeaderElector{Leading: true},
+			publisher: &MockTaskPublisher{},
+			taken:     true,
+			wantNext:  at(10),
+			wantLast:  at(9),
+			wantSaved: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := tt.schedule
+			repo := &MockScheduleRepository{Schedules: []*models.Schedule{&schedule}, SaveError: tt.saveError, Taken: tt.taken}
+			scheduler := NewScheduler(repo, tt.publisher, tt.leader, time.Minute)
+
+			got, err := scheduler.Tick(now, context.TODO())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scheduler.Tick() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.wantPublished || len(tt.publisher.Published) != tt.wantPublished {
+				t.Errorf("Scheduler.Tick() = %d, published %v, want %d", got, tt.publisher.Published, tt.wantPublished)
+			}
+			if (len(repo.Saved) > 0) != tt.wantSaved {
+				t.Fatalf("ScheduleRepository.SaveRuns() saved %v, want saved %v", repo.Saved, tt.wantSaved)
+			}
+			if !tt.wantSaved {
+				return
+			}
+
+			if !repo.Previous[0].Equal(tt.schedule.NextRunAt) {
+				t.Errorf("ScheduleRepository.SaveRuns() previous = %v, want %v", repo.Previous[0], tt.schedule.NextRunAt)
+			}
+
+			saved := repo.Saved[len(repo.Saved)-1]
+			if !saved.NextRunAt.Equal(tt.wantNext) {
+				t.Errorf("Schedule.NextRunAt = %v, want %v", saved.NextRunAt, tt.wantNext)
+			}
+			if (saved.LastRunAt == nil) != tt.wantLast.IsZero() || (saved.LastRunAt != nil && !saved.LastRunAt.Equal(tt.wantLast)) {
+				t.Errorf("Schedule.LastRunAt = %v, want %v", saved.LastRunAt, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestScheduler_TickWithoutDueRun(t *testing.T) {
+	runAt := time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+	schedule := &models.Schedule{Id: "news", Cron: "@hourly", NextRunAt: runAt}
+	repo := &MockScheduleRepository{Schedules: []*models.Schedule{schedule}}
+	scheduler := NewScheduler(repo, &MockTaskPublisher{}, &MockLeaderElector{Leading: true}, time.Minute)
+
+	if _, err := scheduler.Tick(runAt.Add(-time.Second), context.TODO()); err != nil {
+		t.Fatalf("Scheduler.Tick() error = %v", err)
+	}
+	if len(repo.Saved) != 0 {
+		t.Errorf("ScheduleRepository.SaveRuns() saved %v, want nothing saved", repo.Saved)
+	}
+}
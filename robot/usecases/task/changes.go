@@ -0,0 +1,117 @@
+package task
+
+import (
+	"automator-go/robot/entities/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nlepage/go-cuid2"
+	"sort"
+	"time"
+)
+
+// ChangeDetector compares what each task action got in a run with what it
+// got in the previous one, keeping a log of the changes and publishing them.
+type ChangeDetector struct {
+	changeRepo ContentChangeRepository
+	publisher  ChangePublisher
+	logError   func(err error)
+}
+
+// NewChangeDetector receives a nil publisher to only log the changes. The
+// errors detecting the changes of a run don't fail it, they are given to
+// logError, which may be nil to drop them.
+func NewChangeDetector(
+	changeRepo ContentChangeRepository,
+	publisher ChangePublisher,
+	logError func(err error),
+) *ChangeDetector {
+	return &ChangeDetector{changeRepo: changeRepo, publisher: publisher, logError: logError}
+}
+
+// Detect returns the changes of the snapshots of a run. A snapshot becomes the
+// last content of its action once its change is saved and published, so a
+// change that failed to be published is detected again by the next run.
+func (d *ChangeDetector) Detect(snapshots []models.ContentSnapshot, ctx context.Context) ([]*models.ContentChange, error) {
+	var changes []*models.ContentChange
+	var errs []error
+	for i := range snapshots {
+		change, err := d.detect(&snapshots[i], ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error detecting changes of action %s: %w", snapshots[i].ActionId, err))
+			continue
+		}
+		if change != nil {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+func (d *ChangeDetector) detect(snapshot *models.ContentSnapshot, ctx context.Context) (*models.ContentChange, error) {
+	previous, err := d.changeRepo.GetLastContent(snapshot.TaskId, snapshot.ActionId, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil {
+		return nil, d.changeRepo.SaveLastContent(snapshot, ctx)
+	}
+
+	change, err := snapshot.ChangedFrom(previous)
+	if err != nil {
+		return nil, err
+	}
+	if change == nil {
+		return nil, d.changeRepo.SaveLastContent(snapshot, ctx)
+	}
+
+	change.Id, err = cuid2.CreateId()
+	if err != nil {
+		return nil, fmt.Errorf("error generating change id: %w", err)
+	}
+	change.DetectedAt = time.Now()
+	if err = d.changeRepo.SaveChange(change, ctx); err != nil {
+		return nil, err
+	}
+	if d.publisher != nil {
+		if err = d.publisher.PublishContentChanged(change, ctx); err != nil {
+			return nil, fmt.Errorf("error publishing change: %w", err)
+		}
+	}
+	if err = d.changeRepo.SaveLastContent(snapshot, ctx); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// textSnapshots returns the snapshots of the variables extracted by the task
+// actions, sorted by action. Values that are not text are compared by their
+// json.
+func textSnapshots(taskId string, runId string, variables map[string]interface{}) ([]models.ContentSnapshot, error) {
+	snapshots := make([]models.ContentSnapshot, 0, len(variables))
+	for actionId, variable := range variables {
+		text, ok := variable.(string)
+		if !ok {
+			encoded, err := json.Marshal(variable)
+			if err != nil {
+				return nil, fmt.Errorf("error encoding variable %s: %w", actionId, err)
+			}
+			text = string(encoded)
+		}
+		snapshots = append(snapshots, models.ContentSnapshot{
+			TaskId:   taskId,
+			ActionId: actionId,
+			RunId:    runId,
+			Kind:     models.TextContent,
+			Value:    text,
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].ActionId < snapshots[j].ActionId
+	})
+
+	return snapshots, nil
+}
@@ -0,0 +1,164 @@
+package task
+
+import (
+	models2 "automator-go/robot/entities/models"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+type MockContentChangeRepository struct {
+	Last    map[string]*models2.ContentSnapshot
+	Changes []*models2.ContentChange
+	Error   error
+}
+
+func (m *MockContentChangeRepository) GetLastContent(_ string, actionId string, _ context.Context) (*models2.ContentSnapshot, error) {
+	return m.Last[actionId], m.Error
+}
+
+func (m *MockContentChangeRepository) SaveLastContent(snapshot *models2.ContentSnapshot, _ context.Context) error {
+	if m.Last == nil {
+		m.Last = map[string]*models2.ContentSnapshot{}
+	}
+	m.Last[snapshot.ActionId] = snapshot
+	return m.Error
+}
+
+func (m *MockContentChangeRepository) SaveChange(change *models2.ContentChange, _ context.Context) error {
+	m.Changes = append(m.Changes, change)
+	return m.Error
+}
+
+func (m *MockContentChangeRepository) GetChanges(*ContentChangeFilter, context.Context) ([]*models2.ContentChange, error) {
+	return m.Changes, m.Error
+}
+
+type MockChangePublisher struct {
+	Published []*models2.ContentChange
+	Error     error
+}
+
+func (m *MockChangePublisher) PublishContentChanged(change *models2.ContentChange, _ context.Context) error {
+	m.Published = append(m.Published, change)
+	return m.Error
+}
+
+func TestChangeDetector_Detect(t *testing.T) {
+	snapshots := []models2.ContentSnapshot{
+		{TaskId: "1", ActionId: "capture", RunId: "run", Kind: models2.CaptureContent, Value: "8f28f6d738680f00"},
+		{TaskId: "1", ActionId: "title", RunId: "run", Kind: models2.TextContent, Value: "Frank Sinatra"},
+	}
+
+	tests := []struct {
+		name          string
+		last          map[string]*models2.ContentSnapshot
+		publisher     *MockChangePublisher
+		wantChanges   int
+		wantPublished int
+		wantErr       bool
+		// wantKept are the actions whose last content is not replaced.
+		wantKept []string
+	}{
+		{
+			name:      "First snapshots",
+			publisher: &MockChangePublisher{},
+		},
+		{
+			name: "Same contents",
+			last: map[string]*models2.ContentSnapshot{
+				"capture": {Kind: models2.CaptureContent, Value: "8f28f6d738680f00"},
+				"title":   {Kind: models2.TextContent, Value: "Frank Sinatra"},
+			},
+			publisher: &MockChangePublisher{},
+		},
+		{
+			name: "Changed contents",
+			last: map[string]*models2.ContentSnapshot{
+				"capture": {Kind: models2.CaptureContent, Value: "8f28f6d738680f07"},
+				"title":   {Kind: models2.TextContent, Value: "Tony Bennett"},
+			},
+			publisher:     &MockChangePublisher{},
+			wantChanges:   2,
+			wantPublished: 2,
+		},
+		{
+			name: "Changed contents without publisher",
+			last: map[string]*models2.ContentSnapshot{
+				"title": {Kind: models2.TextContent, Value: "Tony Bennett"},
+			},
+			wantChanges: 1,
+		},
+		{
+			name: "Error publishing",
+			last: map[string]*models2.ContentSnapshot{
+				"capture": {Kind: models2.CaptureContent, Value: "8f28f6d738680f07"},
+				"title":   {Kind: models2.TextContent, Value: "Frank Sinatra"},
+			},
+			publisher:     &MockChangePublisher{Error: errors.New("error")},
+			wantPublished: 1,
+			wantErr:       true,
+			wantKept:      []string{"capture"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeRepo := &MockContentChangeRepository{Last: tt.last}
+			var publisher ChangePublisher
+			if tt.publisher != nil {
+				publisher = tt.publisher
+			}
+			detector := NewChangeDetector(changeRepo, publisher, nil)
+			got, err := detector.Detect(snapshots, context.TODO())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ChangeDetector.Detect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.wantChanges {
+				t.Errorf("ChangeDetector.Detect() = %d changes, want %d", len(got), tt.wantChanges)
+			}
+			for _, change := range got {
+				if change.Id == "" || change.DetectedAt.IsZero() {
+					t.Errorf("ChangeDetector.Detect() = %+v, want an id and a detection time", change)
+				}
+			}
+			if tt.publisher != nil && len(tt.publisher.Published) != tt.wantPublished {
+				t.Errorf("ChangePublisher.PublishContentChanged() called %d times, want %d", len(tt.publisher.Published), tt.wantPublished)
+			}
+			for i := range snapshots {
+				kept := slices.Contains(tt.wantKept, snapshots[i].ActionId)
+				if saved := changeRepo.Last[snapshots[i].ActionId] == &snapshots[i]; saved == kept {
+					t.Errorf("ContentChangeRepository.SaveLastContent() saved %s = %v, want %v", snapshots[i].ActionId, !kept, kept)
+				}
+			}
+		})
+	}
+}
+
+func Test_textSnapshots(t *testing.T) {
+	variables := map[string]interface{}{
+		"title": "Frank Sinatra",
+		"count": 3,
+		"tags":  []string{"jazz", "swing"},
+	}
+
+	got, err := textSnapshots("1", "run", variables)
+	if err != nil {
+		t.Fatalf("textSnapshots() error = %v", err)
+	}
+
+	want := []models2.ContentSnapshot{
+		{TaskId: "1", ActionId: "count", RunId: "run", Kind: models2.TextContent, Value: "3"},
+		{TaskId: "1", ActionId: "tags", RunId: "run", Kind: models2.TextContent, Value: `["jazz","swing"]`},
+		{TaskId: "1", ActionId: "title", RunId: "run", Kind: models2.TextContent, Value: "Frank Sinatra"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("textSnapshots() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("textSnapshots()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
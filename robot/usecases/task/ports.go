@@ -3,24 +3,68 @@ package task
 import (
 	models2 "automator-go/robot/entities/models"
 	"context"
+	"io"
 	"time"
 )
 
+// RawMedia is a media captured by the action ActionId. Large resources are streamed to
+// a temporary ResourceFile instead of being kept in Resource, the storage
+// takes the file over when saving the media and the automator removes it
+// otherwise.
 type RawMedia struct {
-	Ext        string
-	Media      []byte
-	Screenshot []byte
-	Resource   []byte
-	Attributes map[string]interface{}
-	Height     float64
-	Width      float64
-	X          float64
-	Y          float64
+	ActionId     string
+	Ext          string
+	Media        []byte
+	Screenshot   []byte
+	Resource     []byte
+	ResourceFile string
+	Attributes   map[string]interface{}
+	Height       float64
+	Width        float64
+	X            float64
+	Y            float64
+	Url          string
+}
+
+// RunResult is what the automator got from running a task: the captured
+// medias and the variables extracted by the task actions, keyed by action id.
+// Har and Video are the network activity and the screencast of the run, when
+// the task records them, and Events what the page reported while it ran.
+// Failure is the snapshot of the page when an action failed.
+type RunResult struct {
+	Medias    []RawMedia
+	Variables map[string]interface{}
+	Har       []byte
+	Events    []models2.RunEvent
+	Failure   *FailureSnapshot
+	Video     *RunVideo
+}
+
+// RunVideo is the screencast of the run encoded in the format of its
+// extension.
+type RunVideo struct {
+	Ext  string
+	Data []byte
+}
+
+// FailureSnapshot is the state of the page when an action failed: its url, a
+// full page screenshot and the serialized DOM.
+type FailureSnapshot struct {
+	ActionId   string
 	Url        string
+	Screenshot []byte
+	Dom        []byte
 }
 
+// AutomatorTaskAdapter runs the task wrapped by the actions of its
+// strategies, already resolved in the task order. When the task fails, the
+// returned result, if any, keeps the har, video, events and failure snapshot
+// recorded until then.
 type AutomatorTaskAdapter interface {
-	Run(task *models2.Task) (*[]RawMedia, error)
+	Run(task *models2.Task, strategies []*models2.Strategy) (*RunResult, error)
+	// Release frees what the result of the task holds, like the temporary
+	// resource files the storage did not take over, once it is saved.
+	Release(task *models2.Task, result *RunResult)
 }
 
 type StorageMedia struct {
@@ -31,22 +75,39 @@ type StorageMedia struct {
 }
 
 type StorageMediaAdapter interface {
-	SaveMedia(hashName string, media *RawMedia) (StorageMedia, error)
+	// SaveMedia addresses the media file by contentHash, the sha256 of its
+	// bytes, captures of the same content share the file.
+	SaveMedia(contentHash string, media *RawMedia) (StorageMedia, error)
+	// SaveHar returns the url of the saved HAR file of the run.
+	SaveHar(runId string, har []byte) (string, error)
+	// SaveDiagnostic saves a file recorded to debug the run, returning its url.
+	// Name tells the files of the run apart and carries their extension.
+	SaveDiagnostic(runId string, name string, data []byte) (string, error)
+	// Open reads a file saved by the adapter from its url.
+	Open(url string) (io.ReadCloser, error)
 }
 
+// NewMediaInput is a media to save, its id is generated before, so the
+// diagnostics of the media can be named after it.
 type NewMediaInput struct {
-	Attributes    map[string]interface{}
-	Height        float64
-	Width         float64
-	X             float64
-	Y             float64
-	Url           string
-	PHash         string
-	Filename      string
-	MediaUrl      string
-	ScreenshotUrl string
-	ResourceUrl   string
-	TaskId        string
+	Id             string
+	Attributes     map[string]interface{}
+	Height         float64
+	Width          float64
+	X              float64
+	Y              float64
+	Url            string
+	PHash          string
+	ResourceHashes []models2.MediaHash
+	Hashes         []models2.MediaHash
+	Filename       string
+	MediaUrl       string
+	ScreenshotUrl  string
+	ResourceUrl    string
+	TaskId         string
+	ActionId       string
+	RunId          string
+	VisualDiff     *models2.VisualDiff
 }
 
 type Order string
@@ -64,13 +125,90 @@ type MediaFilter struct {
 	Limit     *int32
 }
 
+// SimilarMediaQuery searches the medias with a hash of the algorithm at most
+// MaxDistance bits away from Hash. Sha256 hashes only match exactly.
+type SimilarMediaQuery struct {
+	Hash        models2.MediaHash
+	MaxDistance int
+	Limit       int
+}
+
+// SimilarMedia is a media found by a SimilarMediaQuery, with the hamming
+// distance between its hash and the searched one.
+type SimilarMedia struct {
+	Media    *models2.Media
+	Distance int
+}
+
 type CapturedMediaRepository interface {
 	GetMedia(mediaId string, ctx context.Context) (*models2.Media, error)
+	// GetMediaByHash finds a media by its phash, a hash of its resource or the
+	// sha256 of its bytes.
 	GetMediaByHash(hash string, ctx context.Context) (*models2.Media, error)
 	GetMedias(filter *MediaFilter, ctx context.Context) ([]*models2.Media, error)
-	Save(input NewMediaInput, ctx context.Context) error
+	// GetSimilarMedias returns the medias matching the query, closest first.
+	GetSimilarMedias(query SimilarMediaQuery, ctx context.Context) ([]SimilarMedia, error)
+	// Save returns the id of the saved media.
+	Save(input NewMediaInput, ctx context.Context) (string, error)
+}
+
+// BaselineRepository keeps the baseline of each task action. GetBaseline
+// returns nil when the action has none. SaveBaseline replaces the baseline
+// when it is approved, otherwise it only saves the first one.
+type BaselineRepository interface {
+	GetBaseline(taskId string, actionId string, ctx context.Context) (*models2.Baseline, error)
+	SaveBaseline(baseline *models2.Baseline, ctx context.Context) error
+}
+
+// SessionStore keeps the browser sessions saved by the tasks. Get returns a
+// nil session when there is none saved with the name.
+type SessionStore interface {
+	Get(name string, ctx context.Context) (*models2.Session, error)
+	Save(session *models2.Session, ctx context.Context) error
+}
+
+// StrategyRepository keeps the strategies shared by the tasks. GetStrategy
+// returns a nil strategy when there is none with the id.
+type StrategyRepository interface {
+	GetStrategy(id string, ctx context.Context) (*models2.Strategy, error)
+	SaveStrategy(strategy *models2.Strategy, ctx context.Context) error
+}
+
+// SecretStore keeps the secrets written by the strategy actions apart from
+// the strategies.
+type SecretStore interface {
+	GetSecret(name string) (string, error)
+}
+
+// TaskRunRepository keeps the runs of the tasks. GetTaskRun returns a nil run
+// when there is none with the id.
+type TaskRunRepository interface {
+	GetTaskRun(id string, ctx context.Context) (*models2.TaskRun, error)
+	SaveTaskRun(run *models2.TaskRun, ctx context.Context) error
 }
 
 type ProcessorUseCase interface {
 	Process(task *models2.Task, ctx context.Context) error
 }
+
+type ContentChangeFilter struct {
+	TaskId   *string
+	ActionId *string
+	Since    *time.Time
+	Limit    *int32
+}
+
+// ContentChangeRepository keeps the last content of each task action and the
+// log of their changes. GetLastContent returns nil when the action has none.
+type ContentChangeRepository interface {
+	GetLastContent(taskId string, actionId string, ctx context.Context) (*models2.ContentSnapshot, error)
+	SaveLastContent(snapshot *models2.ContentSnapshot, ctx context.Context) error
+	SaveChange(change *models2.ContentChange, ctx context.Context) error
+	// GetChanges returns the changes matching the filter, the latest first.
+	GetChanges(filter *ContentChangeFilter, ctx context.Context) ([]*models2.ContentChange, error)
+}
+
+// ChangePublisher tells the subscribers of the robot that a content changed.
+type ChangePublisher interface {
+	PublishContentChanged(change *models2.ContentChange, ctx context.Context) error
+}
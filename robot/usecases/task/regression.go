@@ -0,0 +1,109 @@
+package task
+
+import (
+	"automator-go/robot/entities/models"
+	"automator-go/robot/usecases/differ"
+	"context"
+	"fmt"
+	"io"
+)
+
+// RegressionDetector compares the captures of each task action with the
+// baseline of the action, to tell when the appearance of an element changed.
+type RegressionDetector struct {
+	baselineRepo BaselineRepository
+	storage      StorageMediaAdapter
+	imageDiffer  differ.ImageDiffer
+	threshold    int
+}
+
+// NewRegressionDetector flags the captures whose perception hash is more than
+// threshold bits away from the baseline one.
+func NewRegressionDetector(
+	baselineRepo BaselineRepository,
+	storage StorageMediaAdapter,
+	imageDiffer differ.ImageDiffer,
+	threshold int,
+) *RegressionDetector {
+	return &RegressionDetector{
+		baselineRepo: baselineRepo,
+		storage:      storage,
+		imageDiffer:  imageDiffer,
+		threshold:    threshold,
+	}
+}
+
+// Compare returns the diff of the media with the baseline of its action, nil
+// when the action has no baseline yet. The diff image is saved with the
+// diagnostics of the run, named after the media. The diff is also returned
+// with the errors comparing the media once its baseline is found.
+func (d *RegressionDetector) Compare(
+	taskId string,
+	runId string,
+	mediaId string,
+	media *RawMedia,
+	hashes []models.MediaHash,
+	ctx context.Context,
+) (*models.VisualDiff, error) {
+	baseline, err := d.baselineRepo.GetBaseline(taskId, media.ActionId, ctx)
+	if err != nil || baseline == nil {
+		return nil, err
+	}
+
+	visualDiff := &models.VisualDiff{BaselineMediaId: baseline.MediaId}
+	if baseline.Sha256 != "" && baseline.Sha256 == models.FindHash(hashes, models.Sha256Hash) {
+		return visualDiff, nil
+	}
+
+	distance, err := models.HashDistance(baseline.PHash, models.FindHash(hashes, models.PerceptionHash))
+	if err != nil {
+		return visualDiff, fmt.Errorf("error comparing hashes: %w", err)
+	}
+
+	baselineImage, err := d.readBaseline(baseline)
+	if err != nil {
+		return visualDiff, err
+	}
+	imageDiff, err := d.imageDiffer.Diff(baselineImage, media.Media)
+	if err != nil {
+		return visualDiff, fmt.Errorf("error diffing capture: %w", err)
+	}
+	diffName := "visual_diff_" + media.ActionId + "_" + mediaId + ".png"
+	diffUrl, err := d.storage.SaveDiagnostic(runId, diffName, imageDiff.Image)
+	if err != nil {
+		return visualDiff, fmt.Errorf("error saving visual diff: %w", err)
+	}
+
+	visualDiff.Distance = distance
+	visualDiff.DiffRatio = imageDiff.Ratio
+	visualDiff.DiffUrl = diffUrl
+	visualDiff.Regressed = distance > d.threshold
+
+	return visualDiff, nil
+}
+
+func (d *RegressionDetector) readBaseline(baseline *models.Baseline) ([]byte, error) {
+	file, err := d.storage.Open(baseline.MediaUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error opening baseline: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading baseline: %w", err)
+	}
+
+	return data, nil
+}
+
+// SaveFirstBaseline makes the saved media the baseline of its action, unless
+// another run saved one meanwhile.
+func (d *RegressionDetector) SaveFirstBaseline(media *models.Media, ctx context.Context) error {
+	baseline, err := models.NewBaseline(media)
+	if err != nil {
+		return err
+	}
+
+	return d.baselineRepo.SaveBaseline(baseline, ctx)
+}
@@ -0,0 +1,113 @@
+package task
+
+import (
+	models2 "automator-go/robot/entities/models"
+	"automator-go/robot/usecases/differ"
+	"context"
+	"errors"
+	"testing"
+)
+
+type MockBaselineRepository struct {
+	Baseline *models2.Baseline
+	Saved    *models2.Baseline
+	Error    error
+}
+
+func (m *MockBaselineRepository) GetBaseline(string, string, context.Context) (*models2.Baseline, error) {
+	return m.Baseline, m.Error
+}
+
+func (m *MockBaselineRepository) SaveBaseline(baseline *models2.Baseline, _ context.Context) error {
+	m.Saved = baseline
+	return m.Error
+}
+
+type MockImageDiffer struct {
+	Ratio float64
+	Error error
+}
+
+func (m *MockImageDiffer) Diff([]byte, []byte) (*differ.ImageDiff, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+
+	return &differ.ImageDiff{Image: []byte("diff"), Ratio: m.Ratio}, nil
+}
+
+func TestRegressionDetector_Compare(t *testing.T) {
+	media := &RawMedia{ActionId: "capture", Media: []byte("capture")}
+	hashes := []models2.MediaHash{
+		{Algorithm: models2.PerceptionHash, Value: "8f28f6d738680f07"},
+		{Algorithm: models2.Sha256Hash, Value: "capture-sha"},
+	}
+
+	tests := []struct {
+		name        string
+		baseline    *models2.Baseline
+		imageDiffer *MockImageDiffer
+		want        *models2.VisualDiff
+		wantErr     bool
+	}{
+		{
+			name:        "Without baseline",
+			imageDiffer: &MockImageDiffer{},
+			want:        nil,
+		},
+		{
+			name:        "Same bytes",
+			baseline:    &models2.Baseline{MediaId: "baseline", PHash: "8f28f6d738680f07", Sha256: "capture-sha"},
+			imageDiffer: &MockImageDiffer{Error: errors.New("not diffed")},
+			want:        &models2.VisualDiff{BaselineMediaId: "baseline"},
+		},
+		{
+			name:        "Under the threshold",
+			baseline:    &models2.Baseline{MediaId: "baseline", PHash: "8f28f6d738680f00", Sha256: "baseline-sha"},
+			imageDiffer: &MockImageDiffer{Ratio: 0.01},
+			want: &models2.VisualDiff{
+				BaselineMediaId: "baseline",
+				Distance:        3,
+				DiffRatio:       0.01,
+				DiffUrl:         "./media/diagnostic_run_visual_diff_capture_media.png",
+			},
+		},
+		{
+			name:        "Over the threshold",
+			baseline:    &models2.Baseline{MediaId: "baseline", PHash: "70d70928c797f0f8", Sha256: "baseline-sha"},
+			imageDiffer: &MockImageDiffer{Ratio: 0.8},
+			want: &models2.VisualDiff{
+				BaselineMediaId: "baseline",
+				Distance:        64,
+				DiffRatio:       0.8,
+				DiffUrl:         "./media/diagnostic_run_visual_diff_capture_media.png",
+				Regressed:       true,
+			},
+		},
+		{
+			name:        "Error diffing",
+			baseline:    &models2.Baseline{MediaId: "baseline", PHash: "8f28f6d738680f00"},
+			imageDiffer: &MockImageDiffer{Error: errors.New("error")},
+			want:        &models2.VisualDiff{BaselineMediaId: "baseline"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := NewRegressionDetector(
+				&MockBaselineRepository{Baseline: tt.baseline},
+				&MockStorageMediaAdapter{},
+				tt.imageDiffer,
+				10,
+			)
+			got, err := detector.Compare("task", "run", "media", media, hashes, context.TODO())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RegressionDetector.Compare() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("RegressionDetector.Compare() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package task
+
+import (
+	"automator-go/robot/entities/models"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// StrategyRegistry resolves the strategies referenced by the tasks. The ones
+// registered in the robot take precedence over the ones in the repository.
+type StrategyRegistry struct {
+	mu           sync.RWMutex
+	strategies   map[string]*models.Strategy
+	strategyRepo StrategyRepository
+	secrets      SecretStore
+}
+
+// NewStrategyRegistry receives a nil strategyRepo to use only the registered
+// strategies, and nil secrets when there are none, strategies with secrets
+// fail to resolve then.
+func NewStrategyRegistry(strategyRepo StrategyRepository, secrets SecretStore) *StrategyRegistry {
+	return &StrategyRegistry{
+		strategies:   make(map[string]*models.Strategy),
+		strategyRepo: strategyRepo,
+		secrets:      secrets,
+	}
+}
+
+func (r *StrategyRegistry) getSecret(name string) (string, error) {
+	if r.secrets == nil {
+		return "", errors.New("secrets are not configured")
+	}
+
+	return r.secrets.GetSecret(name)
+}
+
+func (r *StrategyRegistry) Register(strategy *models.Strategy) error {
+	if err := strategy.Validate(); err != nil {
+		return fmt.Errorf("invalid strategy %s: %w", strategy.Id, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.strategies[strategy.Id] = strategy
+
+	return nil
+}
+
+func (r *StrategyRegistry) get(id string, ctx context.Context) (*models.Strategy, error) {
+	r.mu.RLock()
+	strategy, ok := r.strategies[id]
+	r.mu.RUnlock()
+	if ok {
+		return strategy, nil
+	}
+
+	if r.strategyRepo == nil {
+		return nil, nil
+	}
+
+	return r.strategyRepo.GetStrategy(id, ctx)
+}
+
+// Resolve returns the strategies of the task in its order, checking that all
+// of them exist and apply to the task url, with their secrets resolved.
+func (r *StrategyRegistry) Resolve(task *models.Task, ctx context.Context) ([]*models.Strategy, error) {
+	strategies := make([]*models.Strategy, 0, len(task.Strategies))
+	for _, id := range task.Strategies {
+		strategy, err := r.get(id, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting strategy %s: %w", id, err)
+		}
+		if strategy == nil {
+			return nil, fmt.Errorf("strategy %s not found", id)
+		}
+		if !strategy.AppliesTo(task.Url) {
+			return nil, fmt.Errorf("strategy %s does not apply to %s", id, task.Url)
+		}
+
+		strategy, err = strategy.WithSecrets(r.getSecret)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving strategy %s: %w", id, err)
+		}
+
+		strategies = append(strategies, strategy)
+	}
+
+	return strategies, nil
+}
@@ -0,0 +1,155 @@
+package task
+
+import (
+	models2 "automator-go/robot/entities/models"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStrategyRegistry_Resolve(t *testing.T) {
+	registered := &models2.Strategy{
+		Id:         "google-login",
+		Site:       "google.com",
+		PreActions: []models2.TaskAction{{Id: "1", Type: models2.Click, Value: "#login"}},
+	}
+	stored := &models2.Strategy{
+		Id:          "logout",
+		PostActions: []models2.TaskAction{{Id: "1", Type: models2.Click, Value: "#logout"}},
+	}
+
+	tests := []struct {
+		name         string
+		strategyRepo StrategyRepository
+		task         *models2.Task
+		want         []string
+		wantErr      bool
+	}{
+		{
+			name:         "registered and stored strategies",
+			strategyRepo: &MockStrategyRepository{Strategy: stored},
+			task:         &models2.Task{Url: "https://www.google.com", Strategies: []string{"google-login", "logout"}},
+			want:         []string{"google-login", "logout"},
+		},
+		{
+			name: "without repository",
+			task: &models2.Task{Url: "https://www.google.com", Strategies: []string{"google-login"}},
+			want: []string{"google-login"},
+		},
+		{
+			name:         "strategy not found",
+			strategyRepo: &MockStrategyRepository{},
+			task:         &models2.Task{Url: "https://www.google.com", Strategies: []string{"logout"}},
+			wantErr:      true,
+		},
+		{
+			name:         "error strategy repository",
+			strategyRepo: &MockStrategyRepository{Error: errors.New("error")},
+			task:         &models2.Task{Url: "https://www.google.com", Strategies: []string{"logout"}},
+			wantErr:      true,
+		},
+		{
+			name:    "strategy of another site",
+			task:    &models2.Task{Url: "https://en.wikipedia.org", Strategies: []string{"google-login"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewStrategyRegistry(tt.strategyRepo, nil)
+			if err := registry.Register(registered); err != nil {
+				t.Fatalf("StrategyRegistry.Register() error = %v", err)
+			}
+
+			strategies, err := registry.Resolve(tt.task, context.TODO())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("StrategyRegistry.Resolve() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(strategies) != len(tt.want) {
+				t.Fatalf("StrategyRegistry.Resolve() = %d strategies, want %d", len(strategies), len(tt.want))
+			}
+			for i, strategy := range strategies {
+				if strategy.Id != tt.want[i] {
+					t.Errorf("StrategyRegistry.Resolve()[%d] = %s, want %s", i, strategy.Id, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+type MockSecretStore struct {
+	Secrets map[string]string
+}
+
+func (m *MockSecretStore) GetSecret(name string) (string, error) {
+	secret, ok := m.Secrets[name]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+
+	return secret, nil
+}
+
+func TestStrategyRegistry_ResolveSecrets(t *testing.T) {
+	login := &models2.Strategy{
+		Id: "wikipedia-login",
+		PreActions: []models2.TaskAction{
+			{Id: "1", Type: models2.WriteInput, Selector: "#wpPassword1", Secret: "WIKIPEDIA_PASSWORD"},
+		},
+	}
+	task := &models2.Task{Url: "https://en.wikipedia.org", Strategies: []string{"wikipedia-login"}}
+
+	tests := []struct {
+		name      string
+		secrets   SecretStore
+		wantValue string
+		wantErr   bool
+	}{
+		{
+			name:      "secret resolved",
+			secrets:   &MockSecretStore{Secrets: map[string]string{"WIKIPEDIA_PASSWORD": "hunter2"}},
+			wantValue: "hunter2",
+		},
+		{
+			name:    "secret not found",
+			secrets: &MockSecretStore{},
+			wantErr: true,
+		},
+		{
+			name:    "without secrets",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewStrategyRegistry(nil, tt.secrets)
+			if err := registry.Register(login); err != nil {
+				t.Fatalf("StrategyRegistry.Register() error = %v", err)
+			}
+
+			strategies, err := registry.Resolve(task, context.TODO())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StrategyRegistry.Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := strategies[0].PreActions[0].Value; got != tt.wantValue {
+				t.Errorf("StrategyRegistry.Resolve() value = %q, want %q", got, tt.wantValue)
+			}
+			if login.PreActions[0].Value != "" {
+				t.Errorf("StrategyRegistry.Resolve() wrote the secret on the registered strategy")
+			}
+		})
+	}
+}
+
+func TestStrategyRegistry_Register(t *testing.T) {
+	registry := NewStrategyRegistry(nil, nil)
+	if err := registry.Register(&models2.Strategy{Id: "empty"}); err == nil {
+		t.Errorf("StrategyRegistry.Register() error = nil, want invalid strategy error")
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"automator-go/robot/entities/models"
 	"automator-go/robot/usecases/hasher"
 	"context"
-	"strings"
+	"errors"
+	"fmt"
+	"github.com/nlepage/go-cuid2"
+	"time"
 )
 
 type Processor struct {
@@ -12,63 +15,266 @@ type Processor struct {
 	capturedMediaRepo    CapturedMediaRepository
 	storageMediaAdapter  StorageMediaAdapter
 	imageHasher          hasher.ImageHasher
+	strategyRegistry     *StrategyRegistry
+	taskRunRepo          TaskRunRepository
+	regressionDetector   *RegressionDetector
+	changeDetector       *ChangeDetector
 }
 
+// NewProcessor receives a nil regressionDetector to not compare the captures
+// with their baselines, and a nil changeDetector to not detect the changes of
+// the contents of the task actions.
 func NewProcessor(
 	automatorTaskAdapter AutomatorTaskAdapter,
 	capturedMediaRepo CapturedMediaRepository,
 	storageMediaAdapter StorageMediaAdapter,
 	imageHasher hasher.ImageHasher,
+	strategyRegistry *StrategyRegistry,
+	taskRunRepo TaskRunRepository,
+	regressionDetector *RegressionDetector,
+	changeDetector *ChangeDetector,
 ) *Processor {
 	return &Processor{
 		automatorTaskAdapter: automatorTaskAdapter,
 		capturedMediaRepo:    capturedMediaRepo,
 		storageMediaAdapter:  storageMediaAdapter,
 		imageHasher:          imageHasher,
+		strategyRegistry:     strategyRegistry,
+		taskRunRepo:          taskRunRepo,
+		regressionDetector:   regressionDetector,
+		changeDetector:       changeDetector,
 	}
 }
 
+// Process runs the task and saves what it captured, recording the run even
+// when the task fails, with whatever the run recorded until then.
 func (p *Processor) Process(task *models.Task, ctx context.Context) error {
-	mediaResult, err := p.automatorTaskAdapter.Run(task)
+	strategies, err := p.strategyRegistry.Resolve(task, ctx)
 	if err != nil {
 		return err
 	}
 
-	if mediaResult == nil {
-		return nil
+	runId, err := cuid2.CreateId()
+	if err != nil {
+		return fmt.Errorf("error generating task run id: %w", err)
+	}
+	run := &models.TaskRun{Id: runId, TaskId: task.Id, StartedAt: time.Now()}
+
+	runResult, err := p.automatorTaskAdapter.Run(task, strategies)
+	run.FinishedAt = time.Now()
+	if runResult != nil {
+		defer p.automatorTaskAdapter.Release(task, runResult)
+		run.Events = runResult.Events
+		snapshots, saveErr := p.saveRunResult(task, run, runResult, ctx)
+		err = errors.Join(err, saveErr)
+		// Failed runs may have stopped on an error page, what they got is not
+		// compared with the previous runs.
+		if err == nil && p.changeDetector != nil {
+			p.detectChanges(task, run, runResult, snapshots, ctx)
+		}
+	}
+
+	run.Status = models.TaskRunSucceeded
+	if err != nil {
+		run.Status = models.TaskRunFailed
+		run.Error = err.Error()
+	}
+	if saveErr := p.taskRunRepo.SaveTaskRun(run, ctx); saveErr != nil {
+		err = errors.Join(err, saveErr)
 	}
 
-	for _, mediaResult := range *mediaResult {
-		hash, err := p.imageHasher.Hash(mediaResult.Media)
+	return err
+}
+
+// saveRunResult returns the snapshots of the saved captures.
+func (p *Processor) saveRunResult(
+	task *models.Task,
+	run *models.TaskRun,
+	runResult *RunResult,
+	ctx context.Context,
+) ([]models.ContentSnapshot, error) {
+	if runResult.Har != nil {
+		harUrl, err := p.storageMediaAdapter.SaveHar(run.Id, runResult.Har)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("error saving har: %w", err)
 		}
+		run.HarUrl = harUrl
+	}
 
-		hashWithoutKind := strings.Split(hash, ":")[1]
+	if runResult.Video != nil {
+		videoUrl, err := p.storageMediaAdapter.SaveDiagnostic(run.Id, "video."+runResult.Video.Ext, runResult.Video.Data)
+		if err != nil {
+			return nil, fmt.Errorf("error saving video: %w", err)
+		}
+		run.VideoUrl = videoUrl
+	}
 
-		storageMedia, err := p.storageMediaAdapter.SaveMedia(hashWithoutKind, &mediaResult)
+	if runResult.Failure != nil {
+		failure, err := p.saveFailureSnapshot(run, runResult.Failure)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("error saving failure snapshot: %w", err)
 		}
+		run.Failure = failure
+	}
 
-		err = p.capturedMediaRepo.Save(NewMediaInput{
-			Attributes:    mediaResult.Attributes,
-			Height:        mediaResult.Height,
-			Width:         mediaResult.Width,
-			X:             mediaResult.X,
-			Y:             mediaResult.Y,
-			Url:           mediaResult.Url,
-			PHash:         hash,
-			Filename:      storageMedia.Filename,
-			MediaUrl:      storageMedia.Media,
-			ScreenshotUrl: storageMedia.Screenshot,
-			ResourceUrl:   storageMedia.Resource,
-			TaskId:        task.Id,
+	var snapshots []models.ContentSnapshot
+	// The resource hashing errors don't stop saving the other medias.
+	var hashErrs []error
+	for _, mediaResult := range runResult.Medias {
+		hashes, err := p.imageHasher.Hash(mediaResult.Media)
+		if err != nil {
+			return nil, err
+		}
+
+		storageMedia, err := p.storageMediaAdapter.SaveMedia(models.FindHash(hashes, models.Sha256Hash), &mediaResult)
+		if err != nil {
+			return nil, err
+		}
+
+		// The media is saved without the resource hashes when they fail, its
+		// files are already in the storage.
+		var resourceHashes []models.MediaHash
+		var hashErr error
+		if storageMedia.Resource != "" {
+			resourceHashes, hashErr = p.hashResource(storageMedia.Resource)
+		}
+
+		mediaId, err := cuid2.CreateId()
+		if err != nil {
+			return nil, fmt.Errorf("error generating media id: %w", err)
+		}
+
+		// The media is saved with the error comparing it with its baseline,
+		// the other medias are still compared.
+		compared := p.regressionDetector != nil && mediaResult.ActionId != ""
+		var visualDiff *models.VisualDiff
+		if compared {
+			var compareErr error
+			visualDiff, compareErr = p.regressionDetector.Compare(task.Id, run.Id, mediaId, &mediaResult, hashes, ctx)
+			if compareErr != nil {
+				if visualDiff == nil {
+					visualDiff = &models.VisualDiff{}
+				}
+				visualDiff.Error = fmt.Sprintf("error comparing capture with its baseline: %v", compareErr)
+			}
+			if visualDiff != nil && visualDiff.Regressed {
+				run.Regressed = true
+			}
+		}
+
+		mediaId, err = p.capturedMediaRepo.Save(NewMediaInput{
+			Id:             mediaId,
+			Attributes:     mediaResult.Attributes,
+			Height:         mediaResult.Height,
+			Width:          mediaResult.Width,
+			X:              mediaResult.X,
+			Y:              mediaResult.Y,
+			Url:            mediaResult.Url,
+			PHash:          legacyPHash(hashes),
+			ResourceHashes: resourceHashes,
+			Hashes:         hashes,
+			Filename:       storageMedia.Filename,
+			MediaUrl:       storageMedia.Media,
+			ScreenshotUrl:  storageMedia.Screenshot,
+			ResourceUrl:    storageMedia.Resource,
+			TaskId:         task.Id,
+			ActionId:       mediaResult.ActionId,
+			RunId:          run.Id,
+			VisualDiff:     visualDiff,
 		}, ctx)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if compared && visualDiff == nil {
+			err = p.regressionDetector.SaveFirstBaseline(&models.Media{
+				Id:       mediaId,
+				TaskId:   task.Id,
+				ActionId: mediaResult.ActionId,
+				Hashes:   hashes,
+				MediaUrl: storageMedia.Media,
+			}, ctx)
+			if err != nil {
+				return nil, fmt.Errorf("error saving baseline: %w", err)
+			}
+		}
+		if hashErr != nil {
+			hashErrs = append(hashErrs, fmt.Errorf("error hashing resource of media %s: %w", mediaId, hashErr))
+		}
+
+		if mediaResult.ActionId != "" {
+			snapshots = append(snapshots, models.ContentSnapshot{
+				TaskId:   task.Id,
+				ActionId: mediaResult.ActionId,
+				RunId:    run.Id,
+				Kind:     models.CaptureContent,
+				Value:    models.FindHash(hashes, models.PerceptionHash),
+				MediaId:  mediaId,
+			})
+		}
+	}
+
+	return snapshots, errors.Join(hashErrs...)
+}
+
+// detectChanges compares the captures and the extracted texts of the run with
+// the ones of the previous run. Its errors are logged, they don't fail the run.
+func (p *Processor) detectChanges(
+	task *models.Task,
+	run *models.TaskRun,
+	runResult *RunResult,
+	captures []models.ContentSnapshot,
+	ctx context.Context,
+) {
+	texts, err := textSnapshots(task.Id, run.Id, runResult.Variables)
+	if err == nil {
+		_, err = p.changeDetector.Detect(append(captures, texts...), ctx)
+	}
+	if err != nil && p.changeDetector.logError != nil {
+		p.changeDetector.logError(fmt.Errorf("error detecting changes of run %s: %w", run.Id, err))
+	}
+}
+
+// legacyPHash formats the perception hash like goimagehash, with its kind, as
+// the phash of the medias has always been saved and searched by.
+func legacyPHash(hashes []models.MediaHash) string {
+	return "p:" + models.FindHash(hashes, models.PerceptionHash)
+}
+
+// hashResource hashes the resource saved in the storage, so the medias can be
+// found by the resource itself and not only by their element screenshot.
+func (p *Processor) hashResource(resourceUrl string) ([]models.MediaHash, error) {
+	resource, err := p.storageMediaAdapter.Open(resourceUrl)
+	if err != nil {
+		return nil, fmt.Errorf("error opening resource: %w", err)
+	}
+	defer resource.Close()
+
+	hashes, err := p.imageHasher.HashResource(resource)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing resource: %w", err)
+	}
+
+	return hashes, nil
+}
+
+func (p *Processor) saveFailureSnapshot(run *models.TaskRun, snapshot *FailureSnapshot) (*models.RunFailure, error) {
+	failure := &models.RunFailure{ActionId: snapshot.ActionId, Url: snapshot.Url}
+
+	if len(snapshot.Screenshot) > 0 {
+		screenshotUrl, err := p.storageMediaAdapter.SaveDiagnostic(run.Id, "failure_screenshot.png", snapshot.Screenshot)
+		if err != nil {
+			return nil, err
+		}
+		failure.ScreenshotUrl = screenshotUrl
+	}
+
+	if len(snapshot.Dom) > 0 {
+		domUrl, err := p.storageMediaAdapter.SaveDiagnostic(run.Id, "failure_dom.html", snapshot.Dom)
+		if err != nil {
+			return nil, err
 		}
+		failure.DomUrl = domUrl
 	}
 
-	return nil
+	return failure, nil
 }
@@ -5,36 +5,99 @@ import (
 	"automator-go/robot/usecases/hasher"
 	"context"
 	"errors"
+	"io"
+	"slices"
+	"strings"
 	"testing"
 )
 
 type MockAutomatorTaskAdapter struct {
-	Media *RawMedia
-	Error error
+	Media   *RawMedia
+	Medias  []RawMedia
+	Har     []byte
+	Events  []models2.RunEvent
+	Failure *FailureSnapshot
+	Video   *RunVideo
+	Error   error
+	// Returned and Released are the results returned and then released.
+	Returned *RunResult
+	Released *RunResult
+}
+
+func (m *MockAutomatorTaskAdapter) Run(*models2.Task, []*models2.Strategy) (*RunResult, error) {
+	result, err := m.result()
+	m.Returned = result
+
+	return result, err
+}
+
+func (m *MockAutomatorTaskAdapter) Release(_ *models2.Task, result *RunResult) {
+	m.Released = result
 }
 
-func (m *MockAutomatorTaskAdapter) Run(*models2.Task) (*[]RawMedia, error) {
+func (m *MockAutomatorTaskAdapter) result() (*RunResult, error) {
+	if m.Medias != nil {
+		return &RunResult{Medias: m.Medias}, m.Error
+	}
+	if m.Har != nil || m.Events != nil || m.Failure != nil || m.Video != nil {
+		result := &RunResult{Har: m.Har, Events: m.Events, Failure: m.Failure, Video: m.Video}
+		if m.Media != nil {
+			result.Medias = []RawMedia{*m.Media}
+		}
+		return result, m.Error
+	}
 	if m.Error != nil || m.Media == nil {
 		return nil, m.Error
 	}
 
-	return &[]RawMedia{*m.Media}, m.Error
+	return &RunResult{Medias: []RawMedia{*m.Media}}, m.Error
 }
 
 type MockStorageMediaAdapter struct {
+	Resource    string
+	ContentHash string
+	Error       error
+}
+
+func (m *MockStorageMediaAdapter) SaveMedia(contentHash string, _ *RawMedia) (StorageMedia, error) {
+	m.ContentHash = contentHash
+	return StorageMedia{Resource: m.Resource}, m.Error
+}
+
+func (m *MockStorageMediaAdapter) SaveHar(runId string, _ []byte) (string, error) {
+	return "./media/har_" + runId + ".har", m.Error
+}
+
+func (m *MockStorageMediaAdapter) SaveDiagnostic(runId string, name string, _ []byte) (string, error) {
+	return "./media/diagnostic_" + runId + "_" + name, m.Error
+}
+
+func (m *MockStorageMediaAdapter) Open(string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("")), m.Error
+}
+
+type MockTaskRunRepository struct {
+	Run   *models2.TaskRun
 	Error error
 }
 
-func (m *MockStorageMediaAdapter) SaveMedia(string, *RawMedia) (StorageMedia, error) {
-	return StorageMedia{}, m.Error
+func (m *MockTaskRunRepository) GetTaskRun(string, context.Context) (*models2.TaskRun, error) {
+	return m.Run, m.Error
+}
+
+func (m *MockTaskRunRepository) SaveTaskRun(run *models2.TaskRun, _ context.Context) error {
+	m.Run = run
+	return m.Error
 }
 
 type MockCapturedMediaRepository struct {
+	Saved []NewMediaInput
 	Error error
 }
 
-func (m *MockCapturedMediaRepository) Save(NewMediaInput, context.Context) error {
-	return m.Error
+func (m *MockCapturedMediaRepository) Save(input NewMediaInput, _ context.Context) (string, error) {
+	m.Saved = append(m.Saved, input)
+	return "media-id", m.Error
 }
 
 func (m *MockCapturedMediaRepository) GetMedia(string, context.Context) (*models2.Media, error) {
@@ -49,12 +112,44 @@ func (m *MockCapturedMediaRepository) GetMedias(*MediaFilter, context.Context) (
 	return []*models2.Media{}, m.Error
 }
 
+func (m *MockCapturedMediaRepository) GetSimilarMedias(SimilarMediaQuery, context.Context) ([]SimilarMedia, error) {
+	return []SimilarMedia{}, m.Error
+}
+
+type MockStrategyRepository struct {
+	Strategy *models2.Strategy
+	Error    error
+}
+
+func (m *MockStrategyRepository) GetStrategy(string, context.Context) (*models2.Strategy, error) {
+	return m.Strategy, m.Error
+}
+
+func (m *MockStrategyRepository) SaveStrategy(*models2.Strategy, context.Context) error {
+	return m.Error
+}
+
 type MockImageHasher struct {
-	Error error
+	Error         error
+	ResourceError error
 }
 
-func (m *MockImageHasher) Hash([]byte) (string, error) {
-	return "h:filename", m.Error
+func (m *MockImageHasher) Hash([]byte) ([]models2.MediaHash, error) {
+	return []models2.MediaHash{
+		{Algorithm: models2.PerceptionHash, Value: "8f28f6d738680f07"},
+		{Algorithm: models2.Sha256Hash, Value: "filename"},
+	}, m.Error
+}
+
+func (m *MockImageHasher) HashResource(io.Reader) ([]models2.MediaHash, error) {
+	if m.ResourceError != nil {
+		return nil, m.ResourceError
+	}
+
+	return []models2.MediaHash{
+		{Algorithm: models2.PerceptionHash, Value: "8f28f6d738680f00"},
+		{Algorithm: models2.PerceptionHash, Value: "8f28f6d738680f01"},
+	}, nil
 }
 
 func TestProcessor(t *testing.T) {
@@ -74,6 +169,12 @@ func TestProcessor(t *testing.T) {
 			},
 		},
 	}
+	taskWithStrategy := &models2.Task{
+		Id:         "2",
+		Url:        "https://google.com",
+		Strategies: []string{"google-login"},
+		Actions:    task.Actions,
+	}
 	media := &RawMedia{
 		Ext:        "png",
 		Media:      []byte("test"),
@@ -88,14 +189,27 @@ func TestProcessor(t *testing.T) {
 		Url:    "https://google.com",
 	}
 
+	actionMedia := &RawMedia{ActionId: "capture", Ext: "png", Media: []byte("test"), Screenshot: []byte("test")}
+
 	tests := []struct {
 		name                 string
 		automatorTaskAdapter AutomatorTaskAdapter
 		capturedMediaRepo    CapturedMediaRepository
 		storageMediaAdapter  StorageMediaAdapter
 		imageHasher          hasher.ImageHasher
+		strategyRepo         StrategyRepository
+		taskRunRepo          *MockTaskRunRepository
 		task                 *models2.Task
 		wantErr              bool
+		wantRun              *models2.TaskRun
+		regressionDetector   *RegressionDetector
+		wantResourceHashes   []models2.MediaHash
+		wantSaved            int
+		wantCompareErrors    bool
+		wantPHash            string
+		wantBaseline         *models2.Baseline
+		changeDetector       *ChangeDetector
+		wantChanges          int
 	}{
 		{
 			name: "success with media",
@@ -107,6 +221,7 @@ func TestProcessor(t *testing.T) {
 			imageHasher:         &MockImageHasher{},
 			task:                task,
 			wantErr:             false,
+			wantPHash:           "p:8f28f6d738680f07",
 		},
 		{
 			name:                 "success without media",
@@ -153,6 +268,18 @@ func TestProcessor(t *testing.T) {
 			task:        task,
 			wantErr:     true,
 		},
+		{
+			name: "error strategy not found",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Media: media,
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{},
+			imageHasher:         &MockImageHasher{},
+			strategyRepo:        &MockStrategyRepository{},
+			task:                taskWithStrategy,
+			wantErr:             true,
+		},
 		{
 			name: "error image hasher",
 			automatorTaskAdapter: &MockAutomatorTaskAdapter{
@@ -166,16 +293,324 @@ func TestProcessor(t *testing.T) {
 			task:    task,
 			wantErr: true,
 		},
+		{
+			name: "success hashing the resource",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Media: media,
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{Resource: "./media/resource_filename.gif"},
+			imageHasher:         &MockImageHasher{},
+			task:                task,
+			wantErr:             false,
+			wantResourceHashes: []models2.MediaHash{
+				{Algorithm: models2.PerceptionHash, Value: "8f28f6d738680f00"},
+				{Algorithm: models2.PerceptionHash, Value: "8f28f6d738680f01"},
+			},
+		},
+		{
+			name: "error hashing the resource saves the media",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Media: media,
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{Resource: "./media/resource_filename.mp4"},
+			imageHasher:         &MockImageHasher{ResourceError: errors.New("error")},
+			task:                task,
+			wantErr:             true,
+			wantResourceHashes:  []models2.MediaHash{},
+		},
+		{
+			name: "error hashing a resource saves the other medias",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Medias: []RawMedia{*media, *media, *media},
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{Resource: "./media/resource_filename.mp4"},
+			imageHasher:         &MockImageHasher{ResourceError: errors.New("error")},
+			task:                task,
+			wantErr:             true,
+			wantSaved:           3,
+		},
+		{
+			name: "success saving the first baseline",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Media: actionMedia,
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{},
+			imageHasher:         &MockImageHasher{},
+			regressionDetector:  NewRegressionDetector(&MockBaselineRepository{}, &MockStorageMediaAdapter{}, &MockImageDiffer{}, 10),
+			task:                task,
+			wantErr:             false,
+			wantBaseline:        &models2.Baseline{TaskId: "1", ActionId: "capture", MediaId: "media-id", PHash: "8f28f6d738680f07", Sha256: "filename"},
+		},
+		{
+			name: "success flagging the regressed run",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Media: actionMedia,
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{},
+			imageHasher:         &MockImageHasher{},
+			regressionDetector: NewRegressionDetector(
+				&MockBaselineRepository{Baseline: &models2.Baseline{MediaId: "baseline-id", PHash: "0000000000000000"}},
+				&MockStorageMediaAdapter{},
+				&MockImageDiffer{Ratio: 0.5},
+				10,
+			),
+			task:    task,
+			wantErr: false,
+			wantRun: &models2.TaskRun{TaskId: "1", Status: models2.TaskRunSucceeded, Regressed: true},
+		},
+		{
+			name: "error comparing a capture saves the medias with it",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Medias: []RawMedia{*actionMedia, *actionMedia},
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{},
+			imageHasher:         &MockImageHasher{},
+			regressionDetector: NewRegressionDetector(
+				&MockBaselineRepository{Baseline: &models2.Baseline{MediaId: "baseline-id", PHash: "0000000000000000"}},
+				&MockStorageMediaAdapter{},
+				&MockImageDiffer{Error: errors.New("error")},
+				10,
+			),
+			task:              task,
+			wantErr:           false,
+			wantSaved:         2,
+			wantCompareErrors: true,
+			wantRun:           &models2.TaskRun{TaskId: "1", Status: models2.TaskRunSucceeded},
+		},
+		{
+			name: "success detecting the changed capture",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Media: actionMedia,
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{},
+			imageHasher:         &MockImageHasher{},
+			changeDetector: NewChangeDetector(&MockContentChangeRepository{
+				Last: map[string]*models2.ContentSnapshot{
+					"capture": {Kind: models2.CaptureContent, Value: "0000000000000000"},
+				},
+			}, nil, nil),
+			task:        task,
+			wantErr:     false,
+			wantChanges: 1,
+		},
+		{
+			name: "success when publishing the change fails",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Media: actionMedia,
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{},
+			imageHasher:         &MockImageHasher{},
+			changeDetector: NewChangeDetector(&MockContentChangeRepository{
+				Last: map[string]*models2.ContentSnapshot{
+					"capture": {Kind: models2.CaptureContent, Value: "0000000000000000"},
+				},
+			}, &MockChangePublisher{Error: errors.New("error")}, nil),
+			task:        task,
+			wantErr:     false,
+			wantChanges: 1,
+			wantRun:     &models2.TaskRun{TaskId: "1", Status: models2.TaskRunSucceeded},
+		},
+		{
+			name: "success with har",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Media: media,
+				Har:   []byte("{}"),
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{},
+			imageHasher:         &MockImageHasher{},
+			task:                task,
+			wantErr:             false,
+			wantRun:             &models2.TaskRun{TaskId: "1", Status: models2.TaskRunSucceeded, HarUrl: "har"},
+		},
+		{
+			name: "success with video",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Media: media,
+				Video: &RunVideo{Ext: "gif", Data: []byte("GIF89a")},
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{},
+			imageHasher:         &MockImageHasher{},
+			task:                task,
+			wantErr:             false,
+			wantRun:             &models2.TaskRun{TaskId: "1", Status: models2.TaskRunSucceeded, VideoUrl: "video.gif"},
+		},
+		{
+			name: "failed run keeps its har and events",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Har:    []byte("{}"),
+				Events: []models2.RunEvent{{Kind: models2.ExceptionEvent, Message: "TypeError"}},
+				Error:  errors.New("error"),
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{},
+			imageHasher:         &MockImageHasher{},
+			task:                task,
+			wantErr:             true,
+			wantRun: &models2.TaskRun{
+				TaskId: "1",
+				Status: models2.TaskRunFailed,
+				HarUrl: "har",
+				Events: []models2.RunEvent{{Kind: models2.ExceptionEvent, Message: "TypeError"}},
+			},
+		},
+		{
+			name: "failed run keeps its failure snapshot",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Failure: &FailureSnapshot{
+					ActionId:   "1",
+					Url:        "https://google.com",
+					Screenshot: []byte("screenshot"),
+					Dom:        []byte("<html></html>"),
+				},
+				Error: errors.New("error"),
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{},
+			imageHasher:         &MockImageHasher{},
+			task:                task,
+			wantErr:             true,
+			wantRun: &models2.TaskRun{
+				TaskId: "1",
+				Status: models2.TaskRunFailed,
+				Failure: &models2.RunFailure{
+					ActionId:      "1",
+					Url:           "https://google.com",
+					ScreenshotUrl: "failure_screenshot.png",
+					DomUrl:        "failure_dom.html",
+				},
+			},
+		},
+		{
+			name: "error task run repo",
+			automatorTaskAdapter: &MockAutomatorTaskAdapter{
+				Media: media,
+			},
+			capturedMediaRepo:   &MockCapturedMediaRepository{},
+			storageMediaAdapter: &MockStorageMediaAdapter{},
+			imageHasher:         &MockImageHasher{},
+			taskRunRepo: &MockTaskRunRepository{
+				Error: errors.New("error"),
+			},
+			task:    task,
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			processor := NewProcessor(tt.automatorTaskAdapter, tt.capturedMediaRepo, tt.storageMediaAdapter, tt.imageHasher)
+			taskRunRepo := tt.taskRunRepo
+			if taskRunRepo == nil {
+				taskRunRepo = &MockTaskRunRepository{}
+			}
+			processor := NewProcessor(
+				tt.automatorTaskAdapter,
+				tt.capturedMediaRepo,
+				tt.storageMediaAdapter,
+				tt.imageHasher,
+				NewStrategyRegistry(tt.strategyRepo, nil),
+				taskRunRepo,
+				tt.regressionDetector,
+				tt.changeDetector,
+			)
 			err := processor.Process(tt.task, context.TODO())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Processor.Process() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+
+			if automator, ok := tt.automatorTaskAdapter.(*MockAutomatorTaskAdapter); ok && automator.Released != automator.Returned {
+				t.Errorf("AutomatorTaskAdapter.Release() released %p, want the result %p", automator.Released, automator.Returned)
+			}
+
+			if tt.wantSaved != 0 {
+				if saved := tt.capturedMediaRepo.(*MockCapturedMediaRepository).Saved; len(saved) != tt.wantSaved {
+					t.Errorf("Processor.Process() saved %d medias, want %d", len(saved), tt.wantSaved)
+				}
+			}
+
+			if tt.wantCompareErrors {
+				for _, saved := range tt.capturedMediaRepo.(*MockCapturedMediaRepository).Saved {
+					if saved.VisualDiff == nil || saved.VisualDiff.BaselineMediaId != "baseline-id" || saved.VisualDiff.Error == "" {
+						t.Errorf("NewMediaInput.VisualDiff = %+v, want the comparing error", saved.VisualDiff)
+					}
+				}
+			}
+
+			if tt.wantResourceHashes != nil {
+				saved := tt.capturedMediaRepo.(*MockCapturedMediaRepository).Saved
+				if len(saved) != 1 {
+					t.Fatalf("Processor.Process() saved %d medias, want 1", len(saved))
+				}
+				if !slices.Equal(saved[0].ResourceHashes, tt.wantResourceHashes) {
+					t.Errorf("NewMediaInput.ResourceHashes = %v, want %v", saved[0].ResourceHashes, tt.wantResourceHashes)
+				}
+			}
+
+			if tt.wantPHash != "" {
+				saved := tt.capturedMediaRepo.(*MockCapturedMediaRepository).Saved
+				if len(saved) != 1 {
+					t.Fatalf("Processor.Process() saved %d medias, want 1", len(saved))
+				}
+				if saved[0].PHash != tt.wantPHash || len(saved[0].Hashes) != 2 {
+					t.Errorf("NewMediaInput = %v %v, want phash %v and every hash", saved[0].PHash, saved[0].Hashes, tt.wantPHash)
+				}
+				if contentHash := tt.storageMediaAdapter.(*MockStorageMediaAdapter).ContentHash; contentHash != "filename" {
+					t.Errorf("StorageMediaAdapter.SaveMedia() content hash = %v, want the sha256", contentHash)
+				}
+			}
+
+			if tt.wantBaseline != nil {
+				got := tt.regressionDetector.baselineRepo.(*MockBaselineRepository).Saved
+				if got == nil || *got != *tt.wantBaseline {
+					t.Errorf("BaselineRepository.SaveBaseline() = %+v, want %+v", got, tt.wantBaseline)
+				}
+			}
+
+			if tt.changeDetector != nil {
+				changes := tt.changeDetector.changeRepo.(*MockContentChangeRepository).Changes
+				if len(changes) != tt.wantChanges {
+					t.Errorf("ContentChangeRepository.SaveChange() called %d times, want %d", len(changes), tt.wantChanges)
+				}
+			}
+
+			if tt.wantRun != nil {
+				run := taskRunRepo.Run
+				if run == nil {
+					t.Fatalf("Processor.Process() did not save the task run")
+				}
+				if run.TaskId != tt.wantRun.TaskId || run.Status != tt.wantRun.Status {
+					t.Errorf("TaskRun = %v, %v, want %v, %v", run.TaskId, run.Status, tt.wantRun.TaskId, tt.wantRun.Status)
+				}
+				if tt.wantRun.HarUrl != "" && (!strings.Contains(run.HarUrl, tt.wantRun.HarUrl) || !strings.Contains(run.HarUrl, run.Id)) {
+					t.Errorf("TaskRun.HarUrl = %v, want the har of run %v", run.HarUrl, run.Id)
+				}
+				if tt.wantRun.VideoUrl != "" && !strings.HasSuffix(run.VideoUrl, tt.wantRun.VideoUrl) {
+					t.Errorf("TaskRun.VideoUrl = %v, want %v", run.VideoUrl, tt.wantRun.VideoUrl)
+				}
+				if run.Regressed != tt.wantRun.Regressed {
+					t.Errorf("TaskRun.Regressed = %v, want %v", run.Regressed, tt.wantRun.Regressed)
+				}
+				if len(run.Events) != len(tt.wantRun.Events) {
+					t.Errorf("TaskRun.Events = %v, want %v", run.Events, tt.wantRun.Events)
+				}
+				if want := tt.wantRun.Failure; want != nil {
+					got := run.Failure
+					if got == nil || got.ActionId != want.ActionId || got.Url != want.Url ||
+						!strings.HasSuffix(got.ScreenshotUrl, want.ScreenshotUrl) || !strings.HasSuffix(got.DomUrl, want.DomUrl) {
+						t.Errorf("TaskRun.Failure = %+v, want %+v", got, want)
+					}
+				}
+			}
 		})
 	}
 }
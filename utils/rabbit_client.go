@@ -115,3 +115,62 @@ func StartClient(log *otelzap.LoggerWithCtx, consumerName string) (*Consumer, er
 
 	return c, nil
 }
+
+// Publisher is a connection publishing on the exchange of the robot, apart
+// from the one consuming the tasks.
+type Publisher struct {
+	Conn     *amqp.Connection
+	Channel  *amqp.Channel
+	Exchange string
+	log      *otelzap.LoggerWithCtx
+}
+
+func (p *Publisher) Shutdown() error {
+	if err := p.Conn.Close(); err != nil {
+		return fmt.Errorf("AMQP connection close error: %s", err)
+	}
+	p.log.Debug("AMQP publisher shutdown OK")
+
+	return nil
+}
+
+func StartPublisher(log *otelzap.LoggerWithCtx, connectionName string) (*Publisher, error) {
+	uri := os.Getenv("RABBITMQ_URI")
+	exchange := os.Getenv("RABBITMQ_EXCHANGE")
+	exchangeType := os.Getenv("RABBITMQ_EXCHANGE_TYPE")
+	if uri == "" || exchange == "" || exchangeType == "" {
+		return nil, fmt.Errorf("environment variables for rabbit not set")
+	}
+
+	p := &Publisher{Exchange: exchange, log: log}
+	var err error
+
+	config := amqp.Config{Properties: amqp.NewConnectionProperties()}
+	config.Properties.SetClientConnectionName(connectionName)
+
+	log.Debug("dialing rabbitmq", zap.String("uri", uri))
+	p.Conn, err = amqp.DialConfig(uri, config)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %s", err)
+	}
+
+	p.Channel, err = p.Conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("channel: %s", err)
+	}
+
+	log.Debug("got Channel, declaring Exchange", zap.String("exchange", exchange))
+	if err = p.Channel.ExchangeDeclare(
+		exchange,
+		exchangeType,
+		true,
+		false,
+		false,
+		false,
+		nil,
+	); err != nil {
+		return nil, fmt.Errorf("exchange declare: %s", err)
+	}
+
+	return p, nil
+}